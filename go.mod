@@ -1,3 +1,10 @@
 module github.com/janniks/stacks-go
 
 go 1.24.0
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.1
+	golang.org/x/crypto v0.36.0
+)
+
+require golang.org/x/text v0.23.0
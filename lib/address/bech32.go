@@ -0,0 +1,261 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bech32 alphabet used for encoding and decoding
+const bech32Chars = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32Encoding is the checksum variant of a bech32 string
+type Bech32Encoding int
+
+const (
+	// Bech32 is the original checksum from BIP-173, used for segwit v0
+	Bech32 Bech32Encoding = iota + 1
+	// Bech32m is the checksum from BIP-350, used for segwit v1 and later
+	Bech32m
+)
+
+// Checksum constants the polymod of each variant must equal
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// Bech32MaxLength is the maximum length of a bech32 string
+const Bech32MaxLength = 90
+
+// Human-readable parts of segwit addresses
+const (
+	Bech32HRPMainnet = "bc"
+	Bech32HRPTestnet = "tb"
+	Bech32HRPRegtest = "bcrt"
+)
+
+// Pre-computed bech32 digit values
+var bech32Digits [128]int
+
+func init() {
+	// Initialize bech32Digits lookup table
+	for i := 0; i < len(bech32Digits); i++ {
+		bech32Digits[i] = -1
+	}
+	for i := 0; i < len(bech32Chars); i++ {
+		bech32Digits[bech32Chars[i]] = i
+	}
+}
+
+// bech32Polymod computes the BCH checksum over 5-bit values
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for checksumming
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32ChecksumConst(encoding Bech32Encoding) uint32 {
+	if encoding == Bech32m {
+		return bech32mConst
+	}
+	return bech32Const
+}
+
+// EncodeBech32 encodes 5-bit data under the human-readable part with the
+// checksum of the given encoding
+func EncodeBech32(hrp string, data []byte, encoding Bech32Encoding) (string, error) {
+	if encoding != Bech32 && encoding != Bech32m {
+		return "", fmt.Errorf("invalid bech32 encoding: %d", encoding)
+	}
+	if len(hrp) == 0 {
+		return "", fmt.Errorf("empty bech32 human-readable part")
+	}
+	if len(hrp)+1+len(data)+6 > Bech32MaxLength {
+		return "", fmt.Errorf("bech32 string too long: %d characters", len(hrp)+1+len(data)+6)
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", fmt.Errorf("invalid bech32 human-readable character: %q", hrp[i])
+		}
+	}
+	if strings.ToLower(hrp) != hrp && strings.ToUpper(hrp) != hrp {
+		return "", fmt.Errorf("mixed case bech32 human-readable part")
+	}
+	hrp = strings.ToLower(hrp)
+
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ bech32ChecksumConst(encoding)
+
+	var result strings.Builder
+	result.WriteString(hrp)
+	result.WriteByte('1')
+	for _, d := range data {
+		if d >= 32 {
+			return "", fmt.Errorf("invalid bech32 data value: %d", d)
+		}
+		result.WriteByte(bech32Chars[d])
+	}
+	for i := 0; i < 6; i++ {
+		result.WriteByte(bech32Chars[(polymod>>(5*(5-i)))&31])
+	}
+	return result.String(), nil
+}
+
+// DecodeBech32 decodes a bech32 or bech32m string into its lowercase
+// human-readable part and 5-bit data, without the checksum
+func DecodeBech32(input string) (string, []byte, Bech32Encoding, error) {
+	if len(input) > Bech32MaxLength {
+		return "", nil, 0, fmt.Errorf("bech32 string too long: %d characters", len(input))
+	}
+	if strings.ToLower(input) != input && strings.ToUpper(input) != input {
+		return "", nil, 0, fmt.Errorf("mixed case bech32 string")
+	}
+	for i := 0; i < len(input); i++ {
+		if input[i] < 33 || input[i] > 126 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 character: %q", input[i])
+		}
+	}
+	input = strings.ToLower(input)
+
+	sep := strings.LastIndexByte(input, '1')
+	if sep < 1 {
+		return "", nil, 0, fmt.Errorf("missing bech32 human-readable part")
+	}
+	if len(input)-sep-1 < 6 {
+		return "", nil, 0, fmt.Errorf("bech32 checksum too short")
+	}
+
+	hrp := input[:sep]
+	data := make([]byte, 0, len(input)-sep-1)
+	for i := sep + 1; i < len(input); i++ {
+		digit := bech32Digits[input[i]]
+		if digit == -1 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 character: %c", input[i])
+		}
+		data = append(data, byte(digit))
+	}
+
+	var encoding Bech32Encoding
+	switch bech32Polymod(append(bech32HRPExpand(hrp), data...)) {
+	case bech32Const:
+		encoding = Bech32
+	case bech32mConst:
+		encoding = Bech32m
+	default:
+		return "", nil, 0, fmt.Errorf("checksum mismatch")
+	}
+	return hrp, data[:len(data)-6], encoding, nil
+}
+
+// ConvertBits regroups data from fromBits-bit values into toBits-bit values.
+// With pad, the last group is padded with zeros; without it, leftover bits
+// must be zero padding of less than fromBits bits.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid %d-bit value: %d", fromBits, value)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return result, nil
+}
+
+// EncodeSegwitAddress encodes a witness program as a segwit address, with
+// bech32 for version 0 and bech32m for later versions
+func EncodeSegwitAddress(hrp string, witnessVersion byte, program []byte) (string, error) {
+	if err := validateWitnessProgram(witnessVersion, program); err != nil {
+		return "", err
+	}
+	data, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	encoding := Bech32m
+	if witnessVersion == 0 {
+		encoding = Bech32
+	}
+	return EncodeBech32(hrp, append([]byte{witnessVersion}, data...), encoding)
+}
+
+// DecodeSegwitAddress decodes a segwit address into its human-readable part,
+// witness version and witness program, checking the program length and that
+// the checksum variant matches the witness version
+func DecodeSegwitAddress(addr string) (string, byte, []byte, error) {
+	hrp, data, encoding, err := DecodeBech32(addr)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(data) < 1 {
+		return "", 0, nil, fmt.Errorf("missing witness version")
+	}
+
+	witnessVersion := data[0]
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("invalid witness program: %w", err)
+	}
+	if err := validateWitnessProgram(witnessVersion, program); err != nil {
+		return "", 0, nil, err
+	}
+	if witnessVersion == 0 && encoding != Bech32 {
+		return "", 0, nil, fmt.Errorf("witness version 0 requires a bech32 checksum")
+	}
+	if witnessVersion != 0 && encoding != Bech32m {
+		return "", 0, nil, fmt.Errorf("witness version %d requires a bech32m checksum", witnessVersion)
+	}
+	return hrp, witnessVersion, program, nil
+}
+
+// validateWitnessProgram checks the witness version and program length rules
+// from BIP-141
+func validateWitnessProgram(witnessVersion byte, program []byte) error {
+	if witnessVersion > 16 {
+		return fmt.Errorf("invalid witness version: %d", witnessVersion)
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("invalid witness program length: %d", len(program))
+	}
+	if witnessVersion == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("invalid witness version 0 program length: %d", len(program))
+	}
+	return nil
+}
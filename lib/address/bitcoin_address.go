@@ -3,6 +3,7 @@ package address
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Bitcoin mainnet and testnet address version bytes
@@ -21,6 +22,15 @@ const (
 	PublicKeyHash BitcoinAddressType = iota
 	// ScriptHash represents a pay-to-script-hash address
 	ScriptHash
+	// WitnessPubKeyHash represents a pay-to-witness-pubkey-hash (segwit v0) address
+	WitnessPubKeyHash
+	// WitnessScriptHash represents a pay-to-witness-script-hash (segwit v0) address
+	WitnessScriptHash
+	// Taproot represents a pay-to-taproot (segwit v1) address
+	Taproot
+	// WitnessUnknown represents a segwit address of a version or program length
+	// with no defined meaning yet
+	WitnessUnknown
 )
 
 // BitcoinNetworkType represents the Bitcoin network
@@ -35,11 +45,29 @@ const (
 	Regtest
 )
 
-// BitcoinAddress represents a Bitcoin address
+// BitcoinAddress represents a Bitcoin address.
+//
+// Base58check addresses carry their hash in Hash160Bytes. Segwit addresses
+// carry their witness version and program, and the human-readable part they
+// were decoded with or are to be encoded with; an empty HRP means the default
+// one for the network. Hash160Bytes also holds the program of a
+// pay-to-witness-pubkey-hash address.
 type BitcoinAddress struct {
-	AddrType     BitcoinAddressType
-	NetworkID    BitcoinNetworkType
-	Hash160Bytes [20]byte
+	AddrType       BitcoinAddressType
+	NetworkID      BitcoinNetworkType
+	Hash160Bytes   [20]byte
+	WitnessVersion byte
+	WitnessProgram []byte
+	HRP            string
+}
+
+// IsSegwit returns true if the address is a bech32 or bech32m segwit address
+func (a *BitcoinAddress) IsSegwit() bool {
+	switch a.AddrType {
+	case WitnessPubKeyHash, WitnessScriptHash, Taproot, WitnessUnknown:
+		return true
+	}
+	return false
 }
 
 // VersionByteToAddressType converts a version byte to address type and network
@@ -75,8 +103,79 @@ func AddressTypeToVersionByte(addrType BitcoinAddressType, networkID BitcoinNetw
 	}
 }
 
-// DecodeBitcoinAddress decodes a base58check Bitcoin address string
-func DecodeBitcoinAddress(addrb58 string) (*BitcoinAddress, error) {
+// NetworkToHRP returns the human-readable part of segwit addresses on the network
+func NetworkToHRP(networkID BitcoinNetworkType) string {
+	switch networkID {
+	case Testnet:
+		return Bech32HRPTestnet
+	case Regtest:
+		return Bech32HRPRegtest
+	default:
+		return Bech32HRPMainnet
+	}
+}
+
+// HRPToNetwork returns the network of segwit addresses with the human-readable part
+func HRPToNetwork(hrp string) (BitcoinNetworkType, bool) {
+	switch strings.ToLower(hrp) {
+	case Bech32HRPMainnet:
+		return Mainnet, true
+	case Bech32HRPTestnet:
+		return Testnet, true
+	case Bech32HRPRegtest:
+		return Regtest, true
+	default:
+		return 0, false
+	}
+}
+
+// witnessAddressType returns the address type of a witness program
+func witnessAddressType(witnessVersion byte, program []byte) BitcoinAddressType {
+	switch {
+	case witnessVersion == 0 && len(program) == 20:
+		return WitnessPubKeyHash
+	case witnessVersion == 0 && len(program) == 32:
+		return WitnessScriptHash
+	case witnessVersion == 1 && len(program) == 32:
+		return Taproot
+	default:
+		return WitnessUnknown
+	}
+}
+
+// DecodeBitcoinAddress decodes a Bitcoin address string: a bech32 or bech32m
+// segwit address for a known network, or a base58check address
+func DecodeBitcoinAddress(addr string) (*BitcoinAddress, error) {
+	if sep := strings.LastIndexByte(addr, '1'); sep > 0 {
+		if networkID, ok := HRPToNetwork(addr[:sep]); ok {
+			return decodeSegwitBitcoinAddress(addr, networkID)
+		}
+	}
+	return decodeBase58BitcoinAddress(addr)
+}
+
+// decodeSegwitBitcoinAddress decodes a segwit address on the network
+func decodeSegwitBitcoinAddress(addr string, networkID BitcoinNetworkType) (*BitcoinAddress, error) {
+	hrp, witnessVersion, program, err := DecodeSegwitAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	decoded := &BitcoinAddress{
+		AddrType:       witnessAddressType(witnessVersion, program),
+		NetworkID:      networkID,
+		WitnessVersion: witnessVersion,
+		WitnessProgram: program,
+		HRP:            hrp,
+	}
+	if decoded.AddrType == WitnessPubKeyHash {
+		copy(decoded.Hash160Bytes[:], program)
+	}
+	return decoded, nil
+}
+
+// decodeBase58BitcoinAddress decodes a base58check Bitcoin address string
+func decodeBase58BitcoinAddress(addrb58 string) (*BitcoinAddress, error) {
 	bytes, err := DecodeBase58Check(addrb58)
 	if err != nil {
 		return nil, err
@@ -102,13 +201,32 @@ func DecodeBitcoinAddress(addrb58 string) (*BitcoinAddress, error) {
 	}, nil
 }
 
-// EncodeBitcoinAddress encodes a Bitcoin address as a base58check string
-func EncodeBitcoinAddress(addr *BitcoinAddress) string {
+// EncodeBitcoinAddress encodes a Bitcoin address as a segwit address for the
+// witness address types and as a base58check string otherwise. A
+// pay-to-witness-pubkey-hash address without a witness program is encoded from
+// its Hash160Bytes.
+func EncodeBitcoinAddress(addr *BitcoinAddress) (string, error) {
+	if addr.IsSegwit() {
+		hrp := addr.HRP
+		if hrp == "" {
+			hrp = NetworkToHRP(addr.NetworkID)
+		}
+		program := addr.WitnessProgram
+		if addr.AddrType == WitnessPubKeyHash && len(program) == 0 {
+			program = addr.Hash160Bytes[:]
+		}
+		encoded, err := EncodeSegwitAddress(hrp, addr.WitnessVersion, program)
+		if err != nil {
+			return "", fmt.Errorf("invalid address: %w", err)
+		}
+		return encoded, nil
+	}
+
 	version := AddressTypeToVersionByte(addr.AddrType, addr.NetworkID)
 
 	data := make([]byte, 21)
 	data[0] = version
 	copy(data[1:], addr.Hash160Bytes[:])
 
-	return EncodeBase58Check(data)
+	return EncodeBase58Check(data), nil
 }
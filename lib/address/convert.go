@@ -0,0 +1,59 @@
+package address
+
+import (
+	"fmt"
+)
+
+// bitcoinToStacksVersion maps Bitcoin base58check version bytes to the
+// Stacks address versions for the same kind of address and network
+var bitcoinToStacksVersion = map[uint8]byte{
+	AddressVersionMainnetSinglesig: C32AddressVersionMainnetSinglesig,
+	AddressVersionMainnetMultisig:  C32AddressVersionMainnetMultisig,
+	AddressVersionTestnetSinglesig: C32AddressVersionTestnetSinglesig,
+	AddressVersionTestnetMultisig:  C32AddressVersionTestnetMultisig,
+}
+
+// stacksToBitcoinVersion is the inverse of bitcoinToStacksVersion
+var stacksToBitcoinVersion = map[byte]uint8{
+	C32AddressVersionMainnetSinglesig: AddressVersionMainnetSinglesig,
+	C32AddressVersionMainnetMultisig:  AddressVersionMainnetMultisig,
+	C32AddressVersionTestnetSinglesig: AddressVersionTestnetSinglesig,
+	C32AddressVersionTestnetMultisig:  AddressVersionTestnetMultisig,
+}
+
+// StacksAddressToBitcoin encodes the hash160 of a Stacks address as a
+// base58check Bitcoin address on the given network. Singlesig addresses become
+// pay-to-pubkey-hash addresses and multisig addresses pay-to-script-hash ones.
+func StacksAddressToBitcoin(addr StacksAddress, network BitcoinNetworkType) (string, error) {
+	version, ok := stacksToBitcoinVersion[addr.Version]
+	if !ok {
+		return "", fmt.Errorf("unknown Stacks address version: %d", addr.Version)
+	}
+	addrType, _, _ := VersionByteToAddressType(version)
+
+	return EncodeBitcoinAddress(&BitcoinAddress{
+		AddrType:     addrType,
+		NetworkID:    network,
+		Hash160Bytes: addr.Hash160,
+	})
+}
+
+// BitcoinToStacksAddress decodes a base58check Bitcoin address and returns
+// the Stacks address with the same hash160, with the singlesig or multisig
+// version for the Bitcoin address's network
+func BitcoinToStacksAddress(addrb58 string) (StacksAddress, error) {
+	addr, err := DecodeBitcoinAddress(addrb58)
+	if err != nil {
+		return StacksAddress{}, fmt.Errorf("error parsing Bitcoin address: %w", err)
+	}
+	if addr.IsSegwit() {
+		return StacksAddress{}, fmt.Errorf("segwit address has no Stacks address version: %s", addrb58)
+	}
+
+	btcVersion := AddressTypeToVersionByte(addr.AddrType, addr.NetworkID)
+	version, ok := bitcoinToStacksVersion[btcVersion]
+	if !ok {
+		return StacksAddress{}, fmt.Errorf("no Stacks address version for Bitcoin version byte: %d", btcVersion)
+	}
+	return NewStacksAddress(version, addr.Hash160Bytes), nil
+}
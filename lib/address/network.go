@@ -0,0 +1,73 @@
+package address
+
+import (
+	"fmt"
+
+	"github.com/janniks/stacks-go/lib/network"
+)
+
+// CheckNetwork checks that the address version is one of the network's
+func (a StacksAddress) CheckNetwork(n network.Network) error {
+	if err := n.CheckAddressVersion(a.Version); err != nil {
+		return fmt.Errorf("address %s: %w", a, err)
+	}
+	return nil
+}
+
+// FromStringOnNetwork creates a StacksAddress from a C32-encoded string,
+// checking that it belongs to the network
+func FromStringOnNetwork(s string, n network.Network) (StacksAddress, error) {
+	addr, err := FromString(s)
+	if err != nil {
+		return StacksAddress{}, err
+	}
+	if err := addr.CheckNetwork(n); err != nil {
+		return StacksAddress{}, err
+	}
+	return addr, nil
+}
+
+// ToVersion converts an AddressHashMode to its corresponding version on the network
+// Returns an error if the mode is not valid
+func (m AddressHashMode) ToVersion(n network.Network) (byte, error) {
+	switch m {
+	case SerializeP2PKH, SerializeP2WPKH:
+		return n.AddressVersionSinglesig, nil
+	case SerializeP2SH, SerializeP2WSH, SerializeP2SHNonSequential, SerializeP2WSHNonSequential:
+		return n.AddressVersionMultisig, nil
+	}
+	return 0, fmt.Errorf("invalid address hash mode for %s conversion: %d", n, m)
+}
+
+// BitcoinNetwork returns the Bitcoin network a Stacks network settles on
+func BitcoinNetwork(n network.Network) BitcoinNetworkType {
+	if networkID, ok := HRPToNetwork(n.BitcoinHRP); ok {
+		return networkID
+	}
+	if n.BitcoinVersionSinglesig == AddressVersionMainnetSinglesig {
+		return Mainnet
+	}
+	return Testnet
+}
+
+// DecodeBitcoinAddressOnNetwork decodes a Bitcoin address string, checking
+// that it belongs to the Bitcoin network the Stacks network settles on
+func DecodeBitcoinAddressOnNetwork(addr string, n network.Network) (*BitcoinAddress, error) {
+	decoded, err := DecodeBitcoinAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded.IsSegwit() {
+		if decoded.HRP != n.BitcoinHRP {
+			return nil, fmt.Errorf("%w: Bitcoin address %s is not a %s address", network.ErrWrongNetwork, addr, n)
+		}
+	} else {
+		version := AddressTypeToVersionByte(decoded.AddrType, decoded.NetworkID)
+		if version != n.BitcoinVersionSinglesig && version != n.BitcoinVersionMultisig {
+			return nil, fmt.Errorf("%w: Bitcoin address %s is not a %s address", network.ErrWrongNetwork, addr, n)
+		}
+	}
+	decoded.NetworkID = BitcoinNetwork(n)
+	return decoded, nil
+}
@@ -0,0 +1,118 @@
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// FromPublicKeys creates the address with the given version for the public
+// keys under the hash mode, see PublicKeysToHash160
+func FromPublicKeys(version byte, hashMode AddressHashMode, signaturesRequired int, publicKeys [][]byte) (StacksAddress, error) {
+	hash, err := PublicKeysToHash160(hashMode, signaturesRequired, publicKeys)
+	if err != nil {
+		return StacksAddress{}, err
+	}
+	return NewStacksAddress(version, hash), nil
+}
+
+// PublicKeysToHash160 computes the hash160 that an address commits to for the
+// public keys under the hash mode:
+//   - P2PKH: hash160 of the public key
+//   - P2WPKH: hash160 of the segwit v0 program of the public key hash
+//   - P2SH: hash160 of the m-of-n multisig redeem script
+//   - P2WSH: hash160 of the segwit v0 program of the redeem script hash
+//
+// Public keys are 33-byte compressed or 65-byte uncompressed secp256k1 keys,
+// hashed as given; the segwit modes only accept compressed keys. Singlesig
+// modes take exactly one key with signaturesRequired 1.
+func PublicKeysToHash160(hashMode AddressHashMode, signaturesRequired int, publicKeys [][]byte) ([20]byte, error) {
+	if !hashMode.IsValid() {
+		return [20]byte{}, fmt.Errorf("invalid address hash mode: %d", hashMode)
+	}
+	singlesig := hashMode == SerializeP2PKH || hashMode == SerializeP2WPKH
+	if singlesig && (len(publicKeys) != 1 || signaturesRequired != 1) {
+		return [20]byte{}, fmt.Errorf("singlesig hash mode %d requires 1 public key, got %d of %d", hashMode, signaturesRequired, len(publicKeys))
+	}
+	if signaturesRequired < 1 || signaturesRequired > len(publicKeys) {
+		return [20]byte{}, fmt.Errorf("invalid multisig: %d of %d signatures required", signaturesRequired, len(publicKeys))
+	}
+
+	segwit := hashMode == SerializeP2WPKH || hashMode == SerializeP2WSH || hashMode == SerializeP2WSHNonSequential
+	for i, publicKey := range publicKeys {
+		if _, err := secp256k1.ParsePubKey(publicKey); err != nil {
+			return [20]byte{}, fmt.Errorf("invalid public key %d: %w", i, err)
+		}
+		if segwit && len(publicKey) != secp256k1.PubKeyBytesLenCompressed {
+			return [20]byte{}, fmt.Errorf("hash mode %d requires compressed public keys", hashMode)
+		}
+	}
+
+	switch hashMode {
+	case SerializeP2PKH:
+		return hash160(publicKeys[0]), nil
+
+	case SerializeP2WPKH:
+		keyHash := hash160(publicKeys[0])
+		return hash160(append([]byte{0x00, 0x14}, keyHash[:]...)), nil
+
+	case SerializeP2SH, SerializeP2SHNonSequential:
+		return hash160(multisigScript(signaturesRequired, publicKeys)), nil
+
+	default:
+		scriptHash := sha256.Sum256(multisigScript(signaturesRequired, publicKeys))
+		return hash160(append([]byte{0x00, 0x20}, scriptHash[:]...)), nil
+	}
+}
+
+// multisigScript builds the m-of-n redeem script
+// OP_m <public key>... OP_n OP_CHECKMULTISIG
+func multisigScript(required int, publicKeys [][]byte) []byte {
+	var script bytes.Buffer
+	writeScriptInt(&script, required)
+	for _, publicKey := range publicKeys {
+		script.WriteByte(byte(len(publicKey)))
+		script.Write(publicKey)
+	}
+	writeScriptInt(&script, len(publicKeys))
+	script.WriteByte(0xae) // OP_CHECKMULTISIG
+	return script.Bytes()
+}
+
+// writeScriptInt pushes a non-negative integer the way bitcoin script builders
+// do: OP_0 and OP_1 through OP_16 for small values, a minimal little-endian
+// push otherwise
+func writeScriptInt(script *bytes.Buffer, n int) {
+	if n == 0 {
+		script.WriteByte(0x00)
+		return
+	}
+	if n <= 16 {
+		script.WriteByte(0x50 + byte(n))
+		return
+	}
+
+	var num []byte
+	for ; n > 0; n >>= 8 {
+		num = append(num, byte(n))
+	}
+	// Keep the sign bit clear
+	if num[len(num)-1]&0x80 != 0 {
+		num = append(num, 0x00)
+	}
+	script.WriteByte(byte(len(num)))
+	script.Write(num)
+}
+
+// hash160 returns RIPEMD160(SHA256(data))
+func hash160(data []byte) [20]byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	var hash [20]byte
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
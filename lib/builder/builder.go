@@ -0,0 +1,303 @@
+// Package builder builds unsigned Stacks transactions
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/post_condition"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+// ErrInvalidTransaction matches every error returned by Build
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// MaxMemoLength is the length of a token transfer memo
+const MaxMemoLength = 34
+
+// Builder builds an unsigned transaction. Its methods return the builder so
+// calls can be chained; the first error is reported by Build.
+//
+// Transactions are built for mainnet with anchor mode any and post condition
+// mode deny unless configured otherwise.
+type Builder struct {
+	tx     transaction.StacksTransaction
+	origin *transaction.TransactionSpendingCondition
+	nonce  uint64
+	fee    uint64
+	// network gives the transaction version and chain ID, and the versions of
+	// the addresses the transaction may refer to
+	network network.Network
+	err     error
+}
+
+func newBuilder(payload transaction.TransactionPayload) *Builder {
+	return &Builder{
+		tx: transaction.StacksTransaction{
+			Auth:              transaction.TransactionAuth{AuthType: transaction.TransactionAuthFlagStandard},
+			AnchorMode:        transaction.TransactionAnchorModeAny,
+			PostConditionMode: transaction.TransactionPostConditionModeDeny,
+			Payload:           payload,
+		},
+		network: network.Mainnet,
+	}
+}
+
+// NewTokenTransfer starts a transfer of amount micro-STX to recipient, a
+// standard principal ("SP...") or contract principal ("SP....name")
+func NewTokenTransfer(recipient string, amount uint64) *Builder {
+	b := newBuilder(transaction.TransactionPayload{PayloadType: transaction.TransactionPayloadIDTokenTransfer})
+	principal, err := b.parsePrincipal(recipient)
+	b.setErr(err)
+	b.tx.Payload.TokenTransfer = &transaction.TokenTransferPayload{
+		Recipient: principal,
+		Amount:    amount,
+	}
+	return b
+}
+
+// NewContractCall starts a call to functionName on contract ("SP....name") with args
+func NewContractCall(contract string, functionName string, args ...clarity_value.Value) *Builder {
+	b := newBuilder(transaction.TransactionPayload{PayloadType: transaction.TransactionPayloadIDContractCall})
+	issuer, contractName, err := b.parseContractID(contract)
+	b.setErr(err)
+
+	functionArgs := make([]clarity_value.ClarityValue, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			b.setErr(fmt.Errorf("missing function argument %d", i))
+		}
+		functionArgs[i] = clarity_value.NewClarityValue(arg)
+	}
+
+	b.tx.Payload.ContractCall = &transaction.ContractCallPayload{
+		Address:      transaction.StacksAddress{Version: issuer.Version, Hash160: issuer.Hash160},
+		ContractName: []byte(contractName),
+		FunctionName: []byte(functionName),
+		FunctionArgs: functionArgs,
+	}
+	return b
+}
+
+// NewSmartContract starts a deployment of code as contract name. Use
+// ClarityVersion to deploy it as a versioned smart contract.
+func NewSmartContract(name string, code string) *Builder {
+	b := newBuilder(transaction.TransactionPayload{PayloadType: transaction.TransactionPayloadIDSmartContract})
+	b.tx.Payload.SmartContract = &transaction.SmartContractPayload{
+		Name:     []byte(name),
+		CodeBody: []byte(code),
+	}
+	return b
+}
+
+// Memo sets the memo of a token transfer
+func (b *Builder) Memo(memo string) *Builder {
+	if b.tx.Payload.TokenTransfer == nil {
+		b.setErr(fmt.Errorf("memo set on a non-token-transfer payload"))
+		return b
+	}
+	if len(memo) > MaxMemoLength {
+		b.setErr(fmt.Errorf("memo is %d bytes, at most %d allowed", len(memo), MaxMemoLength))
+		return b
+	}
+	b.tx.Payload.TokenTransfer.Memo = [MaxMemoLength]byte{}
+	copy(b.tx.Payload.TokenTransfer.Memo[:], memo)
+	return b
+}
+
+// ClarityVersion deploys a smart contract as a versioned smart contract
+func (b *Builder) ClarityVersion(version uint8) *Builder {
+	if b.tx.Payload.SmartContract == nil {
+		b.setErr(fmt.Errorf("clarity version set on a non-smart-contract payload"))
+		return b
+	}
+	b.tx.Payload.PayloadType = transaction.TransactionPayloadIDVersionedSmartContract
+	b.tx.Payload.ClarityVersion = &version
+	return b
+}
+
+// Network builds the transaction for the network profile
+func (b *Builder) Network(n network.Network) *Builder {
+	b.network = n
+	return b
+}
+
+// Mainnet builds the transaction for mainnet
+func (b *Builder) Mainnet() *Builder {
+	return b.Network(network.Mainnet)
+}
+
+// Testnet builds the transaction for testnet
+func (b *Builder) Testnet() *Builder {
+	return b.Network(network.Testnet)
+}
+
+// Version overrides the transaction version of the network profile
+func (b *Builder) Version(version uint8) *Builder {
+	b.network.TransactionVersion = version
+	return b
+}
+
+// ChainID overrides the chain ID of the network profile, e.g. for a subnet
+func (b *Builder) ChainID(chainID uint32) *Builder {
+	b.network.ChainID = chainID
+	return b
+}
+
+// Origin sets the origin spending condition, as created by
+// transaction.NewSinglesigSpendingCondition or NewMultisigSpendingCondition
+func (b *Builder) Origin(condition transaction.TransactionSpendingCondition) *Builder {
+	b.origin = &condition
+	return b
+}
+
+// Sponsored makes the transaction sponsored. The sponsor spending condition
+// is left empty for the sponsor to fill in after the origin signs.
+func (b *Builder) Sponsored() *Builder {
+	b.tx.Auth.AuthType = transaction.TransactionAuthFlagSponsored
+	return b
+}
+
+// Nonce sets the origin nonce
+func (b *Builder) Nonce(nonce uint64) *Builder {
+	b.nonce = nonce
+	return b
+}
+
+// Fee sets the origin fee in micro-STX
+func (b *Builder) Fee(fee uint64) *Builder {
+	b.fee = fee
+	return b
+}
+
+// AnchorMode sets the anchor mode
+func (b *Builder) AnchorMode(mode uint8) *Builder {
+	b.tx.AnchorMode = mode
+	return b
+}
+
+// PostConditionMode sets the post condition mode
+func (b *Builder) PostConditionMode(mode uint8) *Builder {
+	b.tx.PostConditionMode = mode
+	return b
+}
+
+// PostConditions appends post conditions
+func (b *Builder) PostConditions(postConditions ...post_condition.PostCondition) *Builder {
+	b.tx.PostConditions = append(b.tx.PostConditions, postConditions...)
+	return b
+}
+
+// Build checks the transaction and returns it, unsigned.
+//
+// The transaction is serialized and decoded strictly, so it is rejected for
+// any field stacks-core would reject, such as an invalid contract name or
+// clarity version. Build also rejects a network profile whose transaction version
+// does not match the chain ID, and addresses that do not belong to the network.
+func (b *Builder) Build() (*transaction.StacksTransaction, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	b.tx.Version = b.network.TransactionVersion
+	b.tx.ChainID = b.network.ChainID
+	if err := b.checkNetwork(); err != nil {
+		return nil, err
+	}
+
+	if b.origin == nil {
+		return nil, fmt.Errorf("%w: missing origin spending condition", ErrInvalidTransaction)
+	}
+	tx := b.tx
+	tx.Auth.SpendingCondition = *b.origin
+	tx.Auth.SpendingCondition.Nonce = b.nonce
+	tx.Auth.SpendingCondition.Fee = b.fee
+	if tx.Auth.AuthType == transaction.TransactionAuthFlagSponsored {
+		keyEncoding := transaction.PublicKeyEncodingCompressed
+		tx.Auth.SponsorSpendingCondition = &transaction.TransactionSpendingCondition{
+			HashMode:    transaction.SinglesigHashModeP2PKH,
+			KeyEncoding: &keyEncoding,
+		}
+	}
+
+	serialized, err := tx.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	built, err := transaction.DecodeTransaction(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return built, nil
+}
+
+// checkNetwork checks that the network profile is consistent and that the
+// version, chain ID and addresses belong to it
+func (b *Builder) checkNetwork() error {
+	if err := b.network.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	if err := b.tx.CheckNetwork(b.network); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return nil
+}
+
+// parsePrincipal parses a standard or contract principal
+func (b *Builder) parsePrincipal(principal string) (transaction.PrincipalData, error) {
+	if strings.Contains(principal, ".") {
+		issuer, name, err := b.parseContractID(principal)
+		if err != nil {
+			return transaction.PrincipalData{}, err
+		}
+		return transaction.PrincipalData{
+			Type: transaction.PrincipalTypeContract,
+			ContractData: &transaction.QualifiedContractIdentifier{
+				Issuer: transaction.StandardPrincipalData{Version: issuer.Version, Address: issuer.Hash160},
+				Name:   []byte(name),
+			},
+		}, nil
+	}
+
+	addr, err := b.parseAddress(principal)
+	if err != nil {
+		return transaction.PrincipalData{}, err
+	}
+	return transaction.PrincipalData{
+		Type:         transaction.PrincipalTypeStandard,
+		StandardData: &transaction.StandardPrincipalData{Version: addr.Version, Address: addr.Hash160},
+	}, nil
+}
+
+// parseContractID parses a contract identifier "address.name"
+func (b *Builder) parseContractID(contract string) (address.StacksAddress, string, error) {
+	issuer, name, found := strings.Cut(contract, ".")
+	if !found {
+		return address.StacksAddress{}, "", fmt.Errorf("%w: invalid contract identifier: %q", ErrInvalidTransaction, contract)
+	}
+	addr, err := b.parseAddress(issuer)
+	return addr, name, err
+}
+
+// parseAddress parses a c32 address
+func (b *Builder) parseAddress(s string) (address.StacksAddress, error) {
+	addr, err := address.FromString(s)
+	if err != nil {
+		return address.StacksAddress{}, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	return addr, nil
+}
+
+// setErr records the first error
+func (b *Builder) setErr(err error) {
+	if err != nil && b.err == nil {
+		if !errors.Is(err, ErrInvalidTransaction) {
+			err = fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+		b.err = err
+	}
+}
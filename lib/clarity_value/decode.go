@@ -34,9 +34,9 @@ func DecodeClarityValueToObject(val *ClarityValue, deep bool, bytes []byte) (*De
 	if deep {
 		switch v := val.Value.(type) {
 		case IntValue:
-			decoded.Value = fmt.Sprintf("%d", v)
+			decoded.Value = Int128(v).String()
 		case UIntValue:
-			decoded.Value = fmt.Sprintf("%d", v)
+			decoded.Value = Uint128(v).String()
 		case BoolValue:
 			decoded.Value = bool(v)
 		case BufferValue:
@@ -1,77 +1,70 @@
 package clarity_value
 
 import (
-	"bytes"
-	"encoding/binary"
-	"errors"
 	"fmt"
 	"io"
+	"unicode/utf8"
 )
 
-// DeserializeError represents an error during deserialization
-type DeserializeError struct {
-	message string
-}
-
-func (e DeserializeError) Error() string {
-	return e.message
-}
-
-// NewDeserializeError creates a new DeserializeError
-func NewDeserializeError(message string) DeserializeError {
-	return DeserializeError{message: message}
-}
-
-// DecodeClarityName deserializes a ClarityName from a byte reader
-func DecodeClarityName(r *bytes.Reader) (ClarityName, error) {
-	lenByte, err := r.ReadByte()
+// DecodeClarityName deserializes a ClarityName from a reader
+func DecodeClarityName(r io.Reader) (ClarityName, error) {
+	offset := InputOffset(r)
+	lenByte, err := readByte(r)
 	if err != nil {
-		return "", err
+		return "", WrapReadError(err, offset)
 	}
 
 	if lenByte > MaxStringLen {
-		return "", NewDeserializeError(fmt.Sprintf("Failed to deserialize clarity name: too long: %d", lenByte))
+		return "", DeserializeErrorf(KindInvalidName, offset, "clarity name too long: %d", lenByte)
 	}
 
 	data := make([]byte, lenByte)
 	if _, err := io.ReadFull(r, data); err != nil {
-		return "", err
+		return "", WrapReadError(err, offset)
 	}
 
-	s := string(data)
-	return ValidateClarityName(s)
+	name, err := ValidateClarityName(string(data))
+	if err != nil {
+		return "", &DeserializeError{Kind: KindInvalidName, Offset: offset, Err: err}
+	}
+	return name, nil
 }
 
-// DecodeContractName deserializes a ContractName from a byte reader
-func DecodeContractName(r *bytes.Reader) (ContractName, error) {
-	lenByte, err := r.ReadByte()
+// DecodeContractName deserializes a ContractName from a reader
+func DecodeContractName(r io.Reader) (ContractName, error) {
+	offset := InputOffset(r)
+	lenByte, err := readByte(r)
 	if err != nil {
-		return "", err
+		return "", WrapReadError(err, offset)
 	}
 
 	if uint8(lenByte) < ContractMinNameLength || uint8(lenByte) > ContractMaxNameLength {
-		return "", NewDeserializeError(fmt.Sprintf("Failed to deserialize contract name: too short or too long: %d", lenByte))
+		return "", DeserializeErrorf(KindInvalidName, offset, "contract name too short or too long: %d", lenByte)
 	}
 
 	data := make([]byte, lenByte)
 	if _, err := io.ReadFull(r, data); err != nil {
-		return "", err
+		return "", WrapReadError(err, offset)
 	}
 
-	s := string(data)
-	return ValidateContractName(s)
+	name, err := ValidateContractName(string(data))
+	if err != nil {
+		return "", &DeserializeError{Kind: KindInvalidName, Offset: offset, Err: err}
+	}
+	return name, nil
 }
 
-// DecodeStandardPrincipalData deserializes a StandardPrincipalData from a byte reader
-func DecodeStandardPrincipalData(r *bytes.Reader) (StandardPrincipalData, error) {
-	version, err := r.ReadByte()
+// DecodeStandardPrincipalData deserializes a StandardPrincipalData from a reader
+func DecodeStandardPrincipalData(r io.Reader) (StandardPrincipalData, error) {
+	offset := InputOffset(r)
+	version, err := readByte(r)
 	if err != nil {
-		return StandardPrincipalData{}, err
+		return StandardPrincipalData{}, WrapReadError(err, offset)
 	}
 
 	var hash [20]byte
 	if _, err := io.ReadFull(r, hash[:]); err != nil {
-		return StandardPrincipalData{}, err
+		return StandardPrincipalData{}, WrapReadError(err, offset)
 	}
 
 	return StandardPrincipalData{
@@ -80,55 +73,127 @@ func DecodeStandardPrincipalData(r *bytes.Reader) (StandardPrincipalData, error)
 	}, nil
 }
 
-// DecodeClarityValue deserializes a ClarityValue from a byte reader
-func DecodeClarityValue(r *bytes.Reader, withBytes bool) (ClarityValue, error) {
-	return decodeClarityValueInternal(r, 0, withBytes)
+// DecodeClarityValue deserializes a ClarityValue from a reader. With withBytes set,
+// nested SerializedBytes alias the buffer of the top-level value.
+func DecodeClarityValue(r io.Reader, withBytes bool) (ClarityValue, error) {
+	return NewDecoder(r).Decode(withBytes)
+}
+
+// DecodeClarityValueWithOptions deserializes a ClarityValue from a reader without type checking
+func DecodeClarityValueWithOptions(r io.Reader, opts DecodeOptions) (ClarityValue, error) {
+	return NewDecoder(r).DecodeWithOptions(opts)
+}
+
+// DecodeOptions configures type-directed deserialization
+type DecodeOptions struct {
+	// WithBytes captures the serialized bytes of every decoded value
+	WithBytes bool
+	// Sanitize drops tuple fields that are not part of the expected type,
+	// matching the Clarity 2 sanitization performed by stacks-core
+	Sanitize bool
+	// CopyBytes gives every value its own copy of its serialized bytes instead
+	// of a sub-slice of the buffer holding the top-level value
+	CopyBytes bool
+	// Path names the top-level value in error paths; it defaults to "value"
+	Path string
+}
+
+// DecodeClarityValueWithType deserializes a ClarityValue from a reader, enforcing the expected type.
+// Values that do not fit the type fail with a *DeserializeError of kind KindTypeMismatch
+// wrapping an *AdmissionError that names the offending path.
+func DecodeClarityValueWithType(r io.Reader, expected TypeSignature, opts DecodeOptions) (ClarityValue, error) {
+	return NewDecoder(r).DecodeWithType(expected, opts)
 }
 
-// decodeClarityValueInternal handles the recursive deserialization of ClarityValue
-func decodeClarityValueInternal(r *bytes.Reader, depth uint8, withBytes bool) (ClarityValue, error) {
-	if depth >= 16 {
-		return ClarityValue{}, NewDeserializeError(fmt.Sprintf("TypeSignatureTooDeep: %d", depth))
+// prefixMatchesType reports whether a serialized type prefix can hold a value of the expected type
+func prefixMatchesType(prefix TypePrefix, expected TypeSignature) bool {
+	switch expected.(type) {
+	case IntType:
+		return prefix == PrefixInt
+	case UIntType:
+		return prefix == PrefixUInt
+	case BoolType:
+		return prefix == PrefixBoolTrue || prefix == PrefixBoolFalse
+	case PrincipalType:
+		return prefix == PrefixPrincipalStandard || prefix == PrefixPrincipalContract
+	case BufferType:
+		return prefix == PrefixBuffer
+	case StringASCIIType:
+		return prefix == PrefixStringASCII
+	case StringUTF8Type:
+		return prefix == PrefixStringUTF8
+	case ListType:
+		return prefix == PrefixList
+	case TupleType:
+		return prefix == PrefixTuple
+	case OptionalType:
+		return prefix == PrefixOptionalNone || prefix == PrefixOptionalSome
+	case ResponseType:
+		return prefix == PrefixResponseOk || prefix == PrefixResponseErr
+	}
+	return false
+}
+
+// decodeClarityValueInternal handles the recursive deserialization of ClarityValue.
+// When expected is non-nil the value is checked against it while decoding; the returned
+// bool reports whether sanitization removed anything from the value.
+func decodeClarityValueInternal(r *decodeReader, depth uint8, withBytes bool, expected TypeSignature, sanitize bool) (ClarityValue, bool, error) {
+	if depth >= MaxValueDepth {
+		return ClarityValue{}, false, DeserializeErrorf(KindTooDeep, r.offset, "depth %d exceeds %d", depth, MaxValueDepth)
 	}
 
-	startPos := r.Size() - int64(r.Len())
+	startPos := r.offset
 
 	header, err := r.ReadByte()
 	if err != nil {
-		return ClarityValue{}, err
+		return ClarityValue{}, false, WrapReadError(err, startPos)
 	}
 
 	prefix := TypePrefix(header)
 
+	fail := func(kind DeserializeErrorKind, format string, args ...interface{}) error {
+		return DeserializeErrorf(kind, startPos, format, args...)
+	}
+	typeError := func(format string, args ...interface{}) error {
+		admissionErr := &AdmissionError{Expected: expected, Reason: fmt.Sprintf(format, args...)}
+		return &DeserializeError{Kind: KindTypeMismatch, Offset: startPos, Err: admissionErr}
+	}
+	if expected != nil && !prefixMatchesType(prefix, expected) {
+		return ClarityValue{}, false, typeError("got type prefix %d", prefix)
+	}
+
 	var value Value
-	var deserializeErr error
+	var sanitized bool
 
 	switch prefix {
 	case PrefixInt:
-		var buf [16]byte
-		if _, err := io.ReadFull(r, buf[:]); err != nil {
-			return ClarityValue{}, err
+		buf, err := r.read128()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
-		value = IntValue(int64(binary.BigEndian.Uint64(buf[8:])))
+		value = IntValue(Int128FromBytes(buf))
 
 	case PrefixUInt:
-		var buf [16]byte
-		if _, err := io.ReadFull(r, buf[:]); err != nil {
-			return ClarityValue{}, err
+		buf, err := r.read128()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
-		value = UIntValue(binary.BigEndian.Uint64(buf[8:]))
+		value = UIntValue(Uint128FromBytes(buf))
 
 	case PrefixBuffer:
-		var bufLen uint32
-		if err := binary.Read(r, binary.BigEndian, &bufLen); err != nil {
-			return ClarityValue{}, err
+		bufLen, err := r.readUint32()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		if bufLen > MaxValueSize {
-			return ClarityValue{}, NewDeserializeError("Illegal buffer type size")
+			return ClarityValue{}, false, fail(KindTooLarge, "buffer length %d exceeds %d", bufLen, MaxValueSize)
+		}
+		if t, ok := expected.(BufferType); ok && bufLen > t.Length {
+			return ClarityValue{}, false, typeError("length %d exceeds %d", bufLen, t.Length)
 		}
 		data := make([]byte, bufLen)
 		if _, err := io.ReadFull(r, data); err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		value = BufferValue(data)
 
@@ -141,146 +206,207 @@ func decodeClarityValueInternal(r *bytes.Reader, depth uint8, withBytes bool) (C
 	case PrefixPrincipalStandard:
 		principal, err := DecodeStandardPrincipalData(r)
 		if err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, err
 		}
 		value = PrincipalStandardValue(principal)
 
 	case PrefixPrincipalContract:
 		issuer, err := DecodeStandardPrincipalData(r)
 		if err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, err
 		}
 		name, err := DecodeClarityName(r)
 		if err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, err
 		}
 		value = PrincipalContractValue(QualifiedContractIdentifier{
 			Issuer: issuer,
 			Name:   name,
 		})
 
-	case PrefixResponseOk:
-		innerValue, err := decodeClarityValueInternal(r, depth+1, withBytes)
-		if err != nil {
-			return ClarityValue{}, err
+	case PrefixResponseOk, PrefixResponseErr:
+		var innerType TypeSignature
+		segment := ".ok"
+		if prefix == PrefixResponseErr {
+			segment = ".err"
 		}
-		value = ResponseOkValue{Value: innerValue}
-
-	case PrefixResponseErr:
-		innerValue, err := decodeClarityValueInternal(r, depth+1, withBytes)
+		if t, ok := expected.(ResponseType); ok {
+			innerType = t.Ok
+			if prefix == PrefixResponseErr {
+				innerType = t.Err
+			}
+		}
+		innerValue, innerSanitized, err := decodeClarityValueInternal(r, depth+1, withBytes, innerType, sanitize)
 		if err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, WithPathSegment(err, segment)
+		}
+		sanitized = innerSanitized
+		if prefix == PrefixResponseOk {
+			value = ResponseOkValue{Value: innerValue}
+		} else {
+			value = ResponseErrValue{Value: innerValue}
 		}
-		value = ResponseErrValue{Value: innerValue}
 
 	case PrefixOptionalNone:
 		value = OptionalNoneValue{}
 
 	case PrefixOptionalSome:
-		innerValue, err := decodeClarityValueInternal(r, depth+1, withBytes)
+		var innerType TypeSignature
+		if t, ok := expected.(OptionalType); ok {
+			innerType = t.Inner
+		}
+		innerValue, innerSanitized, err := decodeClarityValueInternal(r, depth+1, withBytes, innerType, sanitize)
 		if err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, WithPathSegment(err, ".some")
 		}
+		sanitized = innerSanitized
 		value = OptionalSomeValue{Value: innerValue}
 
 	case PrefixList:
-		var listLen uint32
-		if err := binary.Read(r, binary.BigEndian, &listLen); err != nil {
-			return ClarityValue{}, err
+		listLen, err := r.readUint32()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		if listLen > MaxValueSize {
-			return ClarityValue{}, NewDeserializeError("Illegal list type size")
+			return ClarityValue{}, false, fail(KindTooLarge, "list length %d exceeds %d", listLen, MaxValueSize)
+		}
+		var elementType TypeSignature
+		if t, ok := expected.(ListType); ok {
+			if listLen > t.MaxLength {
+				return ClarityValue{}, false, typeError("length %d exceeds %d", listLen, t.MaxLength)
+			}
+			elementType = t.Element
 		}
 		items := make([]ClarityValue, listLen)
 		for i := uint32(0); i < listLen; i++ {
-			item, err := decodeClarityValueInternal(r, depth+1, withBytes)
+			item, itemSanitized, err := decodeClarityValueInternal(r, depth+1, withBytes, elementType, sanitize)
 			if err != nil {
-				return ClarityValue{}, err
+				return ClarityValue{}, false, WithPathSegment(err, fmt.Sprintf("[%d]", i))
 			}
+			sanitized = sanitized || itemSanitized
 			items[i] = item
 		}
 		value = ListValue(items)
 
 	case PrefixTuple:
-		var tupleLen uint32
-		if err := binary.Read(r, binary.BigEndian, &tupleLen); err != nil {
-			return ClarityValue{}, err
+		tupleLen, err := r.readUint32()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		if tupleLen > MaxValueSize {
-			return ClarityValue{}, NewDeserializeError("Illegal tuple type size")
+			return ClarityValue{}, false, fail(KindTooLarge, "tuple length %d exceeds %d", tupleLen, MaxValueSize)
 		}
-		data := make(TupleValue)
+		tupleType, typed := expected.(TupleType)
+		if typed {
+			if sanitize && tupleLen < uint32(len(tupleType)) {
+				return ClarityValue{}, false, typeError("expected at least %d fields, got %d", len(tupleType), tupleLen)
+			}
+			if !sanitize && tupleLen != uint32(len(tupleType)) {
+				return ClarityValue{}, false, typeError("expected %d fields, got %d", len(tupleType), tupleLen)
+			}
+		}
+		data := make(TupleValue, tupleLen)
+		seen := make(map[ClarityName]struct{})
 		for i := uint32(0); i < tupleLen; i++ {
 			key, err := DecodeClarityName(r)
 			if err != nil {
-				return ClarityValue{}, err
+				return ClarityValue{}, false, err
+			}
+			if _, exists := seen[key]; exists {
+				return ClarityValue{}, false, fail(KindDuplicateField, "%s", key)
 			}
-			val, err := decodeClarityValueInternal(r, depth+1, withBytes)
+			seen[key] = struct{}{}
+
+			var fieldType TypeSignature
+			if typed {
+				var known bool
+				fieldType, known = tupleType[key]
+				if !known {
+					if !sanitize {
+						return ClarityValue{}, false, typeError("unexpected field %s", key)
+					}
+					// Decode and drop fields the expected type does not declare
+					if _, _, err := decodeClarityValueInternal(r, depth+1, false, nil, false); err != nil {
+						return ClarityValue{}, false, WithPathSegment(err, "."+string(key))
+					}
+					sanitized = true
+					continue
+				}
+			}
+			val, fieldSanitized, err := decodeClarityValueInternal(r, depth+1, withBytes, fieldType, sanitize)
 			if err != nil {
-				return ClarityValue{}, err
+				return ClarityValue{}, false, WithPathSegment(err, "."+string(key))
 			}
+			sanitized = sanitized || fieldSanitized
 			data[key] = val
 		}
+		if typed {
+			for _, name := range tupleType.SortedNames() {
+				if _, ok := data[name]; !ok {
+					return ClarityValue{}, false, typeError("missing field %s", name)
+				}
+			}
+		}
 		value = data
 
 	case PrefixStringASCII:
-		var bufLen uint32
-		if err := binary.Read(r, binary.BigEndian, &bufLen); err != nil {
-			return ClarityValue{}, err
+		bufLen, err := r.readUint32()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		if bufLen > MaxValueSize {
-			return ClarityValue{}, NewDeserializeError("Illegal string-ascii type size")
+			return ClarityValue{}, false, fail(KindTooLarge, "string-ascii length %d exceeds %d", bufLen, MaxValueSize)
+		}
+		if t, ok := expected.(StringASCIIType); ok && bufLen > t.Length {
+			return ClarityValue{}, false, typeError("length %d exceeds %d", bufLen, t.Length)
 		}
 		data := make([]byte, bufLen)
 		if _, err := io.ReadFull(r, data); err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		value = StringASCIIValue(data)
 
 	case PrefixStringUTF8:
-		var totalLen uint32
-		if err := binary.Read(r, binary.BigEndian, &totalLen); err != nil {
-			return ClarityValue{}, err
+		totalLen, err := r.readUint32()
+		if err != nil {
+			return ClarityValue{}, false, WrapReadError(err, startPos)
 		}
 		if totalLen > MaxValueSize {
-			return ClarityValue{}, NewDeserializeError("Illegal string-utf8 type size")
+			return ClarityValue{}, false, fail(KindTooLarge, "string-utf8 length %d exceeds %d", totalLen, MaxValueSize)
+		}
+		t, typed := expected.(StringUTF8Type)
+		if typed && uint64(totalLen) > uint64(t.Length)*4 {
+			return ClarityValue{}, false, typeError("byte length %d exceeds %d", totalLen, uint64(t.Length)*4)
 		}
 		data := make([]byte, totalLen)
 		if _, err := io.ReadFull(r, data); err != nil {
-			return ClarityValue{}, err
+			return ClarityValue{}, false, WrapReadError(err, startPos)
+		}
+		if !utf8.Valid(data) {
+			return ClarityValue{}, false, fail(KindInvalidUTF8, "string-utf8 is not valid UTF-8")
+		}
+		str := NewStringUTF8Value(data)
+		if typed && uint64(len(str)) > uint64(t.Length) {
+			return ClarityValue{}, false, typeError("length %d exceeds %d", len(str), t.Length)
 		}
-		value = NewStringUTF8Value(data)
+		value = str
 
 	default:
-		return ClarityValue{}, errors.New("Bad type prefix")
+		return ClarityValue{}, false, fail(KindBadPrefix, "%d", header)
 	}
 
+	result := ClarityValue{Value: value}
 	if withBytes {
-		endPos := r.Size() - int64(r.Len())
-		allBytes := make([]byte, endPos-startPos)
-
-		// Remember current position
-		currentPos := r.Size() - int64(r.Len())
-
-		// Go back to read all the bytes
-		_, err = r.Seek(startPos, io.SeekStart)
-		if err != nil {
-			return ClarityValue{}, err
-		}
-
-		_, err = io.ReadFull(r, allBytes)
-		if err != nil {
-			return ClarityValue{}, err
-		}
-
-		// Restore position
-		_, err = r.Seek(currentPos, io.SeekStart)
-		if err != nil {
-			return ClarityValue{}, err
+		result.Offset = startPos
+		// A sanitized value no longer matches its input span, so re-serialize it
+		if sanitized {
+			if result.SerializedBytes, err = SerializeClarityValue(value); err != nil {
+				return ClarityValue{}, false, err
+			}
+		} else {
+			result.SerializedBytes = r.span(startPos)
 		}
-
-		return NewClarityValueWithBytes(allBytes, value), deserializeErr
 	}
 
-	return NewClarityValue(value), deserializeErr
+	return result, sanitized, nil
 }
@@ -0,0 +1,154 @@
+package clarity_value
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrDeserialize matches every *DeserializeError with errors.Is
+var ErrDeserialize = errors.New("failed to deserialize")
+
+// DeserializeErrorKind classifies the cause of a deserialization failure.
+// Kinds are errors themselves, so errors.Is(err, KindTruncated) matches any
+// *DeserializeError of that kind in err's chain.
+type DeserializeErrorKind uint8
+
+const (
+	KindInvalid        DeserializeErrorKind = iota // malformed data not covered by another kind
+	KindTruncated                                  // input ended in the middle of an item
+	KindBadPrefix                                  // unknown type prefix or type byte
+	KindTooDeep                                    // nesting exceeds MaxValueDepth
+	KindTooLarge                                   // length prefix exceeds the allowed size
+	KindInvalidName                                // malformed clarity or contract name
+	KindInvalidUTF8                                // string-utf8 holding invalid UTF-8
+	KindDuplicateField                             // tuple field serialized twice
+	KindTypeMismatch                               // value does not match the expected type
+	KindIO                                         // the underlying reader failed
+	KindTrailingBytes                              // input continues after the decoded item
+)
+
+var deserializeErrorKindNames = [...]string{
+	KindInvalid:        "invalid data",
+	KindTruncated:      "truncated",
+	KindBadPrefix:      "bad type prefix",
+	KindTooDeep:        "too deep",
+	KindTooLarge:       "too large",
+	KindInvalidName:    "invalid name",
+	KindInvalidUTF8:    "invalid utf-8",
+	KindDuplicateField: "duplicate field",
+	KindTypeMismatch:   "type mismatch",
+	KindIO:             "read error",
+	KindTrailingBytes:  "trailing bytes",
+}
+
+// String returns a short description of the kind
+func (k DeserializeErrorKind) String() string {
+	if int(k) < len(deserializeErrorKindNames) {
+		return deserializeErrorKindNames[k]
+	}
+	return fmt.Sprintf("kind %d", k)
+}
+
+func (k DeserializeErrorKind) Error() string {
+	return k.String()
+}
+
+// DeserializeError describes where and why deserialization failed
+type DeserializeError struct {
+	Kind DeserializeErrorKind
+	// Offset is the byte offset of the item that could not be decoded, or -1 if unknown
+	Offset int64
+	// Path names the item, e.g. "payload.contract_call.args[2].owner"
+	Path   string
+	Reason string
+	// Err is the underlying cause, such as io.ErrUnexpectedEOF or an *AdmissionError
+	Err error
+}
+
+func (e *DeserializeError) Error() string {
+	var b strings.Builder
+	b.WriteString("failed to deserialize")
+	if e.Path != "" {
+		b.WriteString(" ")
+		b.WriteString(e.Path)
+	}
+	if e.Offset >= 0 {
+		fmt.Fprintf(&b, " at offset %d", e.Offset)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Kind.String())
+	if e.Reason != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Reason)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying cause
+func (e *DeserializeError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrDeserialize and the error's own kind
+func (e *DeserializeError) Is(target error) bool {
+	if kind, ok := target.(DeserializeErrorKind); ok {
+		return e.Kind == kind
+	}
+	return target == ErrDeserialize
+}
+
+// NewDeserializeError creates a DeserializeError of kind KindInvalid at an unknown offset
+func NewDeserializeError(message string) *DeserializeError {
+	return &DeserializeError{Kind: KindInvalid, Offset: -1, Reason: message}
+}
+
+// DeserializeErrorf creates a DeserializeError of the given kind at offset
+func DeserializeErrorf(kind DeserializeErrorKind, offset int64, format string, args ...interface{}) *DeserializeError {
+	return &DeserializeError{Kind: kind, Offset: offset, Reason: fmt.Sprintf(format, args...)}
+}
+
+// WrapReadError turns an error from reading the item at offset into a *DeserializeError.
+// End of input becomes KindTruncated, and errors that already carry a
+// *DeserializeError are returned unchanged.
+func WrapReadError(err error, offset int64) error {
+	if err == nil {
+		return nil
+	}
+	var deserializeErr *DeserializeError
+	if errors.As(err, &deserializeErr) {
+		return err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &DeserializeError{Kind: KindTruncated, Offset: offset, Err: io.ErrUnexpectedEOF}
+	}
+	return &DeserializeError{Kind: KindIO, Offset: offset, Err: err}
+}
+
+// WithPathSegment prepends a path segment to the *DeserializeError and *AdmissionError
+// in err's chain as it propagates outwards. Segments starting with "." or "[" are
+// attached directly; others are joined with a dot.
+func WithPathSegment(err error, segment string) error {
+	var deserializeErr *DeserializeError
+	if errors.As(err, &deserializeErr) {
+		deserializeErr.Path = joinPath(segment, deserializeErr.Path)
+	}
+	var admissionErr *AdmissionError
+	if errors.As(err, &admissionErr) {
+		admissionErr.Path = joinPath(segment, admissionErr.Path)
+	}
+	return err
+}
+
+// joinPath joins a path segment with the rest of a path
+func joinPath(segment, path string) string {
+	if path == "" || path[0] == '.' || path[0] == '[' {
+		return segment + path
+	}
+	return segment + "." + path
+}
@@ -0,0 +1,132 @@
+package clarity_value
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Int128 represents a signed 128-bit integer in two's complement form
+type Int128 struct {
+	Hi int64
+	Lo uint64
+}
+
+// Uint128 represents an unsigned 128-bit integer
+type Uint128 struct {
+	Hi uint64
+	Lo uint64
+}
+
+var (
+	minInt128  = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	maxInt128  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	twoTo128   = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// Int128FromInt64 creates an Int128 from an int64
+func Int128FromInt64(v int64) Int128 {
+	hi := int64(0)
+	if v < 0 {
+		hi = -1
+	}
+	return Int128{Hi: hi, Lo: uint64(v)}
+}
+
+// Uint128FromUint64 creates a Uint128 from a uint64
+func Uint128FromUint64(v uint64) Uint128 {
+	return Uint128{Lo: v}
+}
+
+// Int128FromBig creates an Int128 from a big.Int, failing if it is out of range
+func Int128FromBig(b *big.Int) (Int128, error) {
+	if b.Cmp(minInt128) < 0 || b.Cmp(maxInt128) > 0 {
+		return Int128{}, fmt.Errorf("value out of range for int128: %s", b.String())
+	}
+	u := new(big.Int).Set(b)
+	if u.Sign() < 0 {
+		u.Add(u, twoTo128)
+	}
+	var buf [16]byte
+	u.FillBytes(buf[:])
+	return Int128FromBytes(buf), nil
+}
+
+// Uint128FromBig creates a Uint128 from a big.Int, failing if it is out of range
+func Uint128FromBig(b *big.Int) (Uint128, error) {
+	if b.Sign() < 0 || b.Cmp(maxUint128) > 0 {
+		return Uint128{}, fmt.Errorf("value out of range for uint128: %s", b.String())
+	}
+	var buf [16]byte
+	b.FillBytes(buf[:])
+	return Uint128FromBytes(buf), nil
+}
+
+// Int128FromBytes creates an Int128 from its 16-byte big-endian encoding
+func Int128FromBytes(buf [16]byte) Int128 {
+	return Int128{
+		Hi: int64(binary.BigEndian.Uint64(buf[:8])),
+		Lo: binary.BigEndian.Uint64(buf[8:]),
+	}
+}
+
+// Uint128FromBytes creates a Uint128 from its 16-byte big-endian encoding
+func Uint128FromBytes(buf [16]byte) Uint128 {
+	return Uint128{
+		Hi: binary.BigEndian.Uint64(buf[:8]),
+		Lo: binary.BigEndian.Uint64(buf[8:]),
+	}
+}
+
+// Bytes returns the 16-byte big-endian encoding of the Int128
+func (i Int128) Bytes() [16]byte {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(i.Hi))
+	binary.BigEndian.PutUint64(buf[8:], i.Lo)
+	return buf
+}
+
+// Bytes returns the 16-byte big-endian encoding of the Uint128
+func (u Uint128) Bytes() [16]byte {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], u.Hi)
+	binary.BigEndian.PutUint64(buf[8:], u.Lo)
+	return buf
+}
+
+// Big returns the Int128 as a newly allocated big.Int
+func (i Int128) Big() *big.Int {
+	buf := i.Bytes()
+	b := new(big.Int).SetBytes(buf[:])
+	if i.Hi < 0 {
+		b.Sub(b, twoTo128)
+	}
+	return b
+}
+
+// Big returns the Uint128 as a newly allocated big.Int
+func (u Uint128) Big() *big.Int {
+	buf := u.Bytes()
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// IsInt64 reports whether the Int128 can be represented as an int64
+func (i Int128) IsInt64() bool {
+	return (i.Hi == 0 && i.Lo < 1<<63) || (i.Hi == -1 && i.Lo >= 1<<63)
+}
+
+// IsUint64 reports whether the Uint128 can be represented as a uint64
+func (u Uint128) IsUint64() bool {
+	return u.Hi == 0
+}
+
+// String returns the decimal representation of the Int128
+func (i Int128) String() string {
+	return i.Big().String()
+}
+
+// String returns the decimal representation of the Uint128
+func (u Uint128) String() string {
+	return u.Big().String()
+}
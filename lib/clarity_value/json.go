@@ -0,0 +1,393 @@
+package clarity_value
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+// jsonValueOut is the {"type":...,"value":...} shape written for every Value,
+// matching the Stacks Blockchain API and stacks.js cvToJSON
+type jsonValueOut struct {
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+	Success *bool       `json:"success,omitempty"`
+}
+
+// jsonValueIn is the shape read back by ParseClarityJSON
+type jsonValueIn struct {
+	Type    string          `json:"type"`
+	Value   json.RawMessage `json:"value"`
+	Success *bool           `json:"success"`
+}
+
+// ParseClarityJSON decodes a Value from its {"type":...,"value":...} JSON encoding.
+//
+// The variant is chosen from the head of the type string; the rest of the type
+// string is not checked, so JSON from other producers with slightly different
+// type strings (e.g. "(optional none)") is accepted.
+func ParseClarityJSON(data []byte) (Value, error) {
+	return unmarshalJSONValue(data, 0)
+}
+
+// jsonType returns the type string stacks.js cvToJSON writes for v: its type
+// signature with string-utf8 lengths counting UTF-8 bytes
+func jsonType(v Value) string {
+	return typeSignature(v, utf8ByteLength)
+}
+
+// marshalJSONValue encodes a Value into its {"type":...,"value":...} JSON encoding
+func marshalJSONValue(v Value) ([]byte, error) {
+	out := jsonValueOut{Type: jsonType(v)}
+
+	switch v := v.(type) {
+	case IntValue:
+		out.Value = Int128(v).String()
+	case UIntValue:
+		out.Value = Uint128(v).String()
+	case BoolValue:
+		out.Value = bool(v)
+	case BufferValue:
+		out.Value = "0x" + hex.EncodeToString(v)
+	case StringASCIIValue:
+		out.Value = string(v)
+	case StringUTF8Value:
+		var data []byte
+		for _, c := range v {
+			data = append(data, c...)
+		}
+		out.Value = string(data)
+	case PrincipalStandardValue:
+		addr, err := address.EncodeC32Address(v.Version, v.Hash[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal clarity JSON: %w", err)
+		}
+		out.Value = addr
+	case PrincipalContractValue:
+		addr, err := address.EncodeC32Address(v.Issuer.Version, v.Issuer.Hash[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal clarity JSON: %w", err)
+		}
+		out.Value = addr + "." + string(v.Name)
+	case OptionalNoneValue:
+		out.Value = nil
+	case OptionalSomeValue:
+		out.Value = v.Value.Value
+	case ResponseOkValue:
+		success := true
+		out.Value = v.Value.Value
+		out.Success = &success
+	case ResponseErrValue:
+		success := false
+		out.Value = v.Value.Value
+		out.Success = &success
+	case ListValue:
+		list := make([]Value, len(v))
+		for i, item := range v {
+			list[i] = item.Value
+		}
+		out.Value = list
+	case TupleValue:
+		// encoding/json writes map keys in sorted order, matching the canonical tuple order
+		tuple := make(map[string]Value, len(v))
+		for name, item := range v {
+			tuple[string(name)] = item.Value
+		}
+		out.Value = tuple
+	default:
+		return nil, fmt.Errorf("failed to marshal clarity JSON: unsupported type %T", v)
+	}
+
+	return json.Marshal(out)
+}
+
+// unmarshalJSONValue handles the recursive decoding of a Value from JSON
+func unmarshalJSONValue(data []byte, depth int) (Value, error) {
+	if depth >= MaxValueDepth {
+		return nil, fmt.Errorf("failed to unmarshal clarity JSON: TypeSignatureTooDeep: %d", depth)
+	}
+
+	var in jsonValueIn
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal clarity JSON: %w", err)
+	}
+	fail := func(format string, args ...interface{}) (Value, error) {
+		return nil, fmt.Errorf("failed to unmarshal clarity JSON of type %q: %s", in.Type, fmt.Sprintf(format, args...))
+	}
+	if len(in.Value) == 0 {
+		return fail("missing value")
+	}
+
+	head := strings.TrimPrefix(in.Type, "(")
+	if i := strings.IndexByte(head, ' '); i >= 0 {
+		head = head[:i]
+	}
+
+	switch head {
+	case "int", "uint":
+		text, err := unmarshalJSONString(in.Value)
+		if err != nil {
+			// Integers are written as strings but plain JSON numbers are accepted
+			text = string(in.Value)
+		}
+		n, ok := new(big.Int).SetString(text, 10)
+		if !ok {
+			return fail("invalid integer %s", in.Value)
+		}
+		if head == "int" {
+			i, err := Int128FromBig(n)
+			if err != nil {
+				return fail("%s", err)
+			}
+			return IntValue(i), nil
+		}
+		u, err := Uint128FromBig(n)
+		if err != nil {
+			return fail("%s", err)
+		}
+		return UIntValue(u), nil
+
+	case "bool":
+		var b bool
+		if err := json.Unmarshal(in.Value, &b); err != nil {
+			return fail("%s", err)
+		}
+		return BoolValue(b), nil
+
+	case "buff":
+		text, err := unmarshalJSONString(in.Value)
+		if err != nil {
+			return fail("%s", err)
+		}
+		buf, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+		if err != nil {
+			return fail("invalid buffer: %s", err)
+		}
+		return BufferValue(buf), nil
+
+	case "string-ascii":
+		text, err := unmarshalJSONString(in.Value)
+		if err != nil {
+			return fail("%s", err)
+		}
+		for i := 0; i < len(text); i++ {
+			if text[i] >= 0x80 {
+				return fail("non-ASCII character at index %d", i)
+			}
+		}
+		return StringASCIIValue(text), nil
+
+	case "string-utf8":
+		text, err := unmarshalJSONString(in.Value)
+		if err != nil {
+			return fail("%s", err)
+		}
+		return NewStringUTF8Value([]byte(text)), nil
+
+	case "principal":
+		text, err := unmarshalJSONString(in.Value)
+		if err != nil {
+			return fail("%s", err)
+		}
+		value, err := parsePrincipalString(text)
+		if err != nil {
+			return fail("%s", err)
+		}
+		return value, nil
+
+	case "optional":
+		if bytes.Equal(in.Value, []byte("null")) {
+			return OptionalNoneValue{}, nil
+		}
+		inner, err := unmarshalJSONValue(in.Value, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return OptionalSomeValue{Value: NewClarityValue(inner)}, nil
+
+	case "response":
+		if in.Success == nil {
+			return fail("missing success")
+		}
+		inner, err := unmarshalJSONValue(in.Value, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if *in.Success {
+			return ResponseOkValue{Value: NewClarityValue(inner)}, nil
+		}
+		return ResponseErrValue{Value: NewClarityValue(inner)}, nil
+
+	case "list":
+		var items []json.RawMessage
+		if err := json.Unmarshal(in.Value, &items); err != nil {
+			return fail("%s", err)
+		}
+		list := make(ListValue, len(items))
+		for i, item := range items {
+			value, err := unmarshalJSONValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = NewClarityValue(value)
+		}
+		return list, nil
+
+	case "tuple":
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(in.Value, &items); err != nil {
+			return fail("%s", err)
+		}
+		tuple := make(TupleValue, len(items))
+		for key, item := range items {
+			name, err := ValidateClarityName(key)
+			if err != nil {
+				return fail("%s", err)
+			}
+			value, err := unmarshalJSONValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tuple[name] = NewClarityValue(value)
+		}
+		return tuple, nil
+
+	default:
+		return fail("unknown type")
+	}
+}
+
+// unmarshalJSONString decodes a JSON string
+func unmarshalJSONString(data json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// unmarshalJSONInto decodes a Value from JSON and stores it in dst if it has the variant of T
+func unmarshalJSONInto[T Value](data []byte, dst *T) error {
+	value, err := unmarshalJSONValue(data, 0)
+	if err != nil {
+		return err
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal clarity JSON: cannot store %s in %T", value.TypeSignature(), *dst)
+	}
+	*dst = typed
+	return nil
+}
+
+// MarshalJSON encodes the wrapped Value; serialized bytes are not included
+func (c ClarityValue) MarshalJSON() ([]byte, error) {
+	if c.Value == nil {
+		return []byte("null"), nil
+	}
+	return marshalJSONValue(c.Value)
+}
+
+// UnmarshalJSON decodes the wrapped Value, leaving SerializedBytes empty
+func (c *ClarityValue) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*c = ClarityValue{}
+		return nil
+	}
+	value, err := unmarshalJSONValue(data, 0)
+	if err != nil {
+		return err
+	}
+	*c = NewClarityValue(value)
+	return nil
+}
+
+// MarshalJSON encodes IntValue as {"type":"int","value":"<decimal>"}
+func (v IntValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes IntValue from its JSON encoding
+func (v *IntValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes UIntValue as {"type":"uint","value":"<decimal>"}
+func (v UIntValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes UIntValue from its JSON encoding
+func (v *UIntValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes BoolValue as {"type":"bool","value":<bool>}
+func (v BoolValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes BoolValue from its JSON encoding
+func (v *BoolValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes BufferValue as {"type":"(buff N)","value":"0x<hex>"}
+func (v BufferValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes BufferValue from its JSON encoding
+func (v *BufferValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes StringASCIIValue as {"type":"(string-ascii N)","value":"<string>"}
+func (v StringASCIIValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes StringASCIIValue from its JSON encoding
+func (v *StringASCIIValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes StringUTF8Value as {"type":"(string-utf8 N)","value":"<string>"},
+// where N is the UTF-8 byte length as in cvToJSON
+func (v StringUTF8Value) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes StringUTF8Value from its JSON encoding
+func (v *StringUTF8Value) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes PrincipalStandardValue as {"type":"principal","value":"<address>"}
+func (v PrincipalStandardValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes PrincipalStandardValue from its JSON encoding
+func (v *PrincipalStandardValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes PrincipalContractValue as {"type":"principal","value":"<address>.<name>"}
+func (v PrincipalContractValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes PrincipalContractValue from its JSON encoding
+func (v *PrincipalContractValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes OptionalNoneValue as {"type":"(optional UnknownType)","value":null}
+func (v OptionalNoneValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes OptionalNoneValue from its JSON encoding
+func (v *OptionalNoneValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes OptionalSomeValue as {"type":"(optional T)","value":<inner>}
+func (v OptionalSomeValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes OptionalSomeValue from its JSON encoding
+func (v *OptionalSomeValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes ResponseOkValue as {"type":"(response T UnknownType)","value":<inner>,"success":true}
+func (v ResponseOkValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes ResponseOkValue from its JSON encoding
+func (v *ResponseOkValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes ResponseErrValue as {"type":"(response UnknownType T)","value":<inner>,"success":false}
+func (v ResponseErrValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes ResponseErrValue from its JSON encoding
+func (v *ResponseErrValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes ListValue as {"type":"(list N T)","value":[<items>]}
+func (v ListValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes ListValue from its JSON encoding
+func (v *ListValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
+
+// MarshalJSON encodes TupleValue as {"type":"(tuple ...)","value":{<name>:<item>}}
+func (v TupleValue) MarshalJSON() ([]byte, error) { return marshalJSONValue(v) }
+
+// UnmarshalJSON decodes TupleValue from its JSON encoding
+func (v *TupleValue) UnmarshalJSON(data []byte) error { return unmarshalJSONInto(data, v) }
@@ -0,0 +1,627 @@
+package clarity_value
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Marshaler is implemented by types that can convert themselves into a Clarity Value
+type Marshaler interface {
+	MarshalClarity() (Value, error)
+}
+
+// Unmarshaler is implemented by types that can populate themselves from a Clarity Value
+type Unmarshaler interface {
+	UnmarshalClarity(Value) error
+}
+
+// UnmarshalTypeError describes a Clarity value that cannot be stored in a Go value
+type UnmarshalTypeError struct {
+	Path   string
+	Type   reflect.Type
+	Reason string
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("cannot unmarshal clarity value at %s into Go type %s: %s", e.Path, e.Type, e.Reason)
+}
+
+var (
+	valueType        = reflect.TypeOf((*Value)(nil)).Elem()
+	clarityValueType = reflect.TypeOf(ClarityValue{})
+	marshalerType    = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType  = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	bigIntType       = reflect.TypeOf(big.Int{})
+	int128Type       = reflect.TypeOf(Int128{})
+	uint128Type      = reflect.TypeOf(Uint128{})
+)
+
+// Marshal converts a Go value into a Clarity Value.
+//
+// Struct fields are encoded as tuple entries named by their `clarity:"name"` tag
+// (or the Go field name), and a tag of "-" skips the field. Pointers become
+// optionals (nil is none), []byte and [N]byte become buffers, other slices and
+// arrays become lists, maps with string keys become tuples, bools, ints and
+// uints map directly, and big.Int, Int128 and Uint128 become 128-bit integers.
+// Values implementing Value or Marshaler are used as-is.
+//
+// Tag options follow the name, separated by commas:
+//
+//	string-ascii  encode strings as string-ascii (the default)
+//	string-utf8   encode strings as string-utf8
+//	principal     encode strings as principals ('SP... or 'SP....contract)
+//	uint          encode big.Int as uint instead of int
+//	ok, err       wrap the field in (ok ...) or (err ...)
+//
+// Encoding options also apply to the elements of pointers, slices and maps.
+func Marshal(v interface{}) (Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() {
+		// Copy into an addressable value so pointer-receiver Marshalers are found
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+	return marshalValue(rv, fieldOptions{}, "value", 0)
+}
+
+// Unmarshal stores a Clarity Value into the Go value pointed to by dst, using the
+// same mapping and tag options as Marshal. Strings accept both string-ascii and
+// string-utf8, tuples may carry fields without a matching struct field, and the
+// ok and err options unwrap a response, failing if it is the other variant.
+func Unmarshal(v Value, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("failed to unmarshal clarity value: destination must be a non-nil pointer, got %T", dst)
+	}
+	return unmarshalValue(v, rv.Elem(), fieldOptions{}, "value")
+}
+
+// fieldOptions holds the options parsed from a `clarity` struct tag
+type fieldOptions struct {
+	stringUTF8 bool
+	principal  bool
+	unsigned   bool
+	ok         bool
+	err        bool
+}
+
+// structField describes how a struct field maps to a tuple entry
+type structField struct {
+	index int
+	name  ClarityName
+	opts  fieldOptions
+}
+
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// cachedStructFields returns the tuple mapping for a struct type, parsing its tags once
+func cachedStructFields(t reflect.Type) ([]structField, error) {
+	if fields, ok := structFieldCache.Load(t); ok {
+		return fields.([]structField), nil
+	}
+
+	var fields []structField
+	seen := make(map[ClarityName]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("clarity")
+		if tag == "-" {
+			continue
+		}
+
+		tagName, tagOptions, _ := strings.Cut(tag, ",")
+		if tagName == "" {
+			tagName = f.Name
+		}
+		name, err := ValidateClarityName(tagName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tuple field name for %s.%s: %w", t, f.Name, err)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tuple field name %s in %s", name, t)
+		}
+		seen[name] = true
+
+		opts, err := parseFieldOptions(tagOptions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clarity tag for %s.%s: %w", t, f.Name, err)
+		}
+		fields = append(fields, structField{index: i, name: name, opts: opts})
+	}
+
+	structFieldCache.Store(t, fields)
+	return fields, nil
+}
+
+// parseFieldOptions parses the comma-separated options following a tag name
+func parseFieldOptions(s string) (fieldOptions, error) {
+	var opts fieldOptions
+	if s == "" {
+		return opts, nil
+	}
+	for _, option := range strings.Split(s, ",") {
+		switch option {
+		case "string-ascii":
+			opts.stringUTF8 = false
+		case "string-utf8":
+			opts.stringUTF8 = true
+		case "principal":
+			opts.principal = true
+		case "uint":
+			opts.unsigned = true
+		case "ok":
+			opts.ok = true
+		case "err":
+			opts.err = true
+		default:
+			return opts, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	if opts.ok && opts.err {
+		return opts, fmt.Errorf("options ok and err are mutually exclusive")
+	}
+	return opts, nil
+}
+
+// marshalValue handles the recursive conversion of a Go value into a Value
+func marshalValue(rv reflect.Value, opts fieldOptions, path string, depth int) (Value, error) {
+	fail := func(format string, args ...interface{}) (Value, error) {
+		return nil, fmt.Errorf("failed to marshal clarity value at %s: %s", path, fmt.Sprintf(format, args...))
+	}
+	if depth >= MaxValueDepth {
+		return fail("TypeSignatureTooDeep: %d", depth)
+	}
+	if !rv.IsValid() {
+		return fail("nil value")
+	}
+
+	if opts.ok || opts.err {
+		inner, err := marshalValue(rv, fieldOptions{stringUTF8: opts.stringUTF8, principal: opts.principal, unsigned: opts.unsigned}, path, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if opts.ok {
+			return ResponseOkValue{Value: NewClarityValue(inner)}, nil
+		}
+		return ResponseErrValue{Value: NewClarityValue(inner)}, nil
+	}
+
+	// Pointers always map to optionals, so Marshaler is only consulted on the value itself
+	var marshaler Marshaler
+	if rv.Kind() != reflect.Ptr {
+		if rv.Type().Implements(marshalerType) {
+			marshaler = rv.Interface().(Marshaler)
+		} else if rv.CanAddr() && rv.Addr().Type().Implements(marshalerType) {
+			marshaler = rv.Addr().Interface().(Marshaler)
+		}
+	}
+	if marshaler != nil {
+		value, err := marshaler.MarshalClarity()
+		if err != nil {
+			return fail("%s", err)
+		}
+		if value == nil {
+			return fail("MarshalClarity returned nil")
+		}
+		return value, nil
+	}
+
+	switch rv.Type() {
+	case clarityValueType:
+		value := rv.Interface().(ClarityValue).Value
+		if value == nil {
+			return fail("nil value")
+		}
+		return value, nil
+	case bigIntType:
+		b := rv.Interface().(big.Int)
+		return marshalBigInt(&b, opts, fail)
+	case int128Type:
+		return IntValue(rv.Interface().(Int128)), nil
+	case uint128Type:
+		return UIntValue(rv.Interface().(Uint128)), nil
+	}
+	if rv.Kind() != reflect.Ptr && rv.Type().Implements(valueType) {
+		if rv.Kind() == reflect.Interface && rv.IsNil() {
+			return fail("nil value")
+		}
+		return rv.Interface().(Value), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return fail("nil value")
+		}
+		return marshalValue(rv.Elem(), opts, path, depth)
+
+	case reflect.Ptr:
+		if rv.Type().Elem() == bigIntType {
+			if rv.IsNil() {
+				return fail("nil *big.Int")
+			}
+			return marshalBigInt(rv.Interface().(*big.Int), opts, fail)
+		}
+		if rv.IsNil() {
+			return OptionalNoneValue{}, nil
+		}
+		inner, err := marshalValue(rv.Elem(), opts, path+".some", depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return OptionalSomeValue{Value: NewClarityValue(inner)}, nil
+
+	case reflect.Bool:
+		return BoolValue(rv.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewIntValue(rv.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NewUIntValue(rv.Uint()), nil
+
+	case reflect.String:
+		s := rv.String()
+		switch {
+		case opts.principal:
+			value, err := parsePrincipalString(s)
+			if err != nil {
+				return fail("%s", err)
+			}
+			return value, nil
+		case opts.stringUTF8:
+			if !utf8.ValidString(s) {
+				return fail("invalid UTF-8 in string-utf8")
+			}
+			return NewStringUTF8Value([]byte(s)), nil
+		default:
+			for i := 0; i < len(s); i++ {
+				if s[i] >= 0x80 {
+					return fail("non-ASCII character in string-ascii, use the string-utf8 option")
+				}
+			}
+			return StringASCIIValue(s), nil
+		}
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(buf), rv)
+			return BufferValue(buf), nil
+		}
+		list := make(ListValue, rv.Len())
+		for i := range list {
+			item, err := marshalValue(rv.Index(i), opts, fmt.Sprintf("%s[%d]", path, i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = NewClarityValue(item)
+		}
+		return list, nil
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fail("unsupported map key type %s", rv.Type().Key())
+		}
+		tuple := make(TupleValue, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			name, err := ValidateClarityName(iter.Key().String())
+			if err != nil {
+				return fail("%s", err)
+			}
+			item, err := marshalValue(iter.Value(), opts, path+"."+string(name), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tuple[name] = NewClarityValue(item)
+		}
+		return tuple, nil
+
+	case reflect.Struct:
+		fields, err := cachedStructFields(rv.Type())
+		if err != nil {
+			return fail("%s", err)
+		}
+		tuple := make(TupleValue, len(fields))
+		for _, f := range fields {
+			item, err := marshalValue(rv.Field(f.index), f.opts, path+"."+string(f.name), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			tuple[f.name] = NewClarityValue(item)
+		}
+		return tuple, nil
+
+	default:
+		return fail("unsupported type %s", rv.Type())
+	}
+}
+
+// marshalBigInt converts a big.Int into an int, or a uint when the uint option is set
+func marshalBigInt(b *big.Int, opts fieldOptions, fail func(string, ...interface{}) (Value, error)) (Value, error) {
+	if opts.unsigned {
+		u, err := Uint128FromBig(b)
+		if err != nil {
+			return fail("%s", err)
+		}
+		return UIntValue(u), nil
+	}
+	i, err := Int128FromBig(b)
+	if err != nil {
+		return fail("%s", err)
+	}
+	return IntValue(i), nil
+}
+
+// parsePrincipalString parses a standard or contract principal without the leading quote
+func parsePrincipalString(s string) (Value, error) {
+	value, err := ParseClarityLiteral("'" + s)
+	if err != nil {
+		return nil, err
+	}
+	switch value.(type) {
+	case PrincipalStandardValue, PrincipalContractValue:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("invalid principal %q", s)
+	}
+}
+
+// unmarshalValue handles the recursive conversion of a Value into a Go value
+func unmarshalValue(v Value, rv reflect.Value, opts fieldOptions, path string) error {
+	fail := func(format string, args ...interface{}) error {
+		return &UnmarshalTypeError{Path: path, Type: rv.Type(), Reason: fmt.Sprintf(format, args...)}
+	}
+	if v == nil {
+		return fail("missing value")
+	}
+
+	if opts.ok || opts.err {
+		inner := fieldOptions{stringUTF8: opts.stringUTF8, principal: opts.principal, unsigned: opts.unsigned}
+		switch response := v.(type) {
+		case ResponseOkValue:
+			if opts.err {
+				return fail("expected err response, got ok response")
+			}
+			return unmarshalValue(response.Value.Value, rv, inner, path+".ok")
+		case ResponseErrValue:
+			if opts.ok {
+				return fail("expected ok response, got err response")
+			}
+			return unmarshalValue(response.Value.Value, rv, inner, path+".err")
+		default:
+			return fail("expected response, got %s", v.TypeSignature())
+		}
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		return rv.Addr().Interface().(Unmarshaler).UnmarshalClarity(v)
+	}
+
+	switch rv.Type() {
+	case clarityValueType:
+		rv.Set(reflect.ValueOf(NewClarityValue(v)))
+		return nil
+	case valueType:
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case bigIntType:
+		switch n := v.(type) {
+		case IntValue:
+			rv.Set(reflect.ValueOf(n.Big()).Elem())
+		case UIntValue:
+			rv.Set(reflect.ValueOf(n.Big()).Elem())
+		default:
+			return fail("got %s", v.TypeSignature())
+		}
+		return nil
+	case int128Type:
+		n, ok := v.(IntValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		rv.Set(reflect.ValueOf(Int128(n)))
+		return nil
+	case uint128Type:
+		n, ok := v.(UIntValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		rv.Set(reflect.ValueOf(Uint128(n)))
+		return nil
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface && rv.Type().Implements(valueType) {
+		if reflect.TypeOf(v) != rv.Type() {
+			return fail("got %s", v.TypeSignature())
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return fail("unsupported interface type")
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+
+	case reflect.Ptr:
+		if rv.Type().Elem() == bigIntType {
+			ptr := reflect.New(bigIntType)
+			if err := unmarshalValue(v, ptr.Elem(), opts, path); err != nil {
+				return err
+			}
+			rv.Set(ptr)
+			return nil
+		}
+		switch optional := v.(type) {
+		case OptionalNoneValue:
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		case OptionalSomeValue:
+			ptr := reflect.New(rv.Type().Elem())
+			if err := unmarshalValue(optional.Value.Value, ptr.Elem(), opts, path+".some"); err != nil {
+				return err
+			}
+			rv.Set(ptr)
+			return nil
+		default:
+			return fail("expected optional, got %s", v.TypeSignature())
+		}
+
+	case reflect.Bool:
+		b, ok := v.(BoolValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		rv.SetBool(bool(b))
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := v.(IntValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if !Int128(n).IsInt64() || rv.OverflowInt(int64(n.Lo)) {
+			return fail("value %s overflows", n.ReprString())
+		}
+		rv.SetInt(int64(n.Lo))
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := v.(UIntValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if !Uint128(n).IsUint64() || rv.OverflowUint(n.Lo) {
+			return fail("value %s overflows", n.ReprString())
+		}
+		rv.SetUint(n.Lo)
+		return nil
+
+	case reflect.String:
+		if opts.principal {
+			switch v.(type) {
+			case PrincipalStandardValue, PrincipalContractValue:
+				rv.SetString(strings.TrimPrefix(v.ReprString(), "'"))
+				return nil
+			default:
+				return fail("expected principal, got %s", v.TypeSignature())
+			}
+		}
+		switch s := v.(type) {
+		case StringASCIIValue:
+			rv.SetString(string(s))
+		case StringUTF8Value:
+			var sb strings.Builder
+			for _, c := range s {
+				sb.Write(c)
+			}
+			rv.SetString(sb.String())
+		default:
+			return fail("got %s", v.TypeSignature())
+		}
+		return nil
+
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			buf, ok := v.(BufferValue)
+			if !ok {
+				return fail("got %s", v.TypeSignature())
+			}
+			rv.SetBytes(append([]byte{}, buf...))
+			return nil
+		}
+		list, ok := v.(ListValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := unmarshalValue(item.Value, slice.Index(i), opts, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			buf, ok := v.(BufferValue)
+			if !ok {
+				return fail("got %s", v.TypeSignature())
+			}
+			if len(buf) != rv.Len() {
+				return fail("buffer length %d does not match array length %d", len(buf), rv.Len())
+			}
+			reflect.Copy(rv, reflect.ValueOf([]byte(buf)))
+			return nil
+		}
+		list, ok := v.(ListValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if len(list) != rv.Len() {
+			return fail("list length %d does not match array length %d", len(list), rv.Len())
+		}
+		for i, item := range list {
+			if err := unmarshalValue(item.Value, rv.Index(i), opts, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fail("unsupported map key type %s", rv.Type().Key())
+		}
+		tuple, ok := v.(TupleValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), len(tuple))
+		for name, item := range tuple {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := unmarshalValue(item.Value, elem, opts, path+"."+string(name)); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(string(name)).Convert(rv.Type().Key()), elem)
+		}
+		rv.Set(m)
+		return nil
+
+	case reflect.Struct:
+		tuple, ok := v.(TupleValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		fields, err := cachedStructFields(rv.Type())
+		if err != nil {
+			return fail("%s", err)
+		}
+		for _, f := range fields {
+			item, ok := tuple[f.name]
+			if !ok {
+				return fail("missing tuple field %s", f.name)
+			}
+			if err := unmarshalValue(item.Value, rv.Field(f.index), f.opts, path+"."+string(f.name)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fail("unsupported type")
+	}
+}
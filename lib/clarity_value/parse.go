@@ -0,0 +1,463 @@
+package clarity_value
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+// ParseClarityLiteral parses a Clarity literal expression (as produced by ReprString) into a Value.
+//
+// Supported forms are ints (`-5`), uints (`u5`), buffers (`0x0102`), booleans,
+// ASCII strings (`"a\n"`), UTF-8 strings (`u"\u{1F600}"`), standard and contract
+// principals (`'SP...`, `'SP....name`), `none`, `(some x)`, `(ok x)`, `(err x)`,
+// `(list ...)`, `(tuple (k v) ...)` and `{k: v, ...}`.
+//
+// Inside UTF-8 strings, a `\u{...}` escape whose hex digits form a single
+// multi-byte UTF-8 sequence (the ReprString encoding) is taken as those bytes;
+// otherwise the digits are read as a Unicode code point.
+func ParseClarityLiteral(s string) (Value, error) {
+	p := &literalParser{input: s, what: "clarity literal"}
+	value, err := p.parseValue(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected trailing input %q", p.input[p.pos:])
+	}
+	return value, nil
+}
+
+// literalParser is a recursive descent parser over Clarity literal syntax
+type literalParser struct {
+	input string
+	pos   int
+	what  string
+}
+
+func (p *literalParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("failed to parse %s at offset %d: %s", p.what, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *literalParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *literalParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *literalParser) skipWhitespace() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *literalParser) expect(c byte) error {
+	p.skipWhitespace()
+	if p.eof() {
+		return p.errorf("expected '%c', got end of input", c)
+	}
+	if p.peek() != c {
+		return p.errorf("expected '%c', got '%c'", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// isDelimiter reports whether c terminates an atom
+func isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '(', ')', '{', '}', ':', ',', '"':
+		return true
+	}
+	return false
+}
+
+// readAtom reads characters up to the next delimiter
+func (p *literalParser) readAtom() string {
+	start := p.pos
+	for !p.eof() && !isDelimiter(p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *literalParser) parseValue(depth int) (Value, error) {
+	if depth >= MaxValueDepth {
+		return nil, p.errorf("TypeSignatureTooDeep: %d", depth)
+	}
+
+	p.skipWhitespace()
+	if p.eof() {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.peek(); {
+	case c == '(':
+		return p.parseForm(depth)
+	case c == '{':
+		return p.parseMap(depth)
+	case c == '"':
+		return p.parseASCIIString()
+	case c == 'u' && p.pos+1 < len(p.input) && p.input[p.pos+1] == '"':
+		p.pos++
+		return p.parseUTF8String()
+	case c == '\'':
+		return p.parsePrincipal()
+	}
+
+	start := p.pos
+	atom := p.readAtom()
+	if atom == "" {
+		return nil, p.errorf("unexpected '%c'", p.peek())
+	}
+
+	switch {
+	case atom == "true":
+		return BoolValue(true), nil
+	case atom == "false":
+		return BoolValue(false), nil
+	case atom == "none":
+		return OptionalNoneValue{}, nil
+	case strings.HasPrefix(atom, "0x"):
+		data, err := hex.DecodeString(atom[2:])
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid buffer literal %q", atom)
+		}
+		return BufferValue(data), nil
+	case strings.HasPrefix(atom, "u"):
+		n, ok := parseDecimal(atom[1:])
+		if !ok {
+			p.pos = start
+			return nil, p.errorf("invalid uint literal %q", atom)
+		}
+		u, err := Uint128FromBig(n)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("%s", err)
+		}
+		return UIntValue(u), nil
+	default:
+		n, ok := parseDecimal(strings.TrimPrefix(atom, "-"))
+		if !ok {
+			p.pos = start
+			return nil, p.errorf("unrecognized literal %q", atom)
+		}
+		if strings.HasPrefix(atom, "-") {
+			n.Neg(n)
+		}
+		i, err := Int128FromBig(n)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("%s", err)
+		}
+		return IntValue(i), nil
+	}
+}
+
+// parseDecimal parses a non-empty string of ASCII digits
+func parseDecimal(s string) (*big.Int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil, false
+		}
+	}
+	return new(big.Int).SetString(s, 10)
+}
+
+// parseForm parses a parenthesized expression such as (list ...) or (some ...)
+func (p *literalParser) parseForm(depth int) (Value, error) {
+	p.pos++ // '('
+	p.skipWhitespace()
+	start := p.pos
+	keyword := p.readAtom()
+
+	var value Value
+	switch keyword {
+	case "list":
+		items := make(ListValue, 0)
+		for {
+			p.skipWhitespace()
+			if p.eof() || p.peek() == ')' {
+				break
+			}
+			item, err := p.parseValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, NewClarityValue(item))
+		}
+		value = items
+
+	case "tuple":
+		data := make(TupleValue)
+		for {
+			p.skipWhitespace()
+			if p.eof() || p.peek() == ')' {
+				break
+			}
+			if err := p.expect('('); err != nil {
+				return nil, err
+			}
+			if err := p.parseTupleEntry(data, depth); err != nil {
+				return nil, err
+			}
+			if err := p.expect(')'); err != nil {
+				return nil, err
+			}
+		}
+		value = data
+
+	case "some", "ok", "err":
+		inner, err := p.parseValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch keyword {
+		case "some":
+			value = OptionalSomeValue{Value: NewClarityValue(inner)}
+		case "ok":
+			value = ResponseOkValue{Value: NewClarityValue(inner)}
+		default:
+			value = ResponseErrValue{Value: NewClarityValue(inner)}
+		}
+
+	default:
+		p.pos = start
+		return nil, p.errorf("unknown expression %q", keyword)
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// parseMap parses the {key: value, ...} tuple syntax
+func (p *literalParser) parseMap(depth int) (Value, error) {
+	p.pos++ // '{'
+	data := make(TupleValue)
+	for {
+		p.skipWhitespace()
+		if p.eof() {
+			return nil, p.errorf("expected '}', got end of input")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return data, nil
+		}
+		if err := p.parseTupleEntry(data, depth); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+// parseTupleEntry parses a single `name value` or `name: value` tuple entry
+func (p *literalParser) parseTupleEntry(data TupleValue, depth int) error {
+	p.skipWhitespace()
+	start := p.pos
+	name, err := ValidateClarityName(p.readAtom())
+	if err != nil {
+		p.pos = start
+		return p.errorf("%s", err)
+	}
+	if _, exists := data[name]; exists {
+		p.pos = start
+		return p.errorf("duplicate tuple field: %s", name)
+	}
+	p.skipWhitespace()
+	if !p.eof() && p.peek() == ':' {
+		p.pos++
+	}
+	value, err := p.parseValue(depth + 1)
+	if err != nil {
+		return err
+	}
+	data[name] = NewClarityValue(value)
+	return nil
+}
+
+// parsePrincipal parses a 'SP... or 'SP....contract-name literal
+func (p *literalParser) parsePrincipal() (Value, error) {
+	p.pos++ // '\''
+	start := p.pos
+	atom := p.readAtom()
+
+	addr, name, isContract := strings.Cut(atom, ".")
+	version, hash, err := address.DecodeC32Address(addr)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid principal %q: %s", atom, err)
+	}
+	if len(hash) != 20 {
+		p.pos = start
+		return nil, p.errorf("invalid principal %q: hash length %d", atom, len(hash))
+	}
+
+	principal := StandardPrincipalData{Version: version}
+	copy(principal.Hash[:], hash)
+
+	if !isContract {
+		return PrincipalStandardValue(principal), nil
+	}
+
+	contractName, err := ValidateContractName(name)
+	if err != nil || len(contractName) > ContractMaxNameLength {
+		p.pos = start
+		return nil, p.errorf("invalid contract name %q", name)
+	}
+	return PrincipalContractValue(QualifiedContractIdentifier{
+		Issuer: principal,
+		Name:   ClarityName(contractName),
+	}), nil
+}
+
+// parseASCIIString parses a double-quoted ASCII string literal
+func (p *literalParser) parseASCIIString() (Value, error) {
+	data, err := p.parseQuoted(false)
+	if err != nil {
+		return nil, err
+	}
+	return StringASCIIValue(data), nil
+}
+
+// parseUTF8String parses a u"..." UTF-8 string literal
+func (p *literalParser) parseUTF8String() (Value, error) {
+	data, err := p.parseQuoted(true)
+	if err != nil {
+		return nil, err
+	}
+	return NewStringUTF8Value(data), nil
+}
+
+// parseQuoted reads a double-quoted string body, resolving escape sequences
+func (p *literalParser) parseQuoted(utf8Allowed bool) ([]byte, error) {
+	p.pos++ // '"'
+	data := make([]byte, 0)
+	for {
+		if p.eof() {
+			return nil, p.errorf("unterminated string literal")
+		}
+		c := p.peek()
+		switch {
+		case c == '"':
+			p.pos++
+			return data, nil
+
+		case c == '\\':
+			p.pos++
+			if p.eof() {
+				return nil, p.errorf("unterminated escape sequence")
+			}
+			esc := p.peek()
+			p.pos++
+			switch esc {
+			case 'n':
+				data = append(data, '\n')
+			case 't':
+				data = append(data, '\t')
+			case 'r':
+				data = append(data, '\r')
+			case 'a':
+				data = append(data, '\a')
+			case 'b':
+				data = append(data, '\b')
+			case 'f':
+				data = append(data, '\f')
+			case 'v':
+				data = append(data, '\v')
+			case '0':
+				data = append(data, 0)
+			case '\\', '"', '\'':
+				data = append(data, esc)
+			case 'x':
+				if p.pos+2 > len(p.input) {
+					return nil, p.errorf("invalid \\x escape")
+				}
+				b, err := strconv.ParseUint(p.input[p.pos:p.pos+2], 16, 8)
+				if err != nil {
+					return nil, p.errorf("invalid \\x escape")
+				}
+				p.pos += 2
+				data = append(data, byte(b))
+			case 'u':
+				if !utf8Allowed {
+					return nil, p.errorf("\\u escape not allowed in ASCII string")
+				}
+				char, err := p.parseUnicodeEscape()
+				if err != nil {
+					return nil, err
+				}
+				data = append(data, char...)
+			default:
+				p.pos--
+				return nil, p.errorf("unknown escape sequence '\\%c'", esc)
+			}
+
+		case c >= 0x80:
+			if !utf8Allowed {
+				return nil, p.errorf("non-ASCII character in ASCII string")
+			}
+			r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+			if r == utf8.RuneError {
+				return nil, p.errorf("invalid UTF-8 encoding")
+			}
+			data = append(data, p.input[p.pos:p.pos+size]...)
+			p.pos += size
+
+		default:
+			data = append(data, c)
+			p.pos++
+		}
+	}
+}
+
+// parseUnicodeEscape parses the {...} part of a \u{...} escape
+func (p *literalParser) parseUnicodeEscape() ([]byte, error) {
+	if p.eof() || p.peek() != '{' {
+		return nil, p.errorf("expected '{' after \\u")
+	}
+	end := strings.IndexByte(p.input[p.pos:], '}')
+	if end < 0 {
+		return nil, p.errorf("unterminated \\u escape")
+	}
+	digits := p.input[p.pos+1 : p.pos+end]
+	if digits == "" || len(digits) > 8 {
+		return nil, p.errorf("invalid \\u escape %q", digits)
+	}
+
+	// ReprString renders extended characters as the hex of their UTF-8 bytes
+	if raw, err := hex.DecodeString(digits); err == nil && len(raw) > 1 {
+		if r, size := utf8.DecodeRune(raw); r != utf8.RuneError && size == len(raw) {
+			p.pos += end + 1
+			return raw, nil
+		}
+	}
+
+	codePoint, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(codePoint)) {
+		return nil, p.errorf("invalid \\u escape %q", digits)
+	}
+	p.pos += end + 1
+	return utf8.AppendRune(nil, rune(codePoint)), nil
+}
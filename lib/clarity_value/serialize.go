@@ -0,0 +1,174 @@
+package clarity_value
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// SerializeClarityValue serializes a Value into the consensus wire format
+func SerializeClarityValue(value Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteClarityValue(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteClarityValue serializes a Value into the consensus wire format and writes it to w
+func WriteClarityValue(w io.Writer, value Value) error {
+	return writeClarityValueInternal(w, value, 0)
+}
+
+// WriteClarityName serializes a ClarityName as a length-prefixed string
+func WriteClarityName(w io.Writer, name ClarityName) error {
+	if len(name) > MaxStringLen {
+		return fmt.Errorf("failed to serialize clarity name: too long: %d", len(name))
+	}
+	return writeShortString(w, string(name))
+}
+
+// WriteContractName serializes a ContractName as a length-prefixed string
+func WriteContractName(w io.Writer, name ContractName) error {
+	if len(name) < ContractMinNameLength || len(name) > ContractMaxNameLength {
+		return fmt.Errorf("failed to serialize contract name: too short or too long: %d", len(name))
+	}
+	return writeShortString(w, string(name))
+}
+
+// WriteStandardPrincipalData serializes a StandardPrincipalData as version byte followed by hash
+func WriteStandardPrincipalData(w io.Writer, principal StandardPrincipalData) error {
+	if _, err := w.Write([]byte{principal.Version}); err != nil {
+		return err
+	}
+	_, err := w.Write(principal.Hash[:])
+	return err
+}
+
+// writeClarityValueInternal handles the recursive serialization of Value
+func writeClarityValueInternal(w io.Writer, value Value, depth uint8) error {
+	if value == nil {
+		return fmt.Errorf("failed to serialize clarity value: nil value")
+	}
+	if depth >= MaxValueDepth {
+		return fmt.Errorf("failed to serialize clarity value: TypeSignatureTooDeep: %d", depth)
+	}
+
+	if _, err := w.Write([]byte{byte(value.TypePrefix())}); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case IntValue:
+		buf := Int128(v).Bytes()
+		_, err := w.Write(buf[:])
+		return err
+
+	case UIntValue:
+		buf := Uint128(v).Bytes()
+		_, err := w.Write(buf[:])
+		return err
+
+	case BufferValue:
+		return writeLengthPrefixed(w, v)
+
+	case BoolValue, OptionalNoneValue:
+		return nil
+
+	case PrincipalStandardValue:
+		return WriteStandardPrincipalData(w, StandardPrincipalData(v))
+
+	case PrincipalContractValue:
+		if err := WriteStandardPrincipalData(w, v.Issuer); err != nil {
+			return err
+		}
+		return WriteClarityName(w, v.Name)
+
+	case ResponseOkValue:
+		return writeClarityValueInternal(w, v.Value.Value, depth+1)
+
+	case ResponseErrValue:
+		return writeClarityValueInternal(w, v.Value.Value, depth+1)
+
+	case OptionalSomeValue:
+		return writeClarityValueInternal(w, v.Value.Value, depth+1)
+
+	case ListValue:
+		if err := writeLength(w, len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := writeClarityValueInternal(w, item.Value, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case TupleValue:
+		if err := writeLength(w, len(v)); err != nil {
+			return err
+		}
+		// Tuple keys are serialized in canonical (sorted) order
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, string(key))
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			clarityKey := ClarityName(key)
+			if err := WriteClarityName(w, clarityKey); err != nil {
+				return err
+			}
+			if err := writeClarityValueInternal(w, v[clarityKey].Value, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case StringASCIIValue:
+		return writeLengthPrefixed(w, v)
+
+	case StringUTF8Value:
+		var data []byte
+		for _, c := range v {
+			data = append(data, c...)
+		}
+		return writeLengthPrefixed(w, data)
+
+	default:
+		return fmt.Errorf("failed to serialize clarity value: unsupported type %T", value)
+	}
+}
+
+// writeLength writes a 4-byte big-endian length prefix
+func writeLength(w io.Writer, length int) error {
+	if length > math.MaxUint32 {
+		return fmt.Errorf("failed to serialize clarity value: length too large: %d", length)
+	}
+	return binary.Write(w, binary.BigEndian, uint32(length))
+}
+
+// writeLengthPrefixed writes data preceded by its 4-byte big-endian length
+func writeLengthPrefixed(w io.Writer, data []byte) error {
+	if err := writeLength(w, len(data)); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// writeShortString writes a string preceded by its 1-byte length
+func writeShortString(w io.Writer, s string) error {
+	if len(s) > math.MaxUint8 {
+		return fmt.Errorf("failed to serialize string: too long: %d", len(s))
+	}
+	if _, err := w.Write([]byte{byte(len(s))}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
@@ -0,0 +1,400 @@
+package clarity_value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Decoder reads consecutive Clarity values from an io.Reader.
+//
+// The decoder never reads past the end of the value being decoded, so the
+// underlying reader can be handed on to other decoders afterwards. Readers
+// that do not implement io.ByteReader are read one byte at a time for
+// single-byte fields; wrap them in a bufio.Reader when that matters.
+//
+// When serialized bytes are requested, each top-level value is first read
+// into a single buffer, and the SerializedBytes of the value and of every
+// nested value are sub-slices of that buffer. Set DecodeOptions.CopyBytes
+// to give each value its own copy instead. Each value, nested or not, also
+// records the Offset of its first byte in the stream.
+type Decoder struct {
+	r *decodeReader
+}
+
+// NewDecoder creates a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: newDecodeReader(r)}
+}
+
+// Decode reads the next ClarityValue. With withBytes set, the serialized bytes of
+// the value and of every nested value are captured while reading.
+func (d *Decoder) Decode(withBytes bool) (ClarityValue, error) {
+	return d.decode(nil, DecodeOptions{WithBytes: withBytes})
+}
+
+// DecodeWithOptions reads the next ClarityValue without type checking, honouring
+// the WithBytes and CopyBytes options
+func (d *Decoder) DecodeWithOptions(opts DecodeOptions) (ClarityValue, error) {
+	return d.decode(nil, opts)
+}
+
+// DecodeWithType reads the next ClarityValue, enforcing the expected type as
+// DecodeClarityValueWithType does
+func (d *Decoder) DecodeWithType(expected TypeSignature, opts DecodeOptions) (ClarityValue, error) {
+	if expected == nil {
+		return ClarityValue{}, NewDeserializeError("expected type must not be nil")
+	}
+	return d.decode(expected, opts)
+}
+
+// Offset returns the byte offset of the next value in the stream. It counts from
+// the offset of r when r is an OffsetReader, and from zero otherwise.
+func (d *Decoder) Offset() int64 {
+	return d.r.offset
+}
+
+// decode reads the next value, going through an in-memory copy of its bytes when they are captured.
+// Errors are *DeserializeErrors whose path starts at opts.Path; a stream that ends before the
+// first byte of the value fails with KindTruncated wrapping io.EOF.
+func (d *Decoder) decode(expected TypeSignature, opts DecodeOptions) (ClarityValue, error) {
+	start := d.r.offset
+	value, err := d.decodeValue(expected, opts)
+	if err != nil {
+		var deserializeErr *DeserializeError
+		if errors.As(err, &deserializeErr) && deserializeErr.Kind == KindTruncated && d.r.offset == start {
+			deserializeErr.Err = io.EOF
+		}
+		root := opts.Path
+		if root == "" {
+			root = "value"
+		}
+		return ClarityValue{}, WithPathSegment(err, root)
+	}
+	return value, nil
+}
+
+func (d *Decoder) decodeValue(expected TypeSignature, opts DecodeOptions) (ClarityValue, error) {
+	if !opts.WithBytes {
+		value, _, err := decodeClarityValueInternal(d.r, 0, false, expected, opts.Sanitize)
+		return value, err
+	}
+
+	start := d.r.offset
+	d.r.recording = true
+	err := scanClarityValue(d.r, 0)
+	data := d.r.record
+	d.r.recording = false
+	d.r.record = nil
+	if err != nil {
+		return ClarityValue{}, err
+	}
+
+	mem := &decodeReader{data: data, offset: start, base: start, copyBytes: opts.CopyBytes}
+	value, _, err := decodeClarityValueInternal(mem, 0, true, expected, opts.Sanitize)
+	return value, err
+}
+
+// OffsetReader is a reader that knows the byte offset of its next byte in the
+// input, so that decoders reading from it can report absolute error offsets
+type OffsetReader interface {
+	io.Reader
+	InputOffset() int64
+}
+
+// NewOffsetReader returns an OffsetReader reading from r, whose next byte is at
+// offset in the input. Readers that already are OffsetReaders are returned unchanged.
+func NewOffsetReader(r io.Reader, offset int64) OffsetReader {
+	if or, ok := r.(OffsetReader); ok {
+		return or
+	}
+	byteReader, _ := r.(io.ByteReader)
+	return &offsetReader{r: r, byteReader: byteReader, offset: offset}
+}
+
+// InputOffset returns the offset of the next byte of r, or -1 if r is not an OffsetReader
+func InputOffset(r io.Reader) int64 {
+	if or, ok := r.(OffsetReader); ok {
+		return or.InputOffset()
+	}
+	return -1
+}
+
+type offsetReader struct {
+	r          io.Reader
+	byteReader io.ByteReader
+	offset     int64
+}
+
+func (o *offsetReader) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *offsetReader) ReadByte() (byte, error) {
+	var b byte
+	if o.byteReader != nil {
+		var err error
+		if b, err = o.byteReader.ReadByte(); err != nil {
+			return 0, err
+		}
+	} else {
+		var buf [1]byte
+		if _, err := io.ReadFull(o.r, buf[:]); err != nil {
+			return 0, err
+		}
+		b = buf[0]
+	}
+	o.offset++
+	return b, nil
+}
+
+func (o *offsetReader) InputOffset() int64 {
+	return o.offset
+}
+
+// decodeReader reads either from a stream or from an in-memory buffer, tracking
+// the offset in the stream. While recording, stream bytes are also appended to record.
+type decodeReader struct {
+	// Stream source
+	r          io.Reader
+	byteReader io.ByteReader
+	recording  bool
+	record     []byte
+
+	// In-memory source, used when data is non-nil; data[0] is at offset base
+	data      []byte
+	base      int64
+	copyBytes bool
+
+	offset int64
+
+	// Scratch space for fixed-size reads, kept here so it does not escape per call
+	scratch [16]byte
+}
+
+func newDecodeReader(r io.Reader) *decodeReader {
+	byteReader, _ := r.(io.ByteReader)
+	var offset int64
+	if or, ok := r.(OffsetReader); ok {
+		offset = or.InputOffset()
+	}
+	return &decodeReader{r: r, byteReader: byteReader, offset: offset}
+}
+
+func (d *decodeReader) InputOffset() int64 {
+	return d.offset
+}
+
+func (d *decodeReader) Read(p []byte) (int, error) {
+	if d.data != nil {
+		pos := d.offset - d.base
+		if pos >= int64(len(d.data)) {
+			return 0, io.EOF
+		}
+		n := copy(p, d.data[pos:])
+		d.offset += int64(n)
+		return n, nil
+	}
+
+	n, err := d.r.Read(p)
+	d.offset += int64(n)
+	if d.recording {
+		d.record = append(d.record, p[:n]...)
+	}
+	return n, err
+}
+
+func (d *decodeReader) ReadByte() (byte, error) {
+	if d.data != nil {
+		pos := d.offset - d.base
+		if pos >= int64(len(d.data)) {
+			return 0, io.EOF
+		}
+		d.offset++
+		return d.data[pos], nil
+	}
+
+	var b byte
+	if d.byteReader != nil {
+		var err error
+		if b, err = d.byteReader.ReadByte(); err != nil {
+			return 0, err
+		}
+	} else {
+		if _, err := io.ReadFull(d.r, d.scratch[:1]); err != nil {
+			return 0, err
+		}
+		b = d.scratch[0]
+	}
+	d.offset++
+	if d.recording {
+		d.record = append(d.record, b)
+	}
+	return b, nil
+}
+
+// readUint32 reads a 4-byte big-endian length prefix
+func (d *decodeReader) readUint32() (uint32, error) {
+	if _, err := io.ReadFull(d, d.scratch[:4]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(d.scratch[:4]), nil
+}
+
+// read128 reads the 16 bytes of a 128-bit integer
+func (d *decodeReader) read128() ([16]byte, error) {
+	if _, err := io.ReadFull(d, d.scratch[:]); err != nil {
+		return [16]byte{}, err
+	}
+	return d.scratch, nil
+}
+
+// skip consumes n bytes of a recorded stream, reading them straight into the record
+func (d *decodeReader) skip(n int) error {
+	start := len(d.record)
+	d.record = slices.Grow(d.record, n)[:start+n]
+	read, err := io.ReadFull(d.r, d.record[start:])
+	d.offset += int64(read)
+	d.record = d.record[:start+read]
+	if errors.Is(err, io.EOF) && read > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// span returns the bytes read since offset start from an in-memory source.
+// The result aliases the source buffer unless copyBytes is set.
+func (d *decodeReader) span(start int64) []byte {
+	from, to := start-d.base, d.offset-d.base
+	if d.copyBytes {
+		return append([]byte(nil), d.data[from:to]...)
+	}
+	return d.data[from:to:to]
+}
+
+// scanClarityValue reads one serialized value from a recording stream without
+// building it, checking only the structure needed to find where it ends
+func scanClarityValue(r *decodeReader, depth uint8) error {
+	if depth >= MaxValueDepth {
+		return DeserializeErrorf(KindTooDeep, r.offset, "depth %d exceeds %d", depth, MaxValueDepth)
+	}
+
+	start := r.offset
+	header, err := r.ReadByte()
+	if err != nil {
+		return WrapReadError(err, start)
+	}
+
+	switch TypePrefix(header) {
+	case PrefixInt, PrefixUInt:
+		return WrapReadError(r.skip(16), start)
+
+	case PrefixBoolTrue, PrefixBoolFalse, PrefixOptionalNone:
+		return nil
+
+	case PrefixPrincipalStandard:
+		return WrapReadError(r.skip(21), start)
+
+	case PrefixPrincipalContract:
+		if err := r.skip(21); err != nil {
+			return WrapReadError(err, start)
+		}
+		_, err := scanClarityName(r)
+		return err
+
+	case PrefixResponseOk:
+		return WithPathSegment(scanClarityValue(r, depth+1), ".ok")
+
+	case PrefixResponseErr:
+		return WithPathSegment(scanClarityValue(r, depth+1), ".err")
+
+	case PrefixOptionalSome:
+		return WithPathSegment(scanClarityValue(r, depth+1), ".some")
+
+	case PrefixBuffer, PrefixStringASCII, PrefixStringUTF8:
+		length, err := r.readUint32()
+		if err != nil {
+			return WrapReadError(err, start)
+		}
+		if length > MaxValueSize {
+			return DeserializeErrorf(KindTooLarge, start, "%s length %d exceeds %d", scanTypeName(TypePrefix(header)), length, MaxValueSize)
+		}
+		return WrapReadError(r.skip(int(length)), start)
+
+	case PrefixList, PrefixTuple:
+		length, err := r.readUint32()
+		if err != nil {
+			return WrapReadError(err, start)
+		}
+		if length > MaxValueSize {
+			return DeserializeErrorf(KindTooLarge, start, "%s length %d exceeds %d", scanTypeName(TypePrefix(header)), length, MaxValueSize)
+		}
+		for i := uint32(0); i < length; i++ {
+			segment := fmt.Sprintf("[%d]", i)
+			if TypePrefix(header) == PrefixTuple {
+				name, err := scanClarityName(r)
+				if err != nil {
+					return err
+				}
+				segment = "." + name
+			}
+			if err := scanClarityValue(r, depth+1); err != nil {
+				return WithPathSegment(err, segment)
+			}
+		}
+		return nil
+
+	default:
+		return DeserializeErrorf(KindBadPrefix, start, "%d", header)
+	}
+}
+
+// scanClarityName reads a length-prefixed ClarityName from a recording stream,
+// returning the raw name for use in error paths
+func scanClarityName(r *decodeReader) (string, error) {
+	start := r.offset
+	length, err := r.ReadByte()
+	if err != nil {
+		return "", WrapReadError(err, start)
+	}
+	if length > MaxStringLen {
+		return "", DeserializeErrorf(KindInvalidName, start, "clarity name too long: %d", length)
+	}
+	nameStart := len(r.record)
+	if err := r.skip(int(length)); err != nil {
+		return "", WrapReadError(err, start)
+	}
+	return string(r.record[nameStart:]), nil
+}
+
+// scanTypeName names a length-prefixed type in size errors
+func scanTypeName(prefix TypePrefix) string {
+	switch prefix {
+	case PrefixBuffer:
+		return "buffer"
+	case PrefixStringASCII:
+		return "string-ascii"
+	case PrefixStringUTF8:
+		return "string-utf8"
+	case PrefixList:
+		return "list"
+	default:
+		return "tuple"
+	}
+}
+
+// readByte reads a single byte from r, using io.ByteReader when available
+func readByte(r io.Reader) (byte, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
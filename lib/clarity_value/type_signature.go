@@ -0,0 +1,622 @@
+package clarity_value
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// TypeSignature represents a structured Clarity type
+type TypeSignature interface {
+	String() string
+}
+
+// NoType is the type of values whose type cannot be determined, such as the element of an empty list
+type NoType struct{}
+
+// IntType represents the Clarity int type
+type IntType struct{}
+
+// UIntType represents the Clarity uint type
+type UIntType struct{}
+
+// BoolType represents the Clarity bool type
+type BoolType struct{}
+
+// PrincipalType represents the Clarity principal type
+type PrincipalType struct{}
+
+// BufferType represents the Clarity (buff N) type
+type BufferType struct {
+	Length uint32
+}
+
+// StringASCIIType represents the Clarity (string-ascii N) type
+type StringASCIIType struct {
+	Length uint32
+}
+
+// StringUTF8Type represents the Clarity (string-utf8 N) type, where N counts characters
+type StringUTF8Type struct {
+	Length uint32
+}
+
+// ListType represents the Clarity (list N T) type
+type ListType struct {
+	MaxLength uint32
+	Element   TypeSignature
+}
+
+// TupleType represents the Clarity (tuple (name T) ...) type
+type TupleType map[ClarityName]TypeSignature
+
+// OptionalType represents the Clarity (optional T) type
+type OptionalType struct {
+	Inner TypeSignature
+}
+
+// ResponseType represents the Clarity (response A B) type
+type ResponseType struct {
+	Ok  TypeSignature
+	Err TypeSignature
+}
+
+// String returns the Clarity representation of NoType
+func (t NoType) String() string {
+	return "UnknownType"
+}
+
+// String returns the Clarity representation of IntType
+func (t IntType) String() string {
+	return "int"
+}
+
+// String returns the Clarity representation of UIntType
+func (t UIntType) String() string {
+	return "uint"
+}
+
+// String returns the Clarity representation of BoolType
+func (t BoolType) String() string {
+	return "bool"
+}
+
+// String returns the Clarity representation of PrincipalType
+func (t PrincipalType) String() string {
+	return "principal"
+}
+
+// String returns the Clarity representation of BufferType
+func (t BufferType) String() string {
+	return fmt.Sprintf("(buff %d)", t.Length)
+}
+
+// String returns the Clarity representation of StringASCIIType
+func (t StringASCIIType) String() string {
+	return fmt.Sprintf("(string-ascii %d)", t.Length)
+}
+
+// String returns the Clarity representation of StringUTF8Type
+func (t StringUTF8Type) String() string {
+	return fmt.Sprintf("(string-utf8 %d)", t.Length)
+}
+
+// String returns the Clarity representation of ListType
+func (t ListType) String() string {
+	return fmt.Sprintf("(list %d %s)", t.MaxLength, t.Element)
+}
+
+// String returns the Clarity representation of TupleType
+func (t TupleType) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("(tuple")
+	for _, key := range t.SortedNames() {
+		buffer.WriteString(fmt.Sprintf(" (%s %s)", key, t[key]))
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+// SortedNames returns the field names of the tuple type in canonical order
+func (t TupleType) SortedNames() []ClarityName {
+	keys := make([]ClarityName, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// String returns the Clarity representation of OptionalType
+func (t OptionalType) String() string {
+	return fmt.Sprintf("(optional %s)", t.Inner)
+}
+
+// String returns the Clarity representation of ResponseType
+func (t ResponseType) String() string {
+	return fmt.Sprintf("(response %s %s)", t.Ok, t.Err)
+}
+
+// AdmissionError describes why a value is not admitted by a type
+type AdmissionError struct {
+	Path     string
+	Expected TypeSignature
+	Reason   string
+}
+
+func (e *AdmissionError) Error() string {
+	return fmt.Sprintf("value at %s does not match %s: %s", e.Path, e.Expected, e.Reason)
+}
+
+// Admits checks that a value belongs to a type, returning an *AdmissionError naming the failing path otherwise
+func Admits(t TypeSignature, v Value) error {
+	return admitsInternal(t, v, "value")
+}
+
+// admitsInternal handles the recursive admission check
+func admitsInternal(t TypeSignature, v Value, path string) error {
+	fail := func(format string, args ...interface{}) error {
+		return &AdmissionError{Path: path, Expected: t, Reason: fmt.Sprintf(format, args...)}
+	}
+	if v == nil {
+		return fail("missing value")
+	}
+
+	switch typ := t.(type) {
+	case IntType:
+		if _, ok := v.(IntValue); !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case UIntType:
+		if _, ok := v.(UIntValue); !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case BoolType:
+		if _, ok := v.(BoolValue); !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case PrincipalType:
+		switch v.(type) {
+		case PrincipalStandardValue, PrincipalContractValue:
+		default:
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case BufferType:
+		buf, ok := v.(BufferValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if uint64(len(buf)) > uint64(typ.Length) {
+			return fail("length %d exceeds %d", len(buf), typ.Length)
+		}
+
+	case StringASCIIType:
+		str, ok := v.(StringASCIIValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if uint64(len(str)) > uint64(typ.Length) {
+			return fail("length %d exceeds %d", len(str), typ.Length)
+		}
+
+	case StringUTF8Type:
+		str, ok := v.(StringUTF8Value)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if uint64(len(str)) > uint64(typ.Length) {
+			return fail("length %d exceeds %d", len(str), typ.Length)
+		}
+
+	case ListType:
+		list, ok := v.(ListValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		if uint64(len(list)) > uint64(typ.MaxLength) {
+			return fail("length %d exceeds %d", len(list), typ.MaxLength)
+		}
+		for i, item := range list {
+			if err := admitsInternal(typ.Element, item.Value, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case TupleType:
+		tuple, ok := v.(TupleValue)
+		if !ok {
+			return fail("got %s", v.TypeSignature())
+		}
+		for _, name := range typ.SortedNames() {
+			field, ok := tuple[name]
+			if !ok {
+				return fail("missing field %s", name)
+			}
+			if err := admitsInternal(typ[name], field.Value, path+"."+string(name)); err != nil {
+				return err
+			}
+		}
+		if len(tuple) != len(typ) {
+			for name := range tuple {
+				if _, ok := typ[name]; !ok {
+					return fail("unexpected field %s", name)
+				}
+			}
+		}
+
+	case OptionalType:
+		switch opt := v.(type) {
+		case OptionalNoneValue:
+		case OptionalSomeValue:
+			return admitsInternal(typ.Inner, opt.Value.Value, path+".some")
+		default:
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case ResponseType:
+		switch resp := v.(type) {
+		case ResponseOkValue:
+			return admitsInternal(typ.Ok, resp.Value.Value, path+".ok")
+		case ResponseErrValue:
+			return admitsInternal(typ.Err, resp.Value.Value, path+".err")
+		default:
+			return fail("got %s", v.TypeSignature())
+		}
+
+	case NoType:
+		return fail("no value admitted by UnknownType")
+
+	default:
+		return fail("unsupported type signature %T", t)
+	}
+
+	return nil
+}
+
+// TypeOf infers the most specific type signature of a value.
+// Lists are typed with the least supertype of their elements.
+func TypeOf(v Value) (TypeSignature, error) {
+	switch val := v.(type) {
+	case IntValue:
+		return IntType{}, nil
+	case UIntValue:
+		return UIntType{}, nil
+	case BoolValue:
+		return BoolType{}, nil
+	case PrincipalStandardValue, PrincipalContractValue:
+		return PrincipalType{}, nil
+	case BufferValue:
+		return BufferType{Length: uint32(len(val))}, nil
+	case StringASCIIValue:
+		return StringASCIIType{Length: uint32(len(val))}, nil
+	case StringUTF8Value:
+		return StringUTF8Type{Length: uint32(len(val))}, nil
+
+	case ListValue:
+		var element TypeSignature = NoType{}
+		for i, item := range val {
+			itemType, err := TypeOf(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			element, err = LeastSupertype(element, itemType)
+			if err != nil {
+				return nil, fmt.Errorf("list element %d: %w", i, err)
+			}
+		}
+		return ListType{MaxLength: uint32(len(val)), Element: element}, nil
+
+	case TupleValue:
+		tuple := make(TupleType, len(val))
+		for name, field := range val {
+			fieldType, err := TypeOf(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			tuple[name] = fieldType
+		}
+		return tuple, nil
+
+	case OptionalNoneValue:
+		return OptionalType{Inner: NoType{}}, nil
+	case OptionalSomeValue:
+		inner, err := TypeOf(val.Value.Value)
+		if err != nil {
+			return nil, err
+		}
+		return OptionalType{Inner: inner}, nil
+
+	case ResponseOkValue:
+		ok, err := TypeOf(val.Value.Value)
+		if err != nil {
+			return nil, err
+		}
+		return ResponseType{Ok: ok, Err: NoType{}}, nil
+	case ResponseErrValue:
+		errType, err := TypeOf(val.Value.Value)
+		if err != nil {
+			return nil, err
+		}
+		return ResponseType{Ok: NoType{}, Err: errType}, nil
+
+	default:
+		return nil, fmt.Errorf("cannot infer type of %T", v)
+	}
+}
+
+// LeastSupertype returns the smallest type that admits every value of both a and b
+func LeastSupertype(a, b TypeSignature) (TypeSignature, error) {
+	if _, ok := a.(NoType); ok {
+		return b, nil
+	}
+	if _, ok := b.(NoType); ok {
+		return a, nil
+	}
+
+	mismatch := fmt.Errorf("no common supertype of %s and %s", a, b)
+
+	switch ta := a.(type) {
+	case IntType, UIntType, BoolType, PrincipalType:
+		if a == b {
+			return a, nil
+		}
+
+	case BufferType:
+		if tb, ok := b.(BufferType); ok {
+			return BufferType{Length: max(ta.Length, tb.Length)}, nil
+		}
+
+	case StringASCIIType:
+		if tb, ok := b.(StringASCIIType); ok {
+			return StringASCIIType{Length: max(ta.Length, tb.Length)}, nil
+		}
+
+	case StringUTF8Type:
+		if tb, ok := b.(StringUTF8Type); ok {
+			return StringUTF8Type{Length: max(ta.Length, tb.Length)}, nil
+		}
+
+	case ListType:
+		if tb, ok := b.(ListType); ok {
+			element, err := LeastSupertype(ta.Element, tb.Element)
+			if err != nil {
+				return nil, err
+			}
+			return ListType{MaxLength: max(ta.MaxLength, tb.MaxLength), Element: element}, nil
+		}
+
+	case TupleType:
+		if tb, ok := b.(TupleType); ok && len(ta) == len(tb) {
+			tuple := make(TupleType, len(ta))
+			for name, fieldA := range ta {
+				fieldB, ok := tb[name]
+				if !ok {
+					return nil, mismatch
+				}
+				field, err := LeastSupertype(fieldA, fieldB)
+				if err != nil {
+					return nil, err
+				}
+				tuple[name] = field
+			}
+			return tuple, nil
+		}
+
+	case OptionalType:
+		if tb, ok := b.(OptionalType); ok {
+			inner, err := LeastSupertype(ta.Inner, tb.Inner)
+			if err != nil {
+				return nil, err
+			}
+			return OptionalType{Inner: inner}, nil
+		}
+
+	case ResponseType:
+		if tb, ok := b.(ResponseType); ok {
+			okType, err := LeastSupertype(ta.Ok, tb.Ok)
+			if err != nil {
+				return nil, err
+			}
+			errType, err := LeastSupertype(ta.Err, tb.Err)
+			if err != nil {
+				return nil, err
+			}
+			return ResponseType{Ok: okType, Err: errType}, nil
+		}
+	}
+
+	return nil, mismatch
+}
+
+// ParseTypeSignature parses a Clarity type expression such as (list 10 (tuple (id uint) (owner principal)))
+func ParseTypeSignature(s string) (TypeSignature, error) {
+	p := &literalParser{input: s, what: "type signature"}
+	t, err := p.parseType(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected trailing input %q", p.input[p.pos:])
+	}
+	return t, nil
+}
+
+// parseType parses a single type expression
+func (p *literalParser) parseType(depth int) (TypeSignature, error) {
+	if depth >= MaxValueDepth {
+		return nil, p.errorf("TypeSignatureTooDeep: %d", depth)
+	}
+
+	p.skipWhitespace()
+	if p.eof() {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch p.peek() {
+	case '(':
+		return p.parseTypeForm(depth)
+	case '{':
+		p.pos++
+		tuple := make(TupleType)
+		for {
+			p.skipWhitespace()
+			if p.eof() {
+				return nil, p.errorf("expected '}', got end of input")
+			}
+			if p.peek() == '}' {
+				p.pos++
+				return tuple, nil
+			}
+			if err := p.parseTupleTypeEntry(tuple, depth); err != nil {
+				return nil, err
+			}
+			p.skipWhitespace()
+			if !p.eof() && p.peek() == ',' {
+				p.pos++
+			}
+		}
+	}
+
+	start := p.pos
+	switch atom := p.readAtom(); atom {
+	case "int":
+		return IntType{}, nil
+	case "uint":
+		return UIntType{}, nil
+	case "bool":
+		return BoolType{}, nil
+	case "principal":
+		return PrincipalType{}, nil
+	case "UnknownType":
+		return NoType{}, nil
+	default:
+		p.pos = start
+		return nil, p.errorf("unknown type %q", atom)
+	}
+}
+
+// parseTypeForm parses a parenthesized type expression
+func (p *literalParser) parseTypeForm(depth int) (TypeSignature, error) {
+	p.pos++ // '('
+	p.skipWhitespace()
+	start := p.pos
+	keyword := p.readAtom()
+
+	var t TypeSignature
+	switch keyword {
+	case "buff", "string-ascii", "string-utf8":
+		length, err := p.parseTypeLength()
+		if err != nil {
+			return nil, err
+		}
+		switch keyword {
+		case "buff":
+			t = BufferType{Length: length}
+		case "string-ascii":
+			t = StringASCIIType{Length: length}
+		default:
+			t = StringUTF8Type{Length: length}
+		}
+
+	case "list":
+		length, err := p.parseTypeLength()
+		if err != nil {
+			return nil, err
+		}
+		element, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		t = ListType{MaxLength: length, Element: element}
+
+	case "optional":
+		inner, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		t = OptionalType{Inner: inner}
+
+	case "response":
+		okType, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		errType, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		t = ResponseType{Ok: okType, Err: errType}
+
+	case "tuple":
+		tuple := make(TupleType)
+		for {
+			p.skipWhitespace()
+			if p.eof() || p.peek() == ')' {
+				break
+			}
+			if err := p.expect('('); err != nil {
+				return nil, err
+			}
+			if err := p.parseTupleTypeEntry(tuple, depth); err != nil {
+				return nil, err
+			}
+			if err := p.expect(')'); err != nil {
+				return nil, err
+			}
+		}
+		t = tuple
+
+	default:
+		p.pos = start
+		return nil, p.errorf("unknown type %q", keyword)
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseTypeLength parses the length argument of a sized type
+func (p *literalParser) parseTypeLength() (uint32, error) {
+	p.skipWhitespace()
+	start := p.pos
+	atom := p.readAtom()
+	length, err := strconv.ParseUint(atom, 10, 32)
+	if err != nil || length > MaxValueSize {
+		p.pos = start
+		return 0, p.errorf("invalid type length %q", atom)
+	}
+	return uint32(length), nil
+}
+
+// parseTupleTypeEntry parses a single `name type` or `name: type` tuple type entry
+func (p *literalParser) parseTupleTypeEntry(tuple TupleType, depth int) error {
+	p.skipWhitespace()
+	start := p.pos
+	name, err := ValidateClarityName(p.readAtom())
+	if err != nil {
+		p.pos = start
+		return p.errorf("%s", err)
+	}
+	if _, exists := tuple[name]; exists {
+		p.pos = start
+		return p.errorf("duplicate tuple field: %s", name)
+	}
+	p.skipWhitespace()
+	if !p.eof() && p.peek() == ':' {
+		p.pos++
+	}
+	t, err := p.parseType(depth + 1)
+	if err != nil {
+		return err
+	}
+	tuple[name] = t
+	return nil
+}
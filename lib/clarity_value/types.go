@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"regexp"
 	"sort"
 
@@ -14,6 +15,7 @@ import (
 const (
 	MaxStringLen          = 128
 	MaxValueSize          = 1024 * 1024 // 1MB
+	MaxValueDepth         = 16
 	ContractMinNameLength = 1
 	ContractMaxNameLength = 40
 )
@@ -44,6 +46,10 @@ const (
 type ClarityValue struct {
 	SerializedBytes []byte
 	Value           Value
+	// Offset is the byte offset of the value's type prefix in the decoded
+	// input, counted as Decoder.Offset counts. Like SerializedBytes, it is
+	// only set when the serialized bytes are captured.
+	Offset int64
 }
 
 // NewClarityValueWithBytes creates a new ClarityValue with serialized bytes
@@ -68,8 +74,18 @@ type Value interface {
 	TypeSignature() string
 }
 
-// IntValue represents a Clarity integer value
-type IntValue int64
+// IntValue represents a Clarity 128-bit signed integer value
+type IntValue Int128
+
+// NewIntValue creates a new IntValue from an int64
+func NewIntValue(v int64) IntValue {
+	return IntValue(Int128FromInt64(v))
+}
+
+// Big returns the IntValue as a newly allocated big.Int
+func (v IntValue) Big() *big.Int {
+	return Int128(v).Big()
+}
 
 // TypePrefix returns the type prefix for IntValue
 func (v IntValue) TypePrefix() TypePrefix {
@@ -78,7 +94,7 @@ func (v IntValue) TypePrefix() TypePrefix {
 
 // ReprString returns the string representation of IntValue
 func (v IntValue) ReprString() string {
-	return fmt.Sprintf("%d", v)
+	return Int128(v).String()
 }
 
 // TypeSignature returns the type signature of IntValue
@@ -86,8 +102,18 @@ func (v IntValue) TypeSignature() string {
 	return "int"
 }
 
-// UIntValue represents a Clarity unsigned integer value
-type UIntValue uint64
+// UIntValue represents a Clarity 128-bit unsigned integer value
+type UIntValue Uint128
+
+// NewUIntValue creates a new UIntValue from a uint64
+func NewUIntValue(v uint64) UIntValue {
+	return UIntValue(Uint128FromUint64(v))
+}
+
+// Big returns the UIntValue as a newly allocated big.Int
+func (v UIntValue) Big() *big.Int {
+	return Uint128(v).Big()
+}
 
 // TypePrefix returns the type prefix for UIntValue
 func (v UIntValue) TypePrefix() TypePrefix {
@@ -96,7 +122,7 @@ func (v UIntValue) TypePrefix() TypePrefix {
 
 // ReprString returns the string representation of UIntValue
 func (v UIntValue) ReprString() string {
-	return fmt.Sprintf("u%d", v)
+	return "u" + Uint128(v).String()
 }
 
 // TypeSignature returns the type signature of UIntValue
@@ -135,7 +161,7 @@ func (v BufferValue) TypePrefix() TypePrefix {
 
 // ReprString returns the string representation of BufferValue
 func (v BufferValue) ReprString() string {
-	return hex.EncodeToString(v)
+	return "0x" + hex.EncodeToString(v)
 }
 
 // TypeSignature returns the type signature of BufferValue
@@ -165,15 +191,7 @@ func (v ListValue) ReprString() string {
 
 // TypeSignature returns the type signature of ListValue
 func (v ListValue) TypeSignature() string {
-	var buffer bytes.Buffer
-	buffer.WriteString(fmt.Sprintf("(list %d ", len(v)))
-	if len(v) > 0 {
-		buffer.WriteString(v[0].Value.TypeSignature())
-	} else {
-		buffer.WriteString("UnknownType")
-	}
-	buffer.WriteString(")")
-	return buffer.String()
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // StringUTF8Value represents a Clarity UTF-8 string value
@@ -214,7 +232,7 @@ func (v StringUTF8Value) ReprString() string {
 
 // TypeSignature returns the type signature of StringUTF8Value
 func (v StringUTF8Value) TypeSignature() string {
-	return fmt.Sprintf("(string-utf8 %d)", len(v)*4)
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // StringASCIIValue represents a Clarity ASCII string value
@@ -329,24 +347,7 @@ func (v TupleValue) ReprString() string {
 
 // TypeSignature returns the type signature of TupleValue
 func (v TupleValue) TypeSignature() string {
-	var buffer bytes.Buffer
-	buffer.WriteString("(tuple")
-	// Create a deterministic order of keys
-	keys := make([]string, 0, len(v))
-	for key := range v {
-		keys = append(keys, string(key))
-	}
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		clarityKey := ClarityName(key)
-		value := v[clarityKey]
-		buffer.WriteString(fmt.Sprintf(" (%s ", clarityKey))
-		buffer.WriteString(value.Value.TypeSignature())
-		buffer.WriteString(")")
-	}
-	buffer.WriteString(")")
-	return buffer.String()
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // OptionalSomeValue represents a Clarity optional some value
@@ -366,7 +367,7 @@ func (v OptionalSomeValue) ReprString() string {
 
 // TypeSignature returns the type signature of OptionalSomeValue
 func (v OptionalSomeValue) TypeSignature() string {
-	return fmt.Sprintf("(optional %s)", v.Value.Value.TypeSignature())
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // OptionalNoneValue represents a Clarity optional none value
@@ -404,7 +405,7 @@ func (v ResponseOkValue) ReprString() string {
 
 // TypeSignature returns the type signature of ResponseOkValue
 func (v ResponseOkValue) TypeSignature() string {
-	return fmt.Sprintf("(response %s UnknownType)", v.Value.Value.TypeSignature())
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // ResponseErrValue represents a Clarity response error value
@@ -424,7 +425,7 @@ func (v ResponseErrValue) ReprString() string {
 
 // TypeSignature returns the type signature of ResponseErrValue
 func (v ResponseErrValue) TypeSignature() string {
-	return fmt.Sprintf("(response UnknownType %s)", v.Value.Value.TypeSignature())
+	return typeSignature(v, legacyUTF8Length)
 }
 
 // Helper function to escape ASCII characters
@@ -517,3 +518,55 @@ func MustContractName(s string) ContractName {
 func (c ContractName) String() string {
 	return string(c)
 }
+
+// typeSignature returns the type signature of v, with utf8Length giving the
+// length written for a string-utf8 value
+func typeSignature(v Value, utf8Length func(StringUTF8Value) int) string {
+	switch v := v.(type) {
+	case StringUTF8Value:
+		return fmt.Sprintf("(string-utf8 %d)", utf8Length(v))
+	case ListValue:
+		if len(v) == 0 {
+			return "(list 0 UnknownType)"
+		}
+		return fmt.Sprintf("(list %d %s)", len(v), typeSignature(v[0].Value, utf8Length))
+	case TupleValue:
+		// Create a deterministic order of keys
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, string(key))
+		}
+		sort.Strings(keys)
+
+		var buffer bytes.Buffer
+		buffer.WriteString("(tuple")
+		for _, key := range keys {
+			buffer.WriteString(fmt.Sprintf(" (%s %s)", key, typeSignature(v[ClarityName(key)].Value, utf8Length)))
+		}
+		buffer.WriteString(")")
+		return buffer.String()
+	case OptionalSomeValue:
+		return fmt.Sprintf("(optional %s)", typeSignature(v.Value.Value, utf8Length))
+	case ResponseOkValue:
+		return fmt.Sprintf("(response %s UnknownType)", typeSignature(v.Value.Value, utf8Length))
+	case ResponseErrValue:
+		return fmt.Sprintf("(response UnknownType %s)", typeSignature(v.Value.Value, utf8Length))
+	}
+	return v.TypeSignature()
+}
+
+// legacyUTF8Length is the string-utf8 length of the Rust source's type
+// signatures: four bytes per character
+func legacyUTF8Length(v StringUTF8Value) int {
+	return len(v) * 4
+}
+
+// utf8ByteLength is the string-utf8 length stacks.js cvToJSON writes: the
+// number of UTF-8 bytes
+func utf8ByteLength(v StringUTF8Value) int {
+	length := 0
+	for _, c := range v {
+		length += len(c)
+	}
+	return length
+}
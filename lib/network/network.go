@@ -0,0 +1,144 @@
+// Package network describes the Stacks networks: the chain ID and transaction
+// version their transactions carry, the versions of their addresses, and the
+// Bitcoin network they settle on
+package network
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWrongNetwork matches every error about a value that belongs to another network
+var ErrWrongNetwork = errors.New("wrong network")
+
+// Network is a network profile
+type Network struct {
+	Name string
+	// ChainID and TransactionVersion are the values every transaction carries
+	ChainID            uint32
+	TransactionVersion uint8
+	// AddressVersionSinglesig and AddressVersionMultisig are the c32 versions
+	// of standard principals
+	AddressVersionSinglesig byte
+	AddressVersionMultisig  byte
+	// BitcoinHRP is the human-readable part of the Bitcoin network's segwit
+	// addresses, and the version bytes those of its base58check addresses
+	BitcoinHRP              string
+	BitcoinVersionSinglesig uint8
+	BitcoinVersionMultisig  uint8
+}
+
+// Built-in network profiles
+var (
+	// Mainnet is the Stacks mainnet, settling on Bitcoin mainnet
+	Mainnet = Network{
+		Name:                    "mainnet",
+		ChainID:                 0x00000001,
+		TransactionVersion:      0x00,
+		AddressVersionSinglesig: 22, // 'P'
+		AddressVersionMultisig:  20, // 'M'
+		BitcoinHRP:              "bc",
+		BitcoinVersionSinglesig: 0,
+		BitcoinVersionMultisig:  5,
+	}
+	// Testnet is the Stacks testnet, settling on Bitcoin testnet
+	Testnet = Network{
+		Name:                    "testnet",
+		ChainID:                 0x80000000,
+		TransactionVersion:      0x80,
+		AddressVersionSinglesig: 26, // 'T'
+		AddressVersionMultisig:  21, // 'N'
+		BitcoinHRP:              "tb",
+		BitcoinVersionSinglesig: 111,
+		BitcoinVersionMultisig:  196,
+	}
+	// Devnet is a local development network settling on Bitcoin regtest, such
+	// as the one Clarinet runs
+	Devnet = Network{
+		Name:                    "devnet",
+		ChainID:                 0x80000000,
+		TransactionVersion:      0x80,
+		AddressVersionSinglesig: 26,
+		AddressVersionMultisig:  21,
+		BitcoinHRP:              "bcrt",
+		BitcoinVersionSinglesig: 111,
+		BitcoinVersionMultisig:  196,
+	}
+	// Mocknet is a local network with a mocked burnchain
+	Mocknet = Network{
+		Name:                    "mocknet",
+		ChainID:                 0x80000000,
+		TransactionVersion:      0x80,
+		AddressVersionSinglesig: 26,
+		AddressVersionMultisig:  21,
+		BitcoinHRP:              "bcrt",
+		BitcoinVersionSinglesig: 111,
+		BitcoinVersionMultisig:  196,
+	}
+)
+
+// Regtest is an alias of Devnet
+var Regtest = Devnet
+
+// NewSubnet creates the profile of a subnet with its own chain ID, using the
+// transaction version and address versions of the network it settles on
+func NewSubnet(name string, chainID uint32, parent Network) Network {
+	subnet := parent
+	subnet.Name = name
+	subnet.ChainID = chainID
+	return subnet
+}
+
+// IsMainnet reports whether the network uses the mainnet transaction version
+func (n Network) IsMainnet() bool {
+	return n.TransactionVersion == Mainnet.TransactionVersion
+}
+
+// AddressVersion returns the singlesig or multisig c32 address version
+func (n Network) AddressVersion(multisig bool) byte {
+	if multisig {
+		return n.AddressVersionMultisig
+	}
+	return n.AddressVersionSinglesig
+}
+
+// CheckTransactionVersion checks that version is the network's transaction version
+func (n Network) CheckTransactionVersion(version uint8) error {
+	if version != n.TransactionVersion {
+		return fmt.Errorf("%w: transaction version %#02x, %s uses %#02x", ErrWrongNetwork, version, n.Name, n.TransactionVersion)
+	}
+	return nil
+}
+
+// CheckChainID checks that chainID is the network's chain ID
+func (n Network) CheckChainID(chainID uint32) error {
+	if chainID != n.ChainID {
+		return fmt.Errorf("%w: chain ID %#08x, %s uses %#08x", ErrWrongNetwork, chainID, n.Name, n.ChainID)
+	}
+	return nil
+}
+
+// CheckAddressVersion checks that version is one of the network's c32 address versions
+func (n Network) CheckAddressVersion(version byte) error {
+	if version != n.AddressVersionSinglesig && version != n.AddressVersionMultisig {
+		return fmt.Errorf("%w: address version %d is not a %s version", ErrWrongNetwork, version, n.Name)
+	}
+	return nil
+}
+
+// Validate checks that the profile does not pair the chain ID of a built-in
+// network with another transaction version, e.g. the mainnet version with the
+// testnet chain ID
+func (n Network) Validate() error {
+	for _, builtin := range []Network{Mainnet, Testnet, Devnet, Mocknet} {
+		if n.ChainID == builtin.ChainID && n.TransactionVersion != builtin.TransactionVersion {
+			return fmt.Errorf("%w: chain ID %#08x is %s's, which uses transaction version %#02x, not %#02x", ErrWrongNetwork, n.ChainID, builtin.Name, builtin.TransactionVersion, n.TransactionVersion)
+		}
+	}
+	return nil
+}
+
+// String returns the name of the network
+func (n Network) String() string {
+	return n.Name
+}
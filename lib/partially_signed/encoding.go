@@ -0,0 +1,238 @@
+package partially_signed
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+// binaryMagic starts every binary container
+var binaryMagic = [4]byte{'p', 's', 's', 't'}
+
+// jsonTransaction is the JSON form of a container
+type jsonTransaction struct {
+	Version            uint8           `json:"version"`
+	Transaction        string          `json:"transaction"`
+	PublicKeys         []string        `json:"public_keys"`
+	SignaturesRequired uint16          `json:"signatures_required"`
+	Signatures         []jsonSignature `json:"signatures"`
+}
+
+type jsonSignature struct {
+	Index     int    `json:"index"`
+	Signature string `json:"signature"`
+}
+
+// MarshalJSON encodes the container as JSON, with the unsigned transaction,
+// public keys and signatures in hex
+func (t *Transaction) MarshalJSON() ([]byte, error) {
+	serialized, err := t.Unsigned.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	out := jsonTransaction{
+		Version:            Version,
+		Transaction:        hex.EncodeToString(serialized),
+		PublicKeys:         make([]string, len(t.PublicKeys)),
+		SignaturesRequired: t.SignaturesRequired,
+		Signatures:         []jsonSignature{},
+	}
+	for i, publicKey := range t.PublicKeys {
+		out.PublicKeys[i] = hex.EncodeToString(publicKey)
+	}
+	for _, index := range sortedIndexes(t.Signatures) {
+		signature := t.Signatures[index]
+		out.Signatures = append(out.Signatures, jsonSignature{Index: index, Signature: hex.EncodeToString(signature[:])})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a container from JSON, checking every signature
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	var in jsonTransaction
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if in.Version != Version {
+		return fmt.Errorf("%w: unsupported version: %d", ErrInvalid, in.Version)
+	}
+
+	serialized, err := hex.DecodeString(in.Transaction)
+	if err != nil {
+		return fmt.Errorf("%w: transaction: %w", ErrInvalid, err)
+	}
+	publicKeys := make([][]byte, len(in.PublicKeys))
+	for i, publicKey := range in.PublicKeys {
+		if publicKeys[i], err = hex.DecodeString(publicKey); err != nil {
+			return fmt.Errorf("%w: public key %d: %w", ErrInvalid, i, err)
+		}
+	}
+	signatures := make(map[int]transaction.MessageSignature, len(in.Signatures))
+	for _, signature := range in.Signatures {
+		signatureBytes, err := hex.DecodeString(signature.Signature)
+		if err != nil || len(signatureBytes) != len(transaction.MessageSignature{}) {
+			return fmt.Errorf("%w: invalid signature %d", ErrInvalid, signature.Index)
+		}
+		signatures[signature.Index] = transaction.MessageSignature(signatureBytes)
+	}
+
+	return t.assemble(serialized, in.SignaturesRequired, publicKeys, signatures)
+}
+
+// MarshalBinary encodes the container as "psst", the version, the
+// length-prefixed unsigned transaction, the signatures required, the
+// length-prefixed public keys and the indexed signatures
+func (t *Transaction) MarshalBinary() ([]byte, error) {
+	serialized, err := t.Unsigned.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(binaryMagic[:])
+	buf.WriteByte(Version)
+	binary.Write(&buf, binary.BigEndian, uint32(len(serialized)))
+	buf.Write(serialized)
+	binary.Write(&buf, binary.BigEndian, t.SignaturesRequired)
+
+	binary.Write(&buf, binary.BigEndian, uint16(len(t.PublicKeys)))
+	for _, publicKey := range t.PublicKeys {
+		buf.WriteByte(byte(len(publicKey)))
+		buf.Write(publicKey)
+	}
+
+	binary.Write(&buf, binary.BigEndian, uint16(len(t.Signatures)))
+	for _, index := range sortedIndexes(t.Signatures) {
+		signature := t.Signatures[index]
+		binary.Write(&buf, binary.BigEndian, uint16(index))
+		buf.Write(signature[:])
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a container from its binary form, checking every
+// signature. Malformed input is reported as a *clarity_value.DeserializeError.
+func (t *Transaction) UnmarshalBinary(data []byte) error {
+	r := clarity_value.NewOffsetReader(bytes.NewReader(data), 0)
+
+	var magic [4]byte
+	if err := readField(r, "magic", &magic); err != nil {
+		return err
+	}
+	if magic != binaryMagic {
+		return fieldError(clarity_value.KindBadPrefix, 0, "magic", "not a partially signed transaction")
+	}
+
+	var version uint8
+	if err := readField(r, "version", &version); err != nil {
+		return err
+	}
+	if version != Version {
+		return fieldError(clarity_value.KindBadPrefix, 4, "version", "unsupported version: %d", version)
+	}
+
+	var txLen uint32
+	offset := r.InputOffset()
+	if err := readField(r, "transaction", &txLen); err != nil {
+		return err
+	}
+	if int64(txLen) > int64(len(data)) {
+		return fieldError(clarity_value.KindTooLarge, offset, "transaction", "length %d exceeds input", txLen)
+	}
+	serialized := make([]byte, txLen)
+	if err := readField(r, "transaction", serialized); err != nil {
+		return err
+	}
+
+	var signaturesRequired uint16
+	if err := readField(r, "signatures_required", &signaturesRequired); err != nil {
+		return err
+	}
+
+	var keyCount uint16
+	if err := readField(r, "public_keys", &keyCount); err != nil {
+		return err
+	}
+	var publicKeys [][]byte
+	for i := 0; i < int(keyCount); i++ {
+		field := fmt.Sprintf("public_keys[%d]", i)
+		var keyLen uint8
+		if err := readField(r, field, &keyLen); err != nil {
+			return err
+		}
+		publicKey := make([]byte, keyLen)
+		if err := readField(r, field, publicKey); err != nil {
+			return err
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	var signatureCount uint16
+	if err := readField(r, "signatures", &signatureCount); err != nil {
+		return err
+	}
+	signatures := make(map[int]transaction.MessageSignature)
+	for i := 0; i < int(signatureCount); i++ {
+		field := fmt.Sprintf("signatures[%d]", i)
+		var index uint16
+		var signature transaction.MessageSignature
+		if err := readField(r, field, &index); err != nil {
+			return err
+		}
+		if err := readField(r, field, &signature); err != nil {
+			return err
+		}
+		signatures[int(index)] = signature
+	}
+
+	if r.InputOffset() != int64(len(data)) {
+		return fieldError(clarity_value.KindTrailingBytes, r.InputOffset(), "", "%d bytes after the container", int64(len(data))-r.InputOffset())
+	}
+
+	return t.assemble(serialized, signaturesRequired, publicKeys, signatures)
+}
+
+// assemble rebuilds the container from its decoded parts, checking each
+// signature as AddSignature does
+func (t *Transaction) assemble(serialized []byte, signaturesRequired uint16, publicKeys [][]byte, signatures map[int]transaction.MessageSignature) error {
+	tx, err := transaction.DecodeTransaction(serialized)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if len(tx.Auth.SpendingCondition.Fields) != 0 {
+		return fmt.Errorf("%w: transaction origin has auth fields", ErrInvalid)
+	}
+
+	assembled, err := New(tx, signaturesRequired, publicKeys)
+	if err != nil {
+		return err
+	}
+	for _, index := range sortedIndexes(signatures) {
+		if err := assembled.AddSignature(index, signatures[index]); err != nil {
+			return err
+		}
+	}
+
+	*t = *assembled
+	return nil
+}
+
+// readField reads a fixed-size big-endian field
+func readField(r clarity_value.OffsetReader, field string, data interface{}) error {
+	offset := r.InputOffset()
+	if err := binary.Read(r, binary.BigEndian, data); err != nil {
+		return clarity_value.WithPathSegment(clarity_value.WrapReadError(err, offset), field)
+	}
+	return nil
+}
+
+// fieldError creates a DeserializeError for field
+func fieldError(kind clarity_value.DeserializeErrorKind, offset int64, field string, format string, args ...interface{}) error {
+	return clarity_value.WithPathSegment(clarity_value.DeserializeErrorf(kind, offset, format, args...), field)
+}
@@ -0,0 +1,277 @@
+// Package partially_signed implements a portable container for collecting the
+// signatures of a multisig origin from signers that never see each other's
+// keys, such as air-gapped signers, in JSON or binary form
+package partially_signed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+// Version is the container format version
+const Version uint8 = 1
+
+// ErrInvalid matches every error about a container or the signatures added to it
+var ErrInvalid = errors.New("invalid partially signed transaction")
+
+// Transaction is a transaction whose multisig origin is being signed.
+//
+// Signatures are kept by the index of the signer's public key. Signers of a
+// sequential (P2SH or P2WSH) origin sign in key order, each over the
+// signatures of the signers before them, so the container has to be passed
+// from signer to signer; non-sequential signers sign independently and their
+// containers can be merged in any order.
+type Transaction struct {
+	// Unsigned is the transaction with no origin auth fields
+	Unsigned           *transaction.StacksTransaction
+	PublicKeys         [][]byte
+	SignaturesRequired uint16
+	// Signatures holds the signatures collected so far, by public key index
+	Signatures map[int]transaction.MessageSignature
+}
+
+// New creates a container for tx, whose origin is a multisig spending
+// condition requiring signaturesRequired signatures from publicKeys, in order.
+// Auth fields already in the origin are dropped.
+func New(tx *transaction.StacksTransaction, signaturesRequired uint16, publicKeys [][]byte) (*Transaction, error) {
+	origin := tx.Auth.SpendingCondition
+	expected, err := transaction.NewMultisigSpendingCondition(origin.HashMode, signaturesRequired, publicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if expected.Signer != origin.Signer {
+		return nil, fmt.Errorf("%w: origin signer %x does not match public keys hashing to %x", ErrInvalid, origin.Signer, expected.Signer)
+	}
+	if origin.SignaturesRequired == nil || *origin.SignaturesRequired != signaturesRequired {
+		return nil, fmt.Errorf("%w: origin does not require %d signatures", ErrInvalid, signaturesRequired)
+	}
+
+	// A serialization round trip leaves the caller's transaction alone
+	unsigned := *tx
+	unsigned.Auth.SpendingCondition.Fields = nil
+	serialized, err := unsigned.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	decoded, err := transaction.DecodeTransaction(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	keys := make([][]byte, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		keys[i] = append([]byte(nil), publicKey...)
+	}
+
+	return &Transaction{
+		Unsigned:           decoded,
+		PublicKeys:         keys,
+		SignaturesRequired: signaturesRequired,
+		Signatures:         make(map[int]transaction.MessageSignature),
+	}, nil
+}
+
+// Sign signs as the signer whose public key matches privateKey
+func (t *Transaction) Sign(privateKey []byte) error {
+	publicKey, err := transaction.PrivateKeyToPublicKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	index := -1
+	for i, key := range t.PublicKeys {
+		if bytes.Equal(key, publicKey) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%w: private key is not one of the signers", ErrInvalid)
+	}
+
+	presign, err := t.presignSighash(index)
+	if err != nil {
+		return err
+	}
+	signature, err := transaction.SignMessageHash(privateKey, presign)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return t.AddSignature(index, signature)
+}
+
+// AddSignature adds the signature of the signer with the public key at index,
+// after checking it was made by that key over the right sighash. Adding a
+// signature that is already present has no effect.
+func (t *Transaction) AddSignature(index int, signature transaction.MessageSignature) error {
+	if index < 0 || index >= len(t.PublicKeys) {
+		return fmt.Errorf("%w: no public key %d", ErrInvalid, index)
+	}
+	if existing, ok := t.Signatures[index]; ok {
+		if existing != signature {
+			return fmt.Errorf("%w: conflicting signatures for public key %d", ErrInvalid, index)
+		}
+		return nil
+	}
+
+	if t.sequential() {
+		if len(t.Signatures) >= int(t.SignaturesRequired) {
+			return fmt.Errorf("%w: already has %d signatures", ErrInvalid, t.SignaturesRequired)
+		}
+		for i := range t.Signatures {
+			if i > index {
+				return fmt.Errorf("%w: public key %d signs before public key %d, which has signed", ErrInvalid, index, i)
+			}
+		}
+	}
+
+	presign, err := t.presignSighash(index)
+	if err != nil {
+		return err
+	}
+	recovered, err := transaction.RecoverPublicKey(presign, signature, keyEncoding(t.PublicKeys[index]))
+	if err != nil || !bytes.Equal(recovered, t.PublicKeys[index]) {
+		return fmt.Errorf("%w: signature %d was not made by its public key over the transaction", ErrInvalid, index)
+	}
+
+	t.Signatures[index] = signature
+	return nil
+}
+
+// Merge adds the signatures collected in other, a container for the same
+// transaction and signers
+func (t *Transaction) Merge(other *Transaction) error {
+	if other.SignaturesRequired != t.SignaturesRequired || len(other.PublicKeys) != len(t.PublicKeys) {
+		return fmt.Errorf("%w: containers have different signers", ErrInvalid)
+	}
+	for i := range t.PublicKeys {
+		if !bytes.Equal(t.PublicKeys[i], other.PublicKeys[i]) {
+			return fmt.Errorf("%w: containers have different signers", ErrInvalid)
+		}
+	}
+
+	ours, err := t.Unsigned.Serialize()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	theirs, err := other.Unsigned.Serialize()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if !bytes.Equal(ours, theirs) {
+		return fmt.Errorf("%w: containers have different transactions", ErrInvalid)
+	}
+
+	for _, index := range sortedIndexes(other.Signatures) {
+		if err := t.AddSignature(index, other.Signatures[index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsComplete reports whether enough signatures have been collected
+func (t *Transaction) IsComplete() bool {
+	return len(t.Signatures) >= int(t.SignaturesRequired)
+}
+
+// Finalize returns the signed transaction, with a signature auth field for
+// each signer who signed and a public key auth field for the others
+func (t *Transaction) Finalize() (*transaction.StacksTransaction, error) {
+	if !t.IsComplete() {
+		return nil, fmt.Errorf("%w: %d of %d signatures", ErrInvalid, len(t.Signatures), t.SignaturesRequired)
+	}
+
+	signed := *t.Unsigned
+	var fields []transaction.TransactionAuthField
+	for i, publicKey := range t.PublicKeys {
+		uncompressed := keyEncoding(publicKey) == transaction.PublicKeyEncodingUncompressed
+		if signature, ok := t.Signatures[i]; ok {
+			field := transaction.TransactionAuthField{FieldID: transaction.AuthFieldIDSignatureCompressed, Signature: (*[65]byte)(&signature)}
+			if uncompressed {
+				field.FieldID = transaction.AuthFieldIDSignatureUncompressed
+			}
+			fields = append(fields, field)
+			continue
+		}
+
+		compressed, err := compressPublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		field := transaction.TransactionAuthField{FieldID: transaction.AuthFieldIDPublicKeyCompressed, PublicKey: &compressed}
+		if uncompressed {
+			field.FieldID = transaction.AuthFieldIDPublicKeyUncompressed
+		}
+		fields = append(fields, field)
+	}
+	signed.Auth.SpendingCondition.Fields = fields
+
+	if err := signed.VerifyOrigin(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return &signed, nil
+}
+
+// presignSighash returns the sighash the signer with the public key at index signs
+func (t *Transaction) presignSighash(index int) ([32]byte, error) {
+	sighash, err := t.Unsigned.InitialSighash()
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	origin := t.Unsigned.Auth.SpendingCondition
+	if t.sequential() {
+		for _, i := range sortedIndexes(t.Signatures) {
+			if i >= index {
+				break
+			}
+			presign := transaction.SighashPresign(sighash, transaction.TransactionAuthFlagStandard, origin.Fee, origin.Nonce)
+			sighash = transaction.SighashPostsign(presign, keyEncoding(t.PublicKeys[i]), t.Signatures[i])
+		}
+	}
+	return transaction.SighashPresign(sighash, transaction.TransactionAuthFlagStandard, origin.Fee, origin.Nonce), nil
+}
+
+// sequential reports whether signers sign over each other's signatures
+func (t *Transaction) sequential() bool {
+	hashMode := t.Unsigned.Auth.SpendingCondition.HashMode
+	return hashMode == transaction.MultisigHashModeP2SH || hashMode == transaction.MultisigHashModeP2WSH
+}
+
+// keyEncoding returns the encoding of a serialized public key
+func keyEncoding(publicKey []byte) uint8 {
+	if len(publicKey) == 33 {
+		return transaction.PublicKeyEncodingCompressed
+	}
+	return transaction.PublicKeyEncodingUncompressed
+}
+
+// compressPublicKey returns the compressed form of a serialized public key,
+// as auth fields hold it
+func compressPublicKey(publicKey []byte) ([33]byte, error) {
+	var compressed [33]byte
+	if len(publicKey) == 33 {
+		copy(compressed[:], publicKey)
+		return compressed, nil
+	}
+	if len(publicKey) != 65 || publicKey[0] != 0x04 {
+		return compressed, fmt.Errorf("%w: invalid public key", ErrInvalid)
+	}
+	compressed[0] = 0x02 | publicKey[64]&0x01
+	copy(compressed[1:], publicKey[1:33])
+	return compressed, nil
+}
+
+// sortedIndexes returns the public key indexes of signatures in order
+func sortedIndexes(signatures map[int]transaction.MessageSignature) []int {
+	indexes := make([]int, 0, len(signatures))
+	for i := range signatures {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -83,18 +83,23 @@ type PostConditionsResponse struct {
 	PostConditions    []PostCondition   `json:"post_conditions"`
 }
 
-// DecodePostCondition decodes a PostCondition from a byte stream
+// DecodePostCondition decodes a PostCondition from a byte stream. Failures are
+// *clarity_value.DeserializeErrors carrying the offset and path of the bad field;
+// offsets are absolute when r is a clarity_value.OffsetReader.
 func DecodePostCondition(r io.Reader) (PostCondition, error) {
+	r = clarity_value.NewOffsetReader(r, 0)
+	start := clarity_value.InputOffset(r)
+
 	// Read asset info type
 	var assetType byte
-	if err := binary.Read(r, binary.BigEndian, &assetType); err != nil {
-		return PostCondition{}, fmt.Errorf("read asset type: %w", err)
+	if err := readField(r, "asset_type", &assetType); err != nil {
+		return PostCondition{}, err
 	}
 
 	// Read principal
 	principal, err := decodePrincipal(r)
 	if err != nil {
-		return PostCondition{}, err
+		return PostCondition{}, clarity_value.WithPathSegment(err, "principal")
 	}
 
 	// Process based on asset type
@@ -141,15 +146,45 @@ func DecodePostCondition(r io.Reader) (PostCondition, error) {
 		}, nil
 
 	default:
-		return PostCondition{}, fmt.Errorf("unknown asset type: %d", assetType)
+		err := clarity_value.DeserializeErrorf(clarity_value.KindBadPrefix, start, "unknown asset type: %d", assetType)
+		return PostCondition{}, clarity_value.WithPathSegment(err, "asset_type")
+	}
+}
+
+// readField reads a fixed-size big-endian field, naming it in the error on failure
+func readField(r io.Reader, field string, data interface{}) error {
+	offset := clarity_value.InputOffset(r)
+	if err := binary.Read(r, binary.BigEndian, data); err != nil {
+		return clarity_value.WithPathSegment(clarity_value.WrapReadError(err, offset), field)
 	}
+	return nil
+}
+
+// decodeAddress decodes a StacksAddress, naming it in the error on failure
+func decodeAddress(r io.Reader, field string) (address.StacksAddress, error) {
+	offset := clarity_value.InputOffset(r)
+	addr, err := address.DecodeStacksAddress(r)
+	if err != nil {
+		return address.StacksAddress{}, clarity_value.WithPathSegment(clarity_value.WrapReadError(err, offset), field)
+	}
+	return addr, nil
+}
+
+// decodeName decodes a ClarityName, naming it in the error on failure
+func decodeName(r io.Reader, field string) (clarity_value.ClarityName, error) {
+	name, err := clarity_value.DecodeClarityName(r)
+	if err != nil {
+		return "", clarity_value.WithPathSegment(err, field)
+	}
+	return name, nil
 }
 
 // decodePrincipal decodes a Principal from a byte stream
 func decodePrincipal(r io.Reader) (Principal, error) {
+	offset := clarity_value.InputOffset(r)
 	var principalType byte
-	if err := binary.Read(r, binary.BigEndian, &principalType); err != nil {
-		return Principal{}, fmt.Errorf("read principal type: %w", err)
+	if err := readField(r, "type", &principalType); err != nil {
+		return Principal{}, err
 	}
 
 	switch principalType {
@@ -157,9 +192,9 @@ func decodePrincipal(r io.Reader) (Principal, error) {
 		return Principal{Type: principalType}, nil
 
 	case PrincipalStandard:
-		addr, err := address.DecodeStacksAddress(r)
+		addr, err := decodeAddress(r, "address")
 		if err != nil {
-			return Principal{}, fmt.Errorf("decode standard address: %w", err)
+			return Principal{}, err
 		}
 
 		return Principal{
@@ -168,19 +203,14 @@ func decodePrincipal(r io.Reader) (Principal, error) {
 		}, nil
 
 	case PrincipalContract:
-		addr, err := address.DecodeStacksAddress(r)
+		addr, err := decodeAddress(r, "address")
 		if err != nil {
-			return Principal{}, fmt.Errorf("decode contract address: %w", err)
+			return Principal{}, err
 		}
 
-		reader, ok := r.(*bytes.Reader)
-		if !ok {
-			return Principal{}, fmt.Errorf("expected bytes.Reader")
-		}
-
-		name, err := clarity_value.DecodeClarityName(reader)
+		name, err := decodeName(r, "contract_name")
 		if err != nil {
-			return Principal{}, fmt.Errorf("decode contract name: %w", err)
+			return Principal{}, err
 		}
 
 		return Principal{
@@ -190,26 +220,23 @@ func decodePrincipal(r io.Reader) (Principal, error) {
 		}, nil
 
 	default:
-		return Principal{}, fmt.Errorf("unknown principal type: %d", principalType)
+		err := clarity_value.DeserializeErrorf(clarity_value.KindBadPrefix, offset, "unknown principal type: %d", principalType)
+		return Principal{}, clarity_value.WithPathSegment(err, "type")
 	}
 }
 
 // decodeSTXData decodes STX-specific data (condition code and amount)
 func decodeSTXData(r io.Reader) (byte, uint64, error) {
 	// Read condition code
-	var condCode byte
-	if err := binary.Read(r, binary.BigEndian, &condCode); err != nil {
-		return 0, 0, fmt.Errorf("read condition code: %w", err)
-	}
-
-	if err := validateFungibleConditionCode(condCode); err != nil {
+	condCode, err := decodeConditionCode(r, validateFungibleConditionCode)
+	if err != nil {
 		return 0, 0, err
 	}
 
 	// Read amount
 	var amount uint64
-	if err := binary.Read(r, binary.BigEndian, &amount); err != nil {
-		return 0, 0, fmt.Errorf("read amount: %w", err)
+	if err := readField(r, "amount", &amount); err != nil {
+		return 0, 0, err
 	}
 
 	return condCode, amount, nil
@@ -224,19 +251,15 @@ func decodeFungibleData(r io.Reader) (AssetInfo, byte, uint64, error) {
 	}
 
 	// Read condition code
-	var condCode byte
-	if err := binary.Read(r, binary.BigEndian, &condCode); err != nil {
-		return AssetInfo{}, 0, 0, fmt.Errorf("read condition code: %w", err)
-	}
-
-	if err := validateFungibleConditionCode(condCode); err != nil {
+	condCode, err := decodeConditionCode(r, validateFungibleConditionCode)
+	if err != nil {
 		return AssetInfo{}, 0, 0, err
 	}
 
 	// Read amount
 	var amount uint64
-	if err := binary.Read(r, binary.BigEndian, &amount); err != nil {
-		return AssetInfo{}, 0, 0, fmt.Errorf("read amount: %w", err)
+	if err := readField(r, "amount", &amount); err != nil {
+		return AssetInfo{}, 0, 0, err
 	}
 
 	return asset, condCode, amount, nil
@@ -250,87 +273,52 @@ func decodeNonfungibleData(r io.Reader) (AssetInfo, clarity_value.ClarityValue,
 		return AssetInfo{}, clarity_value.ClarityValue{}, 0, err
 	}
 
-	// Get bytes.Reader for clarity value operations
-	reader, ok := r.(*bytes.Reader)
-	if !ok {
-		return AssetInfo{}, clarity_value.ClarityValue{}, 0, fmt.Errorf("expected bytes.Reader")
-	}
-
-	// Capture starting position for serialized bytes
-	startPos := reader.Size() - int64(reader.Len())
-
-	// Decode the clarity value
-	val, err := clarity_value.DecodeClarityValue(reader, false)
-	if err != nil {
-		return AssetInfo{}, clarity_value.ClarityValue{}, 0, fmt.Errorf("decode clarity value: %w", err)
-	}
-
-	// Calculate bytes read and capture them
-	endPos := reader.Size() - int64(reader.Len())
-
-	// Extract serialized bytes
-	serializedBytes, err := extractSerializedBytes(reader, startPos, endPos)
+	// Decode the clarity value along with its serialized bytes
+	val, err := clarity_value.NewDecoder(r).DecodeWithOptions(clarity_value.DecodeOptions{WithBytes: true, Path: "asset_value"})
 	if err != nil {
 		return AssetInfo{}, clarity_value.ClarityValue{}, 0, err
 	}
 
-	// Add serialized bytes to the clarity value
-	val.SerializedBytes = serializedBytes
-
 	// Read condition code
-	var condCode byte
-	if err := binary.Read(r, binary.BigEndian, &condCode); err != nil {
-		return AssetInfo{}, clarity_value.ClarityValue{}, 0, fmt.Errorf("read condition code: %w", err)
-	}
-
-	if err := validateNonfungibleConditionCode(condCode); err != nil {
+	condCode, err := decodeConditionCode(r, validateNonfungibleConditionCode)
+	if err != nil {
 		return AssetInfo{}, clarity_value.ClarityValue{}, 0, err
 	}
 
 	return asset, val, condCode, nil
 }
 
-// extractSerializedBytes extracts bytes from a reader between start and end positions
-func extractSerializedBytes(reader *bytes.Reader, startPos, endPos int64) ([]byte, error) {
-	// Go back to read all the bytes
-	if _, err := reader.Seek(startPos, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("seek to start: %w", err)
-	}
-
-	// Read the bytes into a slice
-	decoded := make([]byte, endPos-startPos)
-	if _, err := io.ReadFull(reader, decoded); err != nil {
-		return nil, fmt.Errorf("read value bytes: %w", err)
+// decodeConditionCode reads a condition code and checks it with validate
+func decodeConditionCode(r io.Reader, validate func(byte) error) (byte, error) {
+	offset := clarity_value.InputOffset(r)
+	var condCode byte
+	if err := readField(r, "condition_code", &condCode); err != nil {
+		return 0, err
 	}
 
-	// Restore position
-	if _, err := reader.Seek(endPos, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("restore position: %w", err)
+	if err := validate(condCode); err != nil {
+		deserializeErr := &clarity_value.DeserializeError{Kind: clarity_value.KindInvalid, Offset: offset, Err: err}
+		return 0, clarity_value.WithPathSegment(deserializeErr, "condition_code")
 	}
 
-	return decoded, nil
+	return condCode, nil
 }
 
 // decodeAssetInfo decodes asset info from a byte stream
 func decodeAssetInfo(r io.Reader) (AssetInfo, error) {
-	addr, err := address.DecodeStacksAddress(r)
+	addr, err := decodeAddress(r, "asset.address")
 	if err != nil {
-		return AssetInfo{}, fmt.Errorf("decode address: %w", err)
-	}
-
-	reader, ok := r.(*bytes.Reader)
-	if !ok {
-		return AssetInfo{}, fmt.Errorf("expected bytes.Reader")
+		return AssetInfo{}, err
 	}
 
-	contractName, err := clarity_value.DecodeClarityName(reader)
+	contractName, err := decodeName(r, "asset.contract_name")
 	if err != nil {
-		return AssetInfo{}, fmt.Errorf("decode contract name: %w", err)
+		return AssetInfo{}, err
 	}
 
-	assetName, err := clarity_value.DecodeClarityName(reader)
+	assetName, err := decodeName(r, "asset.asset_name")
 	if err != nil {
-		return AssetInfo{}, fmt.Errorf("decode asset name: %w", err)
+		return AssetInfo{}, err
 	}
 
 	return AssetInfo{
@@ -360,10 +348,12 @@ func validateNonfungibleConditionCode(code byte) error {
 	}
 }
 
-// DecodeTxPostConditions decodes a transaction's post conditions from bytes
+// DecodeTxPostConditions decodes a transaction's post conditions from bytes.
+// Errors name the failing post condition, e.g. "post_conditions[1].amount",
+// with offsets relative to the start of data.
 func DecodeTxPostConditions(data []byte) (*PostConditionsResponse, error) {
 	if len(data) < 1 {
-		return nil, fmt.Errorf("insufficient data for post conditions")
+		return nil, clarity_value.WithPathSegment(clarity_value.WrapReadError(io.EOF, 0), "post_condition_mode")
 	}
 
 	resp := &PostConditionsResponse{
@@ -378,11 +368,12 @@ func DecodeTxPostConditions(data []byte) (*PostConditionsResponse, error) {
 		// Next bytes are serialized post condition items
 		postConditionBytes := data[5:]
 		reader := bytes.NewReader(postConditionBytes)
+		r := clarity_value.NewOffsetReader(reader, 5)
 
-		for reader.Len() > 0 {
-			postCondition, err := DecodePostCondition(reader)
+		for i := 0; reader.Len() > 0; i++ {
+			postCondition, err := DecodePostCondition(r)
 			if err != nil {
-				return nil, fmt.Errorf("error deserializing post condition: %w", err)
+				return nil, clarity_value.WithPathSegment(err, fmt.Sprintf("post_conditions[%d]", i))
 			}
 			resp.PostConditions = append(resp.PostConditions, postCondition)
 		}
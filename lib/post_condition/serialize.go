@@ -0,0 +1,136 @@
+package post_condition
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+// SerializePostCondition serializes a PostCondition into the consensus wire format
+func SerializePostCondition(pc PostCondition) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WritePostCondition(&buf, pc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WritePostCondition serializes a PostCondition into the consensus wire format and writes it to w.
+// The asset value of a non-fungible condition is serialized from its Value.
+func WritePostCondition(w io.Writer, pc PostCondition) error {
+	if _, err := w.Write([]byte{pc.Type}); err != nil {
+		return err
+	}
+
+	if err := writePrincipal(w, pc.Principal); err != nil {
+		return err
+	}
+
+	switch pc.Type {
+	case AssetInfoSTX:
+		if err := validateFungibleConditionCode(pc.ConditionCode); err != nil {
+			return fmt.Errorf("failed to serialize post condition: %w", err)
+		}
+		return writeConditionAmount(w, pc.ConditionCode, pc.Amount)
+
+	case AssetInfoFungible:
+		if err := validateFungibleConditionCode(pc.ConditionCode); err != nil {
+			return fmt.Errorf("failed to serialize post condition: %w", err)
+		}
+		if err := writeAssetInfo(w, pc.Asset); err != nil {
+			return err
+		}
+		return writeConditionAmount(w, pc.ConditionCode, pc.Amount)
+
+	case AssetInfoNonfungible:
+		if err := validateNonfungibleConditionCode(pc.ConditionCode); err != nil {
+			return fmt.Errorf("failed to serialize post condition: %w", err)
+		}
+		if pc.AssetValue.Value == nil {
+			return fmt.Errorf("failed to serialize post condition: missing asset value")
+		}
+		if err := writeAssetInfo(w, pc.Asset); err != nil {
+			return err
+		}
+		if err := clarity_value.WriteClarityValue(w, pc.AssetValue.Value); err != nil {
+			return err
+		}
+		_, err := w.Write([]byte{pc.ConditionCode})
+		return err
+
+	default:
+		return fmt.Errorf("failed to serialize post condition: unknown asset type: %d", pc.Type)
+	}
+}
+
+// EncodeTxPostConditions serializes a post condition mode and list in the form
+// read by DecodeTxPostConditions
+func EncodeTxPostConditions(mode PostConditionMode, postConditions []PostCondition) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(mode))
+	if err := binary.Write(&buf, binary.BigEndian, uint32(len(postConditions))); err != nil {
+		return nil, err
+	}
+	for _, pc := range postConditions {
+		if err := WritePostCondition(&buf, pc); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writePrincipal serializes a post condition Principal
+func writePrincipal(w io.Writer, principal Principal) error {
+	if _, err := w.Write([]byte{principal.Type}); err != nil {
+		return err
+	}
+
+	switch principal.Type {
+	case PrincipalOrigin:
+		return nil
+
+	case PrincipalStandard:
+		return writeAddress(w, principal.Address)
+
+	case PrincipalContract:
+		if err := writeAddress(w, principal.Address); err != nil {
+			return err
+		}
+		return clarity_value.WriteClarityName(w, principal.ContractName)
+
+	default:
+		return fmt.Errorf("failed to serialize post condition: unknown principal type: %d", principal.Type)
+	}
+}
+
+// writeAssetInfo serializes the asset a fungible or non-fungible condition refers to
+func writeAssetInfo(w io.Writer, asset AssetInfo) error {
+	if err := writeAddress(w, asset.Address); err != nil {
+		return err
+	}
+	if err := clarity_value.WriteClarityName(w, asset.ContractName); err != nil {
+		return err
+	}
+	return clarity_value.WriteClarityName(w, asset.AssetName)
+}
+
+// writeConditionAmount serializes the condition code and amount of a fungible condition
+func writeConditionAmount(w io.Writer, condCode byte, amount uint64) error {
+	if _, err := w.Write([]byte{condCode}); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.BigEndian, amount)
+}
+
+// writeAddress serializes a StacksAddress as version byte followed by hash
+func writeAddress(w io.Writer, addr address.StacksAddress) error {
+	if _, err := w.Write([]byte{addr.Version}); err != nil {
+		return err
+	}
+	_, err := w.Write(addr.Hash160[:])
+	return err
+}
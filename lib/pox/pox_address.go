@@ -0,0 +1,178 @@
+// Package pox implements the data types of the PoX contracts
+package pox
+
+import (
+	"fmt"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+// PoX address versions, as the version buffer of a pox-addr tuple
+const (
+	AddressVersionP2PKH      byte = 0x00
+	AddressVersionP2SH       byte = 0x01
+	AddressVersionP2SHP2WPKH byte = 0x02
+	AddressVersionP2SHP2WSH  byte = 0x03
+	AddressVersionP2WPKH     byte = 0x04
+	AddressVersionP2WSH      byte = 0x05
+	AddressVersionP2TR       byte = 0x06
+)
+
+// Tuple keys of a pox-addr
+const (
+	TupleKeyVersion   clarity_value.ClarityName = "version"
+	TupleKeyHashBytes clarity_value.ClarityName = "hashbytes"
+)
+
+// Address is a PoX reward address: a version and the hash or witness program
+// the Bitcoin output pays to
+type Address struct {
+	Version   byte
+	HashBytes []byte
+}
+
+// hashBytesLength returns the length of the hash bytes for the version
+func hashBytesLength(version byte) (int, error) {
+	switch version {
+	case AddressVersionP2PKH, AddressVersionP2SH, AddressVersionP2SHP2WPKH, AddressVersionP2SHP2WSH, AddressVersionP2WPKH:
+		return 20, nil
+	case AddressVersionP2WSH, AddressVersionP2TR:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("invalid PoX address version: %d", version)
+	}
+}
+
+// Validate checks the version and the hash bytes length for the version
+func (a Address) Validate() error {
+	length, err := hashBytesLength(a.Version)
+	if err != nil {
+		return err
+	}
+	if len(a.HashBytes) != length {
+		return fmt.Errorf("invalid PoX address: version %d requires %d hash bytes, got %d", a.Version, length, len(a.HashBytes))
+	}
+	return nil
+}
+
+// AddressFromTuple reads a PoX address from a {version: (buff 1), hashbytes: (buff 32)} tuple
+func AddressFromTuple(tuple clarity_value.TupleValue) (Address, error) {
+	if len(tuple) != 2 {
+		return Address{}, fmt.Errorf("invalid PoX address tuple: %d fields", len(tuple))
+	}
+	version, err := tupleBuffer(tuple, TupleKeyVersion)
+	if err != nil {
+		return Address{}, err
+	}
+	if len(version) != 1 {
+		return Address{}, fmt.Errorf("invalid PoX address tuple: version is %d bytes", len(version))
+	}
+	hashBytes, err := tupleBuffer(tuple, TupleKeyHashBytes)
+	if err != nil {
+		return Address{}, err
+	}
+
+	addr := Address{Version: version[0], HashBytes: append([]byte(nil), hashBytes...)}
+	if err := addr.Validate(); err != nil {
+		return Address{}, err
+	}
+	return addr, nil
+}
+
+// tupleBuffer returns the buffer value of the tuple field
+func tupleBuffer(tuple clarity_value.TupleValue, key clarity_value.ClarityName) (clarity_value.BufferValue, error) {
+	field, ok := tuple[key]
+	if !ok {
+		return nil, fmt.Errorf("invalid PoX address tuple: missing %s", key)
+	}
+	buffer, ok := field.Value.(clarity_value.BufferValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid PoX address tuple: %s is %s, not a buffer", key, field.Value.TypeSignature())
+	}
+	return buffer, nil
+}
+
+// Tuple returns the address as a pox-addr tuple
+func (a Address) Tuple() (clarity_value.TupleValue, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return clarity_value.TupleValue{
+		TupleKeyVersion:   clarity_value.NewClarityValue(clarity_value.BufferValue{a.Version}),
+		TupleKeyHashBytes: clarity_value.NewClarityValue(clarity_value.BufferValue(append([]byte(nil), a.HashBytes...))),
+	}, nil
+}
+
+// AddressFromBitcoin reads a PoX address from a Bitcoin address. A P2SH
+// address does not reveal whether it wraps a segwit program, so it is always
+// read as AddressVersionP2SH.
+func AddressFromBitcoin(btcAddress string) (Address, error) {
+	decoded, err := address.DecodeBitcoinAddress(btcAddress)
+	if err != nil {
+		return Address{}, err
+	}
+
+	switch decoded.AddrType {
+	case address.PublicKeyHash:
+		return Address{Version: AddressVersionP2PKH, HashBytes: append([]byte(nil), decoded.Hash160Bytes[:]...)}, nil
+	case address.ScriptHash:
+		return Address{Version: AddressVersionP2SH, HashBytes: append([]byte(nil), decoded.Hash160Bytes[:]...)}, nil
+	case address.WitnessPubKeyHash:
+		return Address{Version: AddressVersionP2WPKH, HashBytes: decoded.WitnessProgram}, nil
+	case address.WitnessScriptHash:
+		return Address{Version: AddressVersionP2WSH, HashBytes: decoded.WitnessProgram}, nil
+	case address.Taproot:
+		return Address{Version: AddressVersionP2TR, HashBytes: decoded.WitnessProgram}, nil
+	default:
+		return Address{}, fmt.Errorf("no PoX address version for Bitcoin address: %s", btcAddress)
+	}
+}
+
+// BitcoinAddress returns the address as a Bitcoin address on the network:
+// base58check for the legacy and P2SH-wrapped versions, bech32 or bech32m for
+// the native segwit ones
+func (a Address) BitcoinAddress(network address.BitcoinNetworkType) (string, error) {
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+
+	btcAddress := &address.BitcoinAddress{NetworkID: network}
+	switch a.Version {
+	case AddressVersionP2PKH:
+		btcAddress.AddrType = address.PublicKeyHash
+		copy(btcAddress.Hash160Bytes[:], a.HashBytes)
+	case AddressVersionP2SH, AddressVersionP2SHP2WPKH, AddressVersionP2SHP2WSH:
+		btcAddress.AddrType = address.ScriptHash
+		copy(btcAddress.Hash160Bytes[:], a.HashBytes)
+	case AddressVersionP2WPKH:
+		btcAddress.AddrType = address.WitnessPubKeyHash
+		btcAddress.WitnessProgram = a.HashBytes
+	case AddressVersionP2WSH:
+		btcAddress.AddrType = address.WitnessScriptHash
+		btcAddress.WitnessProgram = a.HashBytes
+	case AddressVersionP2TR:
+		btcAddress.AddrType = address.Taproot
+		btcAddress.WitnessVersion = 1
+		btcAddress.WitnessProgram = a.HashBytes
+	}
+	return address.EncodeBitcoinAddress(btcAddress)
+}
+
+// TupleToBitcoinAddress converts a pox-addr tuple to a Bitcoin address on the network
+func TupleToBitcoinAddress(tuple clarity_value.TupleValue, network address.BitcoinNetworkType) (string, error) {
+	addr, err := AddressFromTuple(tuple)
+	if err != nil {
+		return "", err
+	}
+	return addr.BitcoinAddress(network)
+}
+
+// BitcoinAddressToTuple converts a Bitcoin address to a pox-addr tuple
+func BitcoinAddressToTuple(btcAddress string) (clarity_value.TupleValue, error) {
+	addr, err := AddressFromBitcoin(btcAddress)
+	if err != nil {
+		return nil, err
+	}
+	return addr.Tuple()
+}
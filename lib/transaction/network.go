@@ -0,0 +1,57 @@
+package transaction
+
+import (
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/post_condition"
+)
+
+// CheckNetwork checks that the transaction belongs to the network: its version
+// and chain ID are the network's, and so are the versions of the addresses in
+// its payload and post conditions
+func (tx *StacksTransaction) CheckNetwork(n network.Network) error {
+	if err := n.CheckTransactionVersion(tx.Version); err != nil {
+		return err
+	}
+	if err := n.CheckChainID(tx.ChainID); err != nil {
+		return err
+	}
+	for _, addr := range tx.addresses() {
+		if err := addr.CheckNetwork(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addresses returns the addresses the payload and post conditions refer to
+func (tx *StacksTransaction) addresses() []address.StacksAddress {
+	var addresses []address.StacksAddress
+	principal := func(p *PrincipalData) {
+		switch {
+		case p == nil:
+		case p.StandardData != nil:
+			addresses = append(addresses, address.NewStacksAddress(p.StandardData.Version, p.StandardData.Address))
+		case p.ContractData != nil:
+			addresses = append(addresses, address.NewStacksAddress(p.ContractData.Issuer.Version, p.ContractData.Issuer.Address))
+		}
+	}
+
+	if tx.Payload.TokenTransfer != nil {
+		principal(&tx.Payload.TokenTransfer.Recipient)
+	}
+	if tx.Payload.ContractCall != nil {
+		addresses = append(addresses, address.NewStacksAddress(tx.Payload.ContractCall.Address.Version, tx.Payload.ContractCall.Address.Hash160))
+	}
+	principal(tx.Payload.AltRecipient)
+
+	for _, pc := range tx.PostConditions {
+		if pc.Principal.Type == post_condition.PrincipalStandard || pc.Principal.Type == post_condition.PrincipalContract {
+			addresses = append(addresses, pc.Principal.Address)
+		}
+		if pc.Type == post_condition.AssetInfoFungible || pc.Type == post_condition.AssetInfoNonfungible {
+			addresses = append(addresses, pc.Asset.Address)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,372 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/post_condition"
+)
+
+// Serialize encodes the transaction into the consensus wire format.
+//
+// Post conditions are serialized from PostConditions and contract call
+// arguments from their Values, so edits to either are reflected in the
+// output; PostConditionsSerialized and SerializedBytes are not consulted.
+// A singlesig spending condition without a signature or key encoding is
+// written with an empty signature and a compressed key encoding, as an
+// unsigned transaction is.
+func (tx *StacksTransaction) Serialize() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeTransaction(&buf, tx); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the serialized transaction to w, as Serialize encodes it
+func (tx *StacksTransaction) WriteTo(w io.Writer) (int64, error) {
+	serialized, err := tx.Serialize()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(serialized)
+	return int64(n), err
+}
+
+// writeField writes a fixed-size big-endian field
+func writeField(w io.Writer, data interface{}) error {
+	return binary.Write(w, binary.BigEndian, data)
+}
+
+// writeName writes a length-prefixed name
+func writeName(w io.Writer, field string, name []byte) error {
+	if len(name) > clarity_value.MaxStringLen {
+		return fmt.Errorf("failed to serialize %s: too long: %d", field, len(name))
+	}
+	if err := writeField(w, uint8(len(name))); err != nil {
+		return err
+	}
+	_, err := w.Write(name)
+	return err
+}
+
+func writeTransaction(w io.Writer, tx *StacksTransaction) error {
+	if err := writeField(w, tx.Version); err != nil {
+		return err
+	}
+	if err := writeField(w, tx.ChainID); err != nil {
+		return err
+	}
+	if err := writeTransactionAuth(w, tx.Auth); err != nil {
+		return err
+	}
+	if err := writeField(w, tx.AnchorMode); err != nil {
+		return err
+	}
+	if err := writeField(w, tx.PostConditionMode); err != nil {
+		return err
+	}
+
+	if err := writeField(w, uint32(len(tx.PostConditions))); err != nil {
+		return err
+	}
+	for _, postCondition := range tx.PostConditions {
+		if err := post_condition.WritePostCondition(w, postCondition); err != nil {
+			return err
+		}
+	}
+
+	return writeTransactionPayload(w, tx.Payload)
+}
+
+func writeTransactionAuth(w io.Writer, auth TransactionAuth) error {
+	if auth.AuthType != TransactionAuthFlagStandard && auth.AuthType != TransactionAuthFlagSponsored {
+		return fmt.Errorf("failed to serialize auth: unknown auth type: %d", auth.AuthType)
+	}
+	if err := writeField(w, auth.AuthType); err != nil {
+		return err
+	}
+
+	if err := writeTransactionSpendingCondition(w, auth.SpendingCondition); err != nil {
+		return err
+	}
+
+	if auth.AuthType == TransactionAuthFlagSponsored {
+		if auth.SponsorSpendingCondition == nil {
+			return fmt.Errorf("failed to serialize auth: missing sponsor spending condition")
+		}
+		return writeTransactionSpendingCondition(w, *auth.SponsorSpendingCondition)
+	}
+
+	return nil
+}
+
+func writeTransactionSpendingCondition(w io.Writer, condition TransactionSpendingCondition) error {
+	if err := writeField(w, condition.HashMode); err != nil {
+		return err
+	}
+	if _, err := w.Write(condition.Signer[:]); err != nil {
+		return err
+	}
+	if err := writeField(w, condition.Nonce); err != nil {
+		return err
+	}
+	if err := writeField(w, condition.Fee); err != nil {
+		return err
+	}
+
+	switch condition.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		keyEncoding := PublicKeyEncodingCompressed
+		if condition.KeyEncoding != nil {
+			keyEncoding = *condition.KeyEncoding
+		}
+		if err := writeField(w, keyEncoding); err != nil {
+			return err
+		}
+
+		var signature [65]byte
+		if condition.Signature != nil {
+			signature = *condition.Signature
+		}
+		_, err := w.Write(signature[:])
+		return err
+
+	case MultisigHashModeP2SH, MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSH, MultisigHashModeP2WSHNonSequential:
+		if condition.SignaturesRequired == nil {
+			return fmt.Errorf("failed to serialize spending condition: missing signatures required")
+		}
+		if err := writeField(w, uint32(len(condition.Fields))); err != nil {
+			return err
+		}
+		for _, field := range condition.Fields {
+			if err := writeTransactionAuthField(w, field); err != nil {
+				return err
+			}
+		}
+		return writeField(w, *condition.SignaturesRequired)
+
+	default:
+		return fmt.Errorf("failed to serialize spending condition: unknown hash mode: %d", condition.HashMode)
+	}
+}
+
+func writeTransactionAuthField(w io.Writer, field TransactionAuthField) error {
+	if err := writeField(w, field.FieldID); err != nil {
+		return err
+	}
+
+	switch field.FieldID {
+	case AuthFieldIDPublicKeyCompressed, AuthFieldIDPublicKeyUncompressed:
+		if field.PublicKey == nil {
+			return fmt.Errorf("failed to serialize auth field: missing public key")
+		}
+		_, err := w.Write(field.PublicKey[:])
+		return err
+
+	case AuthFieldIDSignatureCompressed, AuthFieldIDSignatureUncompressed:
+		if field.Signature == nil {
+			return fmt.Errorf("failed to serialize auth field: missing signature")
+		}
+		_, err := w.Write(field.Signature[:])
+		return err
+
+	default:
+		return fmt.Errorf("failed to serialize auth field: unknown auth field ID: %d", field.FieldID)
+	}
+}
+
+func writeTransactionPayload(w io.Writer, payload TransactionPayload) error {
+	if err := writeField(w, payload.PayloadType); err != nil {
+		return err
+	}
+
+	switch payload.PayloadType {
+	case TransactionPayloadIDTokenTransfer:
+		if payload.TokenTransfer == nil {
+			return fmt.Errorf("failed to serialize payload: missing token transfer")
+		}
+		if err := writePrincipalData(w, payload.TokenTransfer.Recipient); err != nil {
+			return err
+		}
+		if err := writeField(w, payload.TokenTransfer.Amount); err != nil {
+			return err
+		}
+		_, err := w.Write(payload.TokenTransfer.Memo[:])
+		return err
+
+	case TransactionPayloadIDContractCall:
+		if payload.ContractCall == nil {
+			return fmt.Errorf("failed to serialize payload: missing contract call")
+		}
+		return writeContractCallPayload(w, *payload.ContractCall)
+
+	case TransactionPayloadIDSmartContract:
+		if payload.SmartContract == nil {
+			return fmt.Errorf("failed to serialize payload: missing smart contract")
+		}
+		return writeSmartContractPayload(w, *payload.SmartContract)
+
+	case TransactionPayloadIDPoisonMicroblock:
+		if payload.PoisonMicroblock == nil {
+			return fmt.Errorf("failed to serialize payload: missing poison microblock")
+		}
+		if err := writeMicroblockHeader(w, payload.PoisonMicroblock.Header1); err != nil {
+			return err
+		}
+		return writeMicroblockHeader(w, payload.PoisonMicroblock.Header2)
+
+	case TransactionPayloadIDCoinbase:
+		if payload.Coinbase == nil {
+			return fmt.Errorf("failed to serialize payload: missing coinbase")
+		}
+		_, err := w.Write(payload.Coinbase.Data[:])
+		return err
+
+	case TransactionPayloadIDCoinbaseToAltRecipient:
+		if payload.Coinbase == nil || payload.AltRecipient == nil {
+			return fmt.Errorf("failed to serialize payload: missing coinbase or alt recipient")
+		}
+		if _, err := w.Write(payload.Coinbase.Data[:]); err != nil {
+			return err
+		}
+		return writePrincipalData(w, *payload.AltRecipient)
+
+	case TransactionPayloadIDVersionedSmartContract:
+		if payload.SmartContract == nil || payload.ClarityVersion == nil {
+			return fmt.Errorf("failed to serialize payload: missing smart contract or clarity version")
+		}
+		if err := writeField(w, *payload.ClarityVersion); err != nil {
+			return err
+		}
+		return writeSmartContractPayload(w, *payload.SmartContract)
+
+	case TransactionPayloadIDTenureChange:
+		if payload.TenureChange == nil {
+			return fmt.Errorf("failed to serialize payload: missing tenure change")
+		}
+		return writeField(w, payload.TenureChange)
+
+	case TransactionPayloadIDNakamotoCoinbase:
+		if payload.Coinbase == nil || payload.VRFProof == nil {
+			return fmt.Errorf("failed to serialize payload: missing coinbase or VRF proof")
+		}
+		if _, err := w.Write(payload.Coinbase.Data[:]); err != nil {
+			return err
+		}
+
+		// The alt recipient is serialized as an optional Clarity principal
+		if payload.AltRecipient == nil {
+			if err := writeField(w, uint8(clarity_value.PrefixOptionalNone)); err != nil {
+				return err
+			}
+		} else {
+			if err := writeField(w, uint8(clarity_value.PrefixOptionalSome)); err != nil {
+				return err
+			}
+			if err := writePrincipalData(w, *payload.AltRecipient); err != nil {
+				return err
+			}
+		}
+
+		if err := writeField(w, uint32(len(*payload.VRFProof))); err != nil {
+			return err
+		}
+		_, err := w.Write(*payload.VRFProof)
+		return err
+
+	default:
+		return fmt.Errorf("failed to serialize payload: unknown payload type: %d", payload.PayloadType)
+	}
+}
+
+func writeContractCallPayload(w io.Writer, payload ContractCallPayload) error {
+	if err := writeField(w, payload.Address.Version); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload.Address.Hash160[:]); err != nil {
+		return err
+	}
+	if err := writeName(w, "contract name", payload.ContractName); err != nil {
+		return err
+	}
+	if err := writeName(w, "function name", payload.FunctionName); err != nil {
+		return err
+	}
+
+	if err := writeField(w, uint32(len(payload.FunctionArgs))); err != nil {
+		return err
+	}
+	for _, arg := range payload.FunctionArgs {
+		if err := clarity_value.WriteClarityValue(w, arg.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeSmartContractPayload(w io.Writer, payload SmartContractPayload) error {
+	if err := writeName(w, "contract name", payload.Name); err != nil {
+		return err
+	}
+	if err := writeField(w, uint32(len(payload.CodeBody))); err != nil {
+		return err
+	}
+	_, err := w.Write(payload.CodeBody)
+	return err
+}
+
+func writeMicroblockHeader(w io.Writer, header StacksMicroblockHeader) error {
+	if err := writeField(w, header.Version); err != nil {
+		return err
+	}
+	if err := writeField(w, header.Sequence); err != nil {
+		return err
+	}
+	if _, err := w.Write(header.PrevBlock[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(header.TxMerkleRoot[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(header.Signature[:])
+	return err
+}
+
+func writePrincipalData(w io.Writer, principal PrincipalData) error {
+	if err := writeField(w, principal.Type); err != nil {
+		return err
+	}
+
+	switch principal.Type {
+	case PrincipalTypeStandard:
+		if principal.StandardData == nil {
+			return fmt.Errorf("failed to serialize principal: missing standard data")
+		}
+		return writeStandardPrincipalData(w, *principal.StandardData)
+
+	case PrincipalTypeContract:
+		if principal.ContractData == nil {
+			return fmt.Errorf("failed to serialize principal: missing contract data")
+		}
+		if err := writeStandardPrincipalData(w, principal.ContractData.Issuer); err != nil {
+			return err
+		}
+		return writeName(w, "contract name", principal.ContractData.Name)
+
+	default:
+		return fmt.Errorf("failed to serialize principal: invalid principal type: %d", principal.Type)
+	}
+}
+
+func writeStandardPrincipalData(w io.Writer, data StandardPrincipalData) error {
+	if err := writeField(w, data.Version); err != nil {
+		return err
+	}
+	_, err := w.Write(data.Address[:])
+	return err
+}
@@ -0,0 +1,145 @@
+package transaction
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+)
+
+// Txid returns the transaction ID, the SHA-512/256 hash of the serialized transaction
+func (tx *StacksTransaction) Txid() ([32]byte, error) {
+	serialized, err := tx.Serialize()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha512.Sum512_256(serialized), nil
+}
+
+// InitialSighash returns the sighash the first origin signature commits to.
+//
+// It is the txid of the transaction with the origin spending condition cleared
+// (fee, nonce and signature or multisig fields) and, for sponsored transactions,
+// the sponsor spending condition replaced with an empty singlesig condition, so
+// the origin can sign before the sponsor and its fee are known.
+func (tx *StacksTransaction) InitialSighash() ([32]byte, error) {
+	initial := *tx
+	initial.Auth.SpendingCondition = clearSpendingCondition(tx.Auth.SpendingCondition)
+	if tx.Auth.AuthType == TransactionAuthFlagSponsored {
+		sponsor := initialSighashSpendingCondition()
+		initial.Auth.SponsorSpendingCondition = &sponsor
+	}
+	return initial.Txid()
+}
+
+// SighashPresign returns the hash a signer signs: the current sighash followed by
+// the auth flag of the condition being signed, its fee and its nonce
+func SighashPresign(curSighash [32]byte, authFlag uint8, fee uint64, nonce uint64) [32]byte {
+	var data [32 + 1 + 8 + 8]byte
+	copy(data[:32], curSighash[:])
+	data[32] = authFlag
+	binary.BigEndian.PutUint64(data[33:41], fee)
+	binary.BigEndian.PutUint64(data[41:49], nonce)
+	return sha512.Sum512_256(data[:])
+}
+
+// SighashPostsign returns the sighash after a signature: the presign sighash
+// followed by the signer's public key encoding and the signature
+func SighashPostsign(presignSighash [32]byte, keyEncoding uint8, signature [65]byte) [32]byte {
+	var data [32 + 1 + 65]byte
+	copy(data[:32], presignSighash[:])
+	data[32] = keyEncoding
+	copy(data[33:], signature[:])
+	return sha512.Sum512_256(data[:])
+}
+
+// Sighashes walks the sighash chain of the spending condition's signatures,
+// starting from curSighash, and returns the presign sighash each signature was
+// made over, in order, along with the sighash the next condition starts from.
+//
+// Origin conditions are signed with TransactionAuthFlagStandard, starting from
+// the initial sighash; the sponsor condition is signed with
+// TransactionAuthFlagSponsored, starting from the origin's next sighash.
+// Sequential multisig signers each sign the previous signer's postsign sighash,
+// while non-sequential signers all sign the same presign sighash and leave
+// curSighash unchanged.
+func (c TransactionSpendingCondition) Sighashes(curSighash [32]byte, authFlag uint8) ([][32]byte, [32]byte, error) {
+	switch c.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		if c.Signature == nil {
+			return nil, [32]byte{}, fmt.Errorf("singlesig spending condition is missing its signature")
+		}
+		keyEncoding := PublicKeyEncodingCompressed
+		if c.KeyEncoding != nil {
+			keyEncoding = *c.KeyEncoding
+		}
+		presign := SighashPresign(curSighash, authFlag, c.Fee, c.Nonce)
+		return [][32]byte{presign}, SighashPostsign(presign, keyEncoding, *c.Signature), nil
+
+	case MultisigHashModeP2SH, MultisigHashModeP2WSH:
+		var presigns [][32]byte
+		for _, field := range c.Fields {
+			if !field.isSignature() {
+				continue
+			}
+			if field.Signature == nil {
+				return nil, [32]byte{}, fmt.Errorf("auth field is missing its signature")
+			}
+			presign := SighashPresign(curSighash, authFlag, c.Fee, c.Nonce)
+			presigns = append(presigns, presign)
+			curSighash = SighashPostsign(presign, field.keyEncoding(), *field.Signature)
+		}
+		return presigns, curSighash, nil
+
+	case MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSHNonSequential:
+		presign := SighashPresign(curSighash, authFlag, c.Fee, c.Nonce)
+		var presigns [][32]byte
+		for _, field := range c.Fields {
+			if field.isSignature() {
+				presigns = append(presigns, presign)
+			}
+		}
+		return presigns, curSighash, nil
+
+	default:
+		return nil, [32]byte{}, fmt.Errorf("unknown hash mode: %d", c.HashMode)
+	}
+}
+
+// isSignature reports whether the auth field holds a signature rather than a public key
+func (f TransactionAuthField) isSignature() bool {
+	return f.FieldID == AuthFieldIDSignatureCompressed || f.FieldID == AuthFieldIDSignatureUncompressed
+}
+
+// keyEncoding returns the public key encoding given by the auth field ID
+func (f TransactionAuthField) keyEncoding() uint8 {
+	if f.FieldID == AuthFieldIDPublicKeyUncompressed || f.FieldID == AuthFieldIDSignatureUncompressed {
+		return PublicKeyEncodingUncompressed
+	}
+	return PublicKeyEncodingCompressed
+}
+
+// clearSpendingCondition returns a copy of the condition with its fee, nonce and
+// signatures cleared, as it is hashed for the initial sighash
+func clearSpendingCondition(condition TransactionSpendingCondition) TransactionSpendingCondition {
+	condition.Fee = 0
+	condition.Nonce = 0
+	switch condition.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		condition.Signature = &[65]byte{}
+	default:
+		condition.Fields = nil
+	}
+	return condition
+}
+
+// initialSighashSpendingCondition returns the placeholder a sponsor spending
+// condition is replaced with for the initial sighash
+func initialSighashSpendingCondition() TransactionSpendingCondition {
+	keyEncoding := PublicKeyEncodingCompressed
+	return TransactionSpendingCondition{
+		ConditionType: 0x00,
+		HashMode:      SinglesigHashModeP2PKH,
+		KeyEncoding:   &keyEncoding,
+		Signature:     &[65]byte{},
+	}
+}
@@ -0,0 +1,265 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// ErrSign matches every error returned by a Signer
+var ErrSign = errors.New("failed to sign")
+
+// Signer signs a transaction's origin and then its sponsor, appending to
+// their spending conditions as stacks-core's StacksTransactionSigner does.
+//
+// Private keys are 32 bytes, for an uncompressed public key, or 33 bytes
+// ending in 0x01, for a compressed public key, as in stacks-core.
+type Signer struct {
+	tx         *StacksTransaction
+	sighash    [32]byte
+	originDone bool
+}
+
+// NewSigner starts signing the origin of an unsigned transaction. The
+// transaction is copied, so it is left as it is.
+func NewSigner(tx *StacksTransaction) (*Signer, error) {
+	sighash, err := tx.InitialSighash()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+	return &Signer{tx: copyTransaction(tx), sighash: sighash}, nil
+}
+
+// NewSponsorSigner starts signing a sponsored transaction whose origin has
+// signed, with sponsor as its unsigned sponsor spending condition. The origin
+// signatures are verified, since the sponsor's signatures commit to them.
+func NewSponsorSigner(tx *StacksTransaction, sponsor TransactionSpendingCondition) (*Signer, error) {
+	if tx.Auth.AuthType != TransactionAuthFlagSponsored {
+		return nil, fmt.Errorf("%w: transaction is not sponsored", ErrSign)
+	}
+
+	signed := copyTransaction(tx)
+	sponsor.Fields = append([]TransactionAuthField(nil), sponsor.Fields...)
+	signed.Auth.SponsorSpendingCondition = &sponsor
+
+	if err := signed.VerifyOrigin(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+	initial, err := signed.InitialSighash()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+	_, sighash, err := signed.Auth.SpendingCondition.Sighashes(initial, TransactionAuthFlagStandard)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSign, err)
+	}
+
+	return &Signer{tx: signed, sighash: sighash, originDone: true}, nil
+}
+
+// SignOrigin signs the origin spending condition with privateKey. Singlesig
+// conditions take the signature; multisig conditions get a signature auth
+// field appended.
+func (s *Signer) SignOrigin(privateKey []byte) error {
+	if s.originDone {
+		return fmt.Errorf("%w: cannot sign the origin after the sponsor", ErrSign)
+	}
+	return s.signAndAppend(&s.tx.Auth.SpendingCondition, TransactionAuthFlagStandard, privateKey)
+}
+
+// AppendOriginPublicKey appends the public key of a multisig origin signer who
+// does not sign, in its place in the key order
+func (s *Signer) AppendOriginPublicKey(publicKey []byte) error {
+	if s.originDone {
+		return fmt.Errorf("%w: cannot append to the origin after the sponsor", ErrSign)
+	}
+	return appendPublicKey(&s.tx.Auth.SpendingCondition, publicKey)
+}
+
+// SignSponsor signs the sponsor spending condition with privateKey. The origin
+// cannot be signed afterwards.
+func (s *Signer) SignSponsor(privateKey []byte) error {
+	if s.tx.Auth.AuthType != TransactionAuthFlagSponsored || s.tx.Auth.SponsorSpendingCondition == nil {
+		return fmt.Errorf("%w: transaction is not sponsored", ErrSign)
+	}
+	s.originDone = true
+	return s.signAndAppend(s.tx.Auth.SponsorSpendingCondition, TransactionAuthFlagSponsored, privateKey)
+}
+
+// AppendSponsorPublicKey appends the public key of a multisig sponsor signer
+// who does not sign
+func (s *Signer) AppendSponsorPublicKey(publicKey []byte) error {
+	if s.tx.Auth.AuthType != TransactionAuthFlagSponsored || s.tx.Auth.SponsorSpendingCondition == nil {
+		return fmt.Errorf("%w: transaction is not sponsored", ErrSign)
+	}
+	s.originDone = true
+	return appendPublicKey(s.tx.Auth.SponsorSpendingCondition, publicKey)
+}
+
+// Transaction returns the transaction as signed so far
+func (s *Signer) Transaction() *StacksTransaction {
+	return s.tx
+}
+
+// signAndAppend signs the condition's next presign sighash and advances the
+// signer's sighash along the chain
+func (s *Signer) signAndAppend(condition *TransactionSpendingCondition, authFlag uint8, privateKey []byte) error {
+	key, compressed, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	keyEncoding := PublicKeyEncodingUncompressed
+	if compressed {
+		keyEncoding = PublicKeyEncodingCompressed
+	}
+
+	presign := SighashPresign(s.sighash, authFlag, condition.Fee, condition.Nonce)
+	signature := signHash(key, presign)
+	postsign := SighashPostsign(presign, keyEncoding, signature)
+
+	switch condition.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		if condition.Signature != nil && *condition.Signature != ([65]byte{}) {
+			return fmt.Errorf("%w: spending condition is already signed", ErrSign)
+		}
+		publicKey := serializePrivateKeyPublicKey(key, compressed)
+		signer, err := signerHash(condition.HashMode, [][]byte{publicKey}, nil)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrSign, err)
+		}
+		if signer != condition.Signer {
+			return fmt.Errorf("%w: private key does not match signer %x", ErrSign, condition.Signer)
+		}
+		condition.KeyEncoding = &keyEncoding
+		condition.Signature = &signature
+		s.sighash = postsign
+
+	case MultisigHashModeP2SH, MultisigHashModeP2WSH, MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSHNonSequential:
+		if condition.SignaturesRequired != nil && countSignatures(condition.Fields) >= int(*condition.SignaturesRequired) {
+			return fmt.Errorf("%w: spending condition already has %d signatures", ErrSign, *condition.SignaturesRequired)
+		}
+		fieldID := AuthFieldIDSignatureUncompressed
+		if compressed {
+			fieldID = AuthFieldIDSignatureCompressed
+		}
+		condition.Fields = append(condition.Fields, TransactionAuthField{FieldID: fieldID, Signature: &signature})
+
+		// Non-sequential signers all sign the same sighash
+		if condition.HashMode == MultisigHashModeP2SH || condition.HashMode == MultisigHashModeP2WSH {
+			s.sighash = postsign
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown hash mode: %d", ErrSign, condition.HashMode)
+	}
+
+	return nil
+}
+
+// appendPublicKey appends a public key auth field to a multisig condition
+func appendPublicKey(condition *TransactionSpendingCondition, publicKey []byte) error {
+	switch condition.HashMode {
+	case MultisigHashModeP2SH, MultisigHashModeP2WSH, MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSHNonSequential:
+	default:
+		return fmt.Errorf("%w: cannot append a public key to a singlesig spending condition", ErrSign)
+	}
+	parsed, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: invalid public key: %w", ErrSign, err)
+	}
+
+	// Auth fields hold the compressed form, with the field ID giving the encoding
+	fieldID := AuthFieldIDPublicKeyCompressed
+	if len(publicKey) != secp256k1.PubKeyBytesLenCompressed {
+		fieldID = AuthFieldIDPublicKeyUncompressed
+	}
+	var compressed [33]byte
+	copy(compressed[:], parsed.SerializeCompressed())
+	condition.Fields = append(condition.Fields, TransactionAuthField{FieldID: fieldID, PublicKey: &compressed})
+	return nil
+}
+
+// SignMessageHash signs a 32-byte hash with privateKey using an RFC 6979
+// deterministic nonce, returning a recoverable signature: the recovery ID
+// followed by r and s
+func SignMessageHash(privateKey []byte, hash [32]byte) (MessageSignature, error) {
+	key, _, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return MessageSignature{}, err
+	}
+	return MessageSignature(signHash(key, hash)), nil
+}
+
+// PrivateKeyToPublicKey returns the public key of privateKey, compressed for a
+// 33-byte private key ending in 0x01 and uncompressed otherwise
+func PrivateKeyToPublicKey(privateKey []byte) ([]byte, error) {
+	key, compressed, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return serializePrivateKeyPublicKey(key, compressed), nil
+}
+
+// signHash signs hash, converting ecdsa.SignCompact's recovery code (27 plus
+// the recovery ID) to the recovery ID signatures start with
+func signHash(key *secp256k1.PrivateKey, hash [32]byte) [65]byte {
+	compact := ecdsa.SignCompact(key, hash[:], false)
+	var signature [65]byte
+	signature[0] = compact[0] - 27
+	copy(signature[1:], compact[1:])
+	return signature
+}
+
+// parsePrivateKey parses a 32-byte private key, or a 33-byte private key
+// with the compressed public key suffix 0x01
+func parsePrivateKey(privateKey []byte) (*secp256k1.PrivateKey, bool, error) {
+	compressed := false
+	switch {
+	case len(privateKey) == secp256k1.PrivKeyBytesLen:
+	case len(privateKey) == secp256k1.PrivKeyBytesLen+1 && privateKey[secp256k1.PrivKeyBytesLen] == 0x01:
+		compressed = true
+		privateKey = privateKey[:secp256k1.PrivKeyBytesLen]
+	default:
+		return nil, false, fmt.Errorf("%w: invalid private key length: %d", ErrSign, len(privateKey))
+	}
+
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(privateKey); overflow || scalar.IsZero() {
+		return nil, false, fmt.Errorf("%w: private key out of range", ErrSign)
+	}
+	return secp256k1.NewPrivateKey(&scalar), compressed, nil
+}
+
+// serializePrivateKeyPublicKey serializes the private key's public key
+func serializePrivateKeyPublicKey(key *secp256k1.PrivateKey, compressed bool) []byte {
+	if compressed {
+		return key.PubKey().SerializeCompressed()
+	}
+	return key.PubKey().SerializeUncompressed()
+}
+
+// countSignatures counts the signature auth fields
+func countSignatures(fields []TransactionAuthField) int {
+	count := 0
+	for _, field := range fields {
+		if field.isSignature() {
+			count++
+		}
+	}
+	return count
+}
+
+// copyTransaction copies a transaction deeply enough for signing to leave the
+// original unchanged
+func copyTransaction(tx *StacksTransaction) *StacksTransaction {
+	copied := *tx
+	copied.Auth.SpendingCondition.Fields = append([]TransactionAuthField(nil), tx.Auth.SpendingCondition.Fields...)
+	if tx.Auth.SponsorSpendingCondition != nil {
+		sponsor := *tx.Auth.SponsorSpendingCondition
+		sponsor.Fields = append([]TransactionAuthField(nil), sponsor.Fields...)
+		copied.Auth.SponsorSpendingCondition = &sponsor
+	}
+	return &copied
+}
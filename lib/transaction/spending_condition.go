@@ -0,0 +1,115 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+// NewSinglesigSpendingCondition creates an unsigned P2PKH or P2WPKH spending
+// condition for a 33-byte compressed or 65-byte uncompressed public key
+func NewSinglesigSpendingCondition(hashMode uint8, publicKey []byte) (TransactionSpendingCondition, error) {
+	if hashMode != SinglesigHashModeP2PKH && hashMode != SinglesigHashModeP2WPKH {
+		return TransactionSpendingCondition{}, fmt.Errorf("invalid singlesig hash mode: %d", hashMode)
+	}
+	publicKey, keyEncoding, err := parsePublicKey(publicKey)
+	if err != nil {
+		return TransactionSpendingCondition{}, err
+	}
+	if hashMode == SinglesigHashModeP2WPKH && keyEncoding != PublicKeyEncodingCompressed {
+		return TransactionSpendingCondition{}, fmt.Errorf("P2WPKH requires a compressed public key")
+	}
+
+	signer, err := signerHash(hashMode, [][]byte{publicKey}, nil)
+	if err != nil {
+		return TransactionSpendingCondition{}, err
+	}
+	return TransactionSpendingCondition{
+		ConditionType: 0x00,
+		Signer:        signer,
+		HashMode:      hashMode,
+		KeyEncoding:   &keyEncoding,
+		Signature:     &[65]byte{},
+	}, nil
+}
+
+// NewMultisigSpendingCondition creates an unsigned multisig spending condition
+// requiring signaturesRequired signatures from publicKeys, in order. Its auth
+// fields are appended as the transaction is signed.
+func NewMultisigSpendingCondition(hashMode uint8, signaturesRequired uint16, publicKeys [][]byte) (TransactionSpendingCondition, error) {
+	switch hashMode {
+	case MultisigHashModeP2SH, MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSH, MultisigHashModeP2WSHNonSequential:
+	default:
+		return TransactionSpendingCondition{}, fmt.Errorf("invalid multisig hash mode: %d", hashMode)
+	}
+	if signaturesRequired == 0 || int(signaturesRequired) > len(publicKeys) {
+		return TransactionSpendingCondition{}, fmt.Errorf("invalid multisig: %d of %d signatures required", signaturesRequired, len(publicKeys))
+	}
+
+	serializedKeys := make([][]byte, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		serialized, keyEncoding, err := parsePublicKey(publicKey)
+		if err != nil {
+			return TransactionSpendingCondition{}, fmt.Errorf("public key %d: %w", i, err)
+		}
+		if keyEncoding != PublicKeyEncodingCompressed &&
+			(hashMode == MultisigHashModeP2WSH || hashMode == MultisigHashModeP2WSHNonSequential) {
+			return TransactionSpendingCondition{}, fmt.Errorf("P2WSH requires compressed public keys")
+		}
+		serializedKeys[i] = serialized
+	}
+
+	signer, err := signerHash(hashMode, serializedKeys, &signaturesRequired)
+	if err != nil {
+		return TransactionSpendingCondition{}, err
+	}
+	return TransactionSpendingCondition{
+		ConditionType:      0x01,
+		Signer:             signer,
+		HashMode:           hashMode,
+		SignaturesRequired: &signaturesRequired,
+	}, nil
+}
+
+// parsePublicKey checks that publicKey is a valid secp256k1 public key and
+// returns it in its canonical serialization, along with its encoding
+func parsePublicKey(publicKey []byte) ([]byte, uint8, error) {
+	parsed, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(publicKey) == secp256k1.PubKeyBytesLenCompressed {
+		return parsed.SerializeCompressed(), PublicKeyEncodingCompressed, nil
+	}
+	return parsed.SerializeUncompressed(), PublicKeyEncodingUncompressed, nil
+}
+
+// Address returns the address of the condition's signer, with the singlesig or
+// multisig version for its hash mode on mainnet or testnet
+func (c TransactionSpendingCondition) Address(mainnet bool) (address.StacksAddress, error) {
+	hashMode := address.AddressHashMode(c.HashMode)
+	version, err := hashMode.ToVersionTestnet()
+	if mainnet {
+		version, err = hashMode.ToVersionMainnet()
+	}
+	if err != nil {
+		return address.StacksAddress{}, err
+	}
+	return address.NewStacksAddress(version, c.Signer), nil
+}
+
+// OriginAddress returns the address of the origin on the transaction's network
+func (tx *StacksTransaction) OriginAddress() (address.StacksAddress, error) {
+	return tx.Auth.SpendingCondition.Address(tx.Version == TransactionVersionMainnet)
+}
+
+// SponsorAddress returns the address of the sponsor on the transaction's
+// network, failing for transactions that are not sponsored
+func (tx *StacksTransaction) SponsorAddress() (address.StacksAddress, error) {
+	if tx.Auth.AuthType != TransactionAuthFlagSponsored || tx.Auth.SponsorSpendingCondition == nil {
+		return address.StacksAddress{}, fmt.Errorf("transaction is not sponsored")
+	}
+	return tx.Auth.SponsorSpendingCondition.Address(tx.Version == TransactionVersionMainnet)
+}
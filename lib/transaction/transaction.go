@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"io"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/post_condition"
 )
 
 // Transaction version values
@@ -15,6 +18,12 @@ const (
 	TransactionVersionTestnet uint8 = 0x80
 )
 
+// Chain IDs
+const (
+	ChainIDMainnet uint32 = 0x00000001
+	ChainIDTestnet uint32 = 0x80000000
+)
+
 // Transaction anchor mode values
 const (
 	TransactionAnchorModeOnChainOnly  uint8 = 1
@@ -80,8 +89,12 @@ const (
 	ClarityVersion1 uint8 = 1
 	ClarityVersion2 uint8 = 2
 	ClarityVersion3 uint8 = 3
+	ClarityVersion4 uint8 = 4
 )
 
+// VRFProofLength is the length of the VRF proof in a Nakamoto coinbase
+const VRFProofLength = 80
+
 // Tenure change causes
 const (
 	TenureChangeCauseBlockFound uint8 = 0
@@ -96,16 +109,21 @@ const (
 
 // Error definitions
 var (
-	ErrDeserialize = errors.New("failed to deserialize")
+	// ErrDeserialize matches every decoding failure. Failures are
+	// *clarity_value.DeserializeErrors carrying the kind, byte offset and
+	// field path, e.g. "payload.contract_call.args[2]".
+	ErrDeserialize = clarity_value.ErrDeserialize
 )
 
 // StacksTransaction represents a Stacks blockchain transaction
 type StacksTransaction struct {
-	Version                  uint8
-	ChainID                  uint32
-	Auth                     TransactionAuth
-	AnchorMode               uint8
-	PostConditionMode        uint8
+	Version           uint8
+	ChainID           uint32
+	Auth              TransactionAuth
+	AnchorMode        uint8
+	PostConditionMode uint8
+	// PostConditionsSerialized holds the post condition mode, count and items as
+	// serialized, in the form accepted by post_condition.DecodeTxPostConditions
 	PostConditionsSerialized []byte
 	PostConditions           []TransactionPostCondition
 	Payload                  TransactionPayload
@@ -120,6 +138,7 @@ type TransactionAuth struct {
 
 // TransactionSpendingCondition represents a spending condition for a transaction
 type TransactionSpendingCondition struct {
+	// ConditionType is derived from the hash mode: 0x00 for singlesig, 0x01 for multisig
 	ConditionType      uint8
 	Signer             [20]byte
 	Nonce              uint64
@@ -168,7 +187,7 @@ type ContractCallPayload struct {
 	Address      StacksAddress
 	ContractName []byte
 	FunctionName []byte
-	FunctionArgs []ClarityValue
+	FunctionArgs []clarity_value.ClarityValue
 }
 
 // SmartContractPayload represents a smart contract deployment
@@ -234,15 +253,20 @@ type StacksAddress struct {
 	Hash160 [20]byte
 }
 
-// ClarityValue represents a Clarity language value
-type ClarityValue struct {
-	TypeID uint8
-	Data   []byte
-}
-
 // TransactionPostCondition represents a post condition in a transaction
-type TransactionPostCondition struct {
-	// Not implemented as it's not used in the test
+type TransactionPostCondition = post_condition.PostCondition
+
+// DecodeOptions configures transaction decoding
+type DecodeOptions struct {
+	// Lenient accepts transactions that stacks-core would reject because of a field
+	// value: unknown versions, anchor modes, post condition modes, key encodings,
+	// clarity versions and tenure change causes, invalid names, VRF proofs of the
+	// wrong length, and trailing bytes. Unknown type bytes that decide how the rest
+	// of the transaction is laid out are rejected either way.
+	Lenient bool
+	// Network, when set, rejects transactions that do not belong to the network,
+	// as checked by CheckNetwork once the transaction is decoded
+	Network *network.Network
 }
 
 // DecodeHex decodes a hex string to bytes
@@ -250,86 +274,184 @@ func DecodeHex(hexStr []byte) ([]byte, error) {
 	return hex.DecodeString(string(hexStr))
 }
 
-// DecodeTransaction decodes a Stacks transaction from a byte slice
+// DecodeTransaction decodes a Stacks transaction from a byte slice, rejecting
+// anything stacks-core would not accept, including trailing bytes
 func DecodeTransaction(data []byte) (*StacksTransaction, error) {
+	return DecodeTransactionWithOptions(data, DecodeOptions{})
+}
+
+// DecodeTransactionWithOptions decodes a Stacks transaction from a byte slice
+func DecodeTransactionWithOptions(data []byte, opts DecodeOptions) (*StacksTransaction, error) {
 	reader := bytes.NewReader(data)
-	return DecodeTransactionFromReader(reader)
+	tx, err := DecodeTransactionFromReaderWithOptions(reader, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Lenient && reader.Len() > 0 {
+		offset := int64(len(data) - reader.Len())
+		return nil, clarity_value.DeserializeErrorf(clarity_value.KindTrailingBytes, offset, "%d bytes after transaction", reader.Len())
+	}
+
+	return tx, nil
 }
 
-// DecodeTransactionFromReader decodes a Stacks transaction from a reader
+// DecodeTransactionFromReader decodes a Stacks transaction from a reader, rejecting
+// anything stacks-core would not accept. Reading stops at the end of the transaction.
 func DecodeTransactionFromReader(reader io.Reader) (*StacksTransaction, error) {
+	return DecodeTransactionFromReaderWithOptions(reader, DecodeOptions{})
+}
+
+// DecodeTransactionFromReaderWithOptions decodes a Stacks transaction from a reader
+func DecodeTransactionFromReaderWithOptions(reader io.Reader, opts DecodeOptions) (*StacksTransaction, error) {
 	var tx StacksTransaction
 	var err error
 
+	reader = clarity_value.NewOffsetReader(reader, 0)
+
 	// Decode version
-	if err = binary.Read(reader, binary.BigEndian, &tx.Version); err != nil {
-		return nil, fmt.Errorf("%w: version: %v", ErrDeserialize, err)
+	offset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "version", &tx.Version); err != nil {
+		return nil, err
+	}
+	if !opts.Lenient && tx.Version != TransactionVersionMainnet && tx.Version != TransactionVersionTestnet {
+		return nil, fieldError(clarity_value.KindInvalid, offset, "version", "unknown transaction version: %d", tx.Version)
 	}
 
 	// Decode chain ID
-	if err = binary.Read(reader, binary.BigEndian, &tx.ChainID); err != nil {
-		return nil, fmt.Errorf("%w: chain ID: %v", ErrDeserialize, err)
+	if err = readField(reader, "chain_id", &tx.ChainID); err != nil {
+		return nil, err
 	}
 
 	// Decode auth
-	if tx.Auth, err = decodeTransactionAuth(reader); err != nil {
-		return nil, fmt.Errorf("%w: auth: %v", ErrDeserialize, err)
+	if tx.Auth, err = decodeTransactionAuth(reader, opts); err != nil {
+		return nil, clarity_value.WithPathSegment(err, "auth")
 	}
 
 	// Decode anchor mode
-	if err = binary.Read(reader, binary.BigEndian, &tx.AnchorMode); err != nil {
-		return nil, fmt.Errorf("%w: anchor mode: %v", ErrDeserialize, err)
+	offset = clarity_value.InputOffset(reader)
+	if err = readField(reader, "anchor_mode", &tx.AnchorMode); err != nil {
+		return nil, err
 	}
-
-	// For the test vector, if anchor mode is 2, set it to 3 (Any)
-	if tx.AnchorMode == TransactionAnchorModeOffChainOnly {
-		tx.AnchorMode = TransactionAnchorModeAny
+	switch tx.AnchorMode {
+	case TransactionAnchorModeOnChainOnly, TransactionAnchorModeOffChainOnly, TransactionAnchorModeAny:
+	default:
+		if !opts.Lenient {
+			return nil, fieldError(clarity_value.KindInvalid, offset, "anchor_mode", "unknown anchor mode: %d", tx.AnchorMode)
+		}
 	}
 
-	// Decode post condition mode
-	if err = binary.Read(reader, binary.BigEndian, &tx.PostConditionMode); err != nil {
-		return nil, fmt.Errorf("%w: post condition mode: %v", ErrDeserialize, err)
+	// Decode post condition mode and post conditions, keeping their serialized bytes
+	var postConditions bytes.Buffer
+	pcReader := clarity_value.NewOffsetReader(io.TeeReader(reader, &postConditions), clarity_value.InputOffset(reader))
+	offset = clarity_value.InputOffset(reader)
+	if err = readField(pcReader, "post_condition_mode", &tx.PostConditionMode); err != nil {
+		return nil, err
+	}
+	if !opts.Lenient && tx.PostConditionMode != TransactionPostConditionModeAllow && tx.PostConditionMode != TransactionPostConditionModeDeny {
+		return nil, fieldError(clarity_value.KindInvalid, offset, "post_condition_mode", "unknown post condition mode: %d", tx.PostConditionMode)
 	}
 
-	// Decode post conditions serialized length
-	var postConditionsLength uint32
-	if err = binary.Read(reader, binary.BigEndian, &postConditionsLength); err != nil {
-		return nil, fmt.Errorf("%w: post conditions length: %v", ErrDeserialize, err)
+	var postConditionsCount uint32
+	if err = readField(pcReader, "post_conditions_count", &postConditionsCount); err != nil {
+		return nil, err
 	}
 
-	// Decode post conditions serialized data
-	tx.PostConditionsSerialized = make([]byte, postConditionsLength)
-	if _, err = io.ReadFull(reader, tx.PostConditionsSerialized); err != nil {
-		return nil, fmt.Errorf("%w: post conditions data: %v", ErrDeserialize, err)
+	// The count is untrusted, so grow the slice as conditions are decoded
+	tx.PostConditions = []TransactionPostCondition{}
+	for i := uint32(0); i < postConditionsCount; i++ {
+		postCondition, err := post_condition.DecodePostCondition(pcReader)
+		if err != nil {
+			return nil, clarity_value.WithPathSegment(err, fmt.Sprintf("post_conditions[%d]", i))
+		}
+		tx.PostConditions = append(tx.PostConditions, postCondition)
 	}
+	tx.PostConditionsSerialized = postConditions.Bytes()
 
 	// Decode payload
-	if tx.Payload, err = decodeTransactionPayload(reader); err != nil {
-		return nil, fmt.Errorf("%w: payload: %v", ErrDeserialize, err)
+	if tx.Payload, err = decodeTransactionPayload(reader, opts); err != nil {
+		return nil, clarity_value.WithPathSegment(err, "payload")
+	}
+
+	if opts.Network != nil {
+		if err := tx.CheckNetwork(*opts.Network); err != nil {
+			return nil, err
+		}
 	}
 
 	return &tx, nil
 }
 
-func decodeTransactionAuth(reader io.Reader) (TransactionAuth, error) {
+// readField reads a fixed-size big-endian field, naming it in the error on failure
+func readField(reader io.Reader, field string, data interface{}) error {
+	offset := clarity_value.InputOffset(reader)
+	if err := binary.Read(reader, binary.BigEndian, data); err != nil {
+		return clarity_value.WithPathSegment(clarity_value.WrapReadError(err, offset), field)
+	}
+	return nil
+}
+
+// fieldError reports a field at offset that holds a value stacks-core rejects
+func fieldError(kind clarity_value.DeserializeErrorKind, offset int64, field string, format string, args ...interface{}) error {
+	return clarity_value.WithPathSegment(clarity_value.DeserializeErrorf(kind, offset, format, args...), field)
+}
+
+// readName reads a length-prefixed name, checking it with validate unless decoding leniently
+func readName(reader io.Reader, field string, opts DecodeOptions, validate func(string) error) ([]byte, error) {
+	offset := clarity_value.InputOffset(reader)
+	var nameLen uint8
+	if err := readField(reader, field, &nameLen); err != nil {
+		return nil, err
+	}
+
+	name := make([]byte, nameLen)
+	if err := readField(reader, field, name); err != nil {
+		return nil, err
+	}
+
+	if !opts.Lenient {
+		if err := validate(string(name)); err != nil {
+			deserializeErr := &clarity_value.DeserializeError{Kind: clarity_value.KindInvalidName, Offset: offset, Err: err}
+			return nil, clarity_value.WithPathSegment(deserializeErr, field)
+		}
+	}
+
+	return name, nil
+}
+
+func validateContractName(name string) error {
+	_, err := clarity_value.ValidateContractName(name)
+	return err
+}
+
+func validateClarityName(name string) error {
+	_, err := clarity_value.ValidateClarityName(name)
+	return err
+}
+
+func decodeTransactionAuth(reader io.Reader, opts DecodeOptions) (TransactionAuth, error) {
 	var auth TransactionAuth
 	var err error
 
 	// Read auth type
-	if err = binary.Read(reader, binary.BigEndian, &auth.AuthType); err != nil {
-		return auth, fmt.Errorf("auth type: %v", err)
+	offset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "auth_type", &auth.AuthType); err != nil {
+		return auth, err
+	}
+	if auth.AuthType != TransactionAuthFlagStandard && auth.AuthType != TransactionAuthFlagSponsored {
+		return auth, fieldError(clarity_value.KindBadPrefix, offset, "auth_type", "unknown auth type: %d", auth.AuthType)
 	}
 
 	// Decode spending condition
-	if auth.SpendingCondition, err = decodeTransactionSpendingCondition(reader); err != nil {
-		return auth, fmt.Errorf("spending condition: %v", err)
+	if auth.SpendingCondition, err = decodeTransactionSpendingCondition(reader, opts); err != nil {
+		return auth, clarity_value.WithPathSegment(err, "spending_condition")
 	}
 
 	// If sponsored, decode sponsor spending condition
 	if auth.AuthType == TransactionAuthFlagSponsored {
-		sponsorCondition, err := decodeTransactionSpendingCondition(reader)
+		sponsorCondition, err := decodeTransactionSpendingCondition(reader, opts)
 		if err != nil {
-			return auth, fmt.Errorf("sponsor spending condition: %v", err)
+			return auth, clarity_value.WithPathSegment(err, "sponsor_spending_condition")
 		}
 		auth.SponsorSpendingCondition = &sponsorCondition
 	}
@@ -337,70 +459,86 @@ func decodeTransactionAuth(reader io.Reader) (TransactionAuth, error) {
 	return auth, nil
 }
 
-func decodeTransactionSpendingCondition(reader io.Reader) (TransactionSpendingCondition, error) {
+func decodeTransactionSpendingCondition(reader io.Reader, opts DecodeOptions) (TransactionSpendingCondition, error) {
 	var condition TransactionSpendingCondition
 	var err error
 
-	// Read condition type
-	if err = binary.Read(reader, binary.BigEndian, &condition.ConditionType); err != nil {
-		return condition, fmt.Errorf("condition type: %v", err)
-	}
-
 	// Read hash mode
-	if err = binary.Read(reader, binary.BigEndian, &condition.HashMode); err != nil {
-		return condition, fmt.Errorf("hash mode: %v", err)
+	hashModeOffset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "hash_mode", &condition.HashMode); err != nil {
+		return condition, err
 	}
 
 	// Read signer
-	if _, err = io.ReadFull(reader, condition.Signer[:]); err != nil {
-		return condition, fmt.Errorf("signer: %v", err)
+	if err = readField(reader, "signer", condition.Signer[:]); err != nil {
+		return condition, err
 	}
 
 	// Read nonce
-	if err = binary.Read(reader, binary.BigEndian, &condition.Nonce); err != nil {
-		return condition, fmt.Errorf("nonce: %v", err)
+	if err = readField(reader, "nonce", &condition.Nonce); err != nil {
+		return condition, err
 	}
 
 	// Read fee
-	if err = binary.Read(reader, binary.BigEndian, &condition.Fee); err != nil {
-		return condition, fmt.Errorf("fee: %v", err)
+	if err = readField(reader, "fee", &condition.Fee); err != nil {
+		return condition, err
 	}
 
-	// Handle singlesig or multisig based on condition type
-	if condition.ConditionType == 0x00 { // Singlesig
+	// Handle singlesig or multisig based on hash mode
+	switch condition.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		condition.ConditionType = 0x00
+
+		offset := clarity_value.InputOffset(reader)
 		var keyEncoding uint8
-		if err = binary.Read(reader, binary.BigEndian, &keyEncoding); err != nil {
-			return condition, fmt.Errorf("key encoding: %v", err)
+		if err = readField(reader, "key_encoding", &keyEncoding); err != nil {
+			return condition, err
 		}
 		condition.KeyEncoding = &keyEncoding
+		if !opts.Lenient {
+			if keyEncoding != PublicKeyEncodingCompressed && keyEncoding != PublicKeyEncodingUncompressed {
+				return condition, fieldError(clarity_value.KindInvalid, offset, "key_encoding", "unknown key encoding: %d", keyEncoding)
+			}
+			if condition.HashMode == SinglesigHashModeP2WPKH && keyEncoding != PublicKeyEncodingCompressed {
+				return condition, fieldError(clarity_value.KindInvalid, offset, "key_encoding", "P2WPKH requires a compressed key")
+			}
+		}
 
 		var signature [65]byte
-		if _, err = io.ReadFull(reader, signature[:]); err != nil {
-			return condition, fmt.Errorf("signature: %v", err)
+		if err = readField(reader, "signature", signature[:]); err != nil {
+			return condition, err
 		}
 		condition.Signature = &signature
-	} else if condition.ConditionType == 0x01 { // Multisig
-		var signaturesRequired uint16
-		if err = binary.Read(reader, binary.BigEndian, &signaturesRequired); err != nil {
-			return condition, fmt.Errorf("signatures required: %v", err)
-		}
-		condition.SignaturesRequired = &signaturesRequired
+	case MultisigHashModeP2SH, MultisigHashModeP2SHNonSequential, MultisigHashModeP2WSH, MultisigHashModeP2WSHNonSequential:
+		condition.ConditionType = 0x01
 
 		// Read number of auth fields
 		var fieldCount uint32
-		if err = binary.Read(reader, binary.BigEndian, &fieldCount); err != nil {
-			return condition, fmt.Errorf("field count: %v", err)
+		if err = readField(reader, "field_count", &fieldCount); err != nil {
+			return condition, err
 		}
 
-		// Read auth fields
-		condition.Fields = make([]TransactionAuthField, fieldCount)
+		// Read auth fields, growing the slice as they are decoded since the count is untrusted
+		segwit := condition.HashMode == MultisigHashModeP2WSH || condition.HashMode == MultisigHashModeP2WSHNonSequential
 		for i := uint32(0); i < fieldCount; i++ {
+			offset := clarity_value.InputOffset(reader)
 			field, err := decodeTransactionAuthField(reader)
 			if err != nil {
-				return condition, fmt.Errorf("auth field %d: %v", i, err)
+				return condition, clarity_value.WithPathSegment(err, fmt.Sprintf("fields[%d]", i))
 			}
-			condition.Fields[i] = field
+			if !opts.Lenient && segwit && *field.PublicKeyEncoding != PublicKeyEncodingCompressed {
+				return condition, fieldError(clarity_value.KindInvalid, offset, fmt.Sprintf("fields[%d]", i), "P2WSH requires compressed keys")
+			}
+			condition.Fields = append(condition.Fields, field)
 		}
+
+		var signaturesRequired uint16
+		if err = readField(reader, "signatures_required", &signaturesRequired); err != nil {
+			return condition, err
+		}
+		condition.SignaturesRequired = &signaturesRequired
+	default:
+		return condition, fieldError(clarity_value.KindBadPrefix, hashModeOffset, "hash_mode", "unknown hash mode: %d", condition.HashMode)
 	}
 
 	return condition, nil
@@ -411,246 +549,239 @@ func decodeTransactionAuthField(reader io.Reader) (TransactionAuthField, error)
 	var err error
 
 	// Read field ID
-	if err = binary.Read(reader, binary.BigEndian, &field.FieldID); err != nil {
-		return field, fmt.Errorf("field ID: %v", err)
+	offset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "field_id", &field.FieldID); err != nil {
+		return field, err
+	}
+
+	// The field ID carries the public key encoding; keys are always serialized compressed
+	encoding := PublicKeyEncodingCompressed
+	if field.FieldID == AuthFieldIDPublicKeyUncompressed || field.FieldID == AuthFieldIDSignatureUncompressed {
+		encoding = PublicKeyEncodingUncompressed
 	}
 
 	switch field.FieldID {
 	case AuthFieldIDPublicKeyCompressed, AuthFieldIDPublicKeyUncompressed:
+		field.PublicKeyEncoding = &encoding
+
 		var pubKey [33]byte
-		if _, err = io.ReadFull(reader, pubKey[:]); err != nil {
-			return field, fmt.Errorf("public key: %v", err)
+		if err = readField(reader, "public_key", pubKey[:]); err != nil {
+			return field, err
 		}
 		field.PublicKey = &pubKey
 	case AuthFieldIDSignatureCompressed, AuthFieldIDSignatureUncompressed:
-		var encoding uint8
-		if err = binary.Read(reader, binary.BigEndian, &encoding); err != nil {
-			return field, fmt.Errorf("signature encoding: %v", err)
-		}
 		field.PublicKeyEncoding = &encoding
 
 		var signature [65]byte
-		if _, err = io.ReadFull(reader, signature[:]); err != nil {
-			return field, fmt.Errorf("signature: %v", err)
+		if err = readField(reader, "signature", signature[:]); err != nil {
+			return field, err
 		}
 		field.Signature = &signature
+	default:
+		return field, fieldError(clarity_value.KindBadPrefix, offset, "field_id", "unknown auth field ID: %d", field.FieldID)
 	}
 
 	return field, nil
 }
 
-func decodeTransactionPayload(reader io.Reader) (TransactionPayload, error) {
+func decodeTransactionPayload(reader io.Reader, opts DecodeOptions) (TransactionPayload, error) {
 	var payload TransactionPayload
 	var err error
 
 	// Read payload type
-	if err = binary.Read(reader, binary.BigEndian, &payload.PayloadType); err != nil {
-		return payload, fmt.Errorf("payload type: %v", err)
-	}
-
-	// The test vector actually uses 0x83 (131 decimal) for token transfer
-	// For our purposes, we'll recognize this as a token transfer (0x00)
-	actualPayloadType := payload.PayloadType
-	if actualPayloadType == 131 {
-		payload.PayloadType = TransactionPayloadIDTokenTransfer
+	offset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "payload_type", &payload.PayloadType); err != nil {
+		return payload, err
 	}
 
 	switch payload.PayloadType {
 	case TransactionPayloadIDTokenTransfer:
-		tokenTransfer, err := decodeTokenTransferPayload(reader)
+		tokenTransfer, err := decodeTokenTransferPayload(reader, opts)
 		if err != nil {
-			return payload, fmt.Errorf("token transfer: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "token_transfer")
 		}
 		payload.TokenTransfer = &tokenTransfer
 	case TransactionPayloadIDContractCall:
-		contractCall, err := decodeContractCallPayload(reader)
+		contractCall, err := decodeContractCallPayload(reader, opts)
 		if err != nil {
-			return payload, fmt.Errorf("contract call: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "contract_call")
 		}
 		payload.ContractCall = &contractCall
 	case TransactionPayloadIDSmartContract:
-		smartContract, err := decodeSmartContractPayload(reader)
+		smartContract, err := decodeSmartContractPayload(reader, opts)
 		if err != nil {
-			return payload, fmt.Errorf("smart contract: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "smart_contract")
 		}
 		payload.SmartContract = &smartContract
 	case TransactionPayloadIDPoisonMicroblock:
 		poisonMicroblock, err := decodePoisonMicroblockPayload(reader)
 		if err != nil {
-			return payload, fmt.Errorf("poison microblock: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "poison_microblock")
 		}
 		payload.PoisonMicroblock = &poisonMicroblock
 	case TransactionPayloadIDCoinbase:
 		coinbase, err := decodeCoinbasePayload(reader)
 		if err != nil {
-			return payload, fmt.Errorf("coinbase: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "coinbase")
 		}
 		payload.Coinbase = &coinbase
 	case TransactionPayloadIDCoinbaseToAltRecipient:
 		coinbase, err := decodeCoinbasePayload(reader)
 		if err != nil {
-			return payload, fmt.Errorf("coinbase alt: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "coinbase_to_alt_recipient")
 		}
 		payload.Coinbase = &coinbase
 
-		altRecipient, err := decodePrincipalData(reader)
+		altRecipient, err := decodePrincipalData(reader, opts)
 		if err != nil {
-			return payload, fmt.Errorf("alt recipient: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "alt_recipient")
 		}
 		payload.AltRecipient = &altRecipient
 	case TransactionPayloadIDVersionedSmartContract:
-		smartContract, err := decodeSmartContractPayload(reader)
-		if err != nil {
-			return payload, fmt.Errorf("versioned smart contract: %v", err)
-		}
-		payload.SmartContract = &smartContract
-
+		// The clarity version precedes the contract
+		versionOffset := clarity_value.InputOffset(reader)
 		var clarityVersion uint8
-		if err = binary.Read(reader, binary.BigEndian, &clarityVersion); err != nil {
-			return payload, fmt.Errorf("clarity version: %v", err)
+		if err = readField(reader, "clarity_version", &clarityVersion); err != nil {
+			return payload, clarity_value.WithPathSegment(err, "versioned_smart_contract")
+		}
+		if !opts.Lenient && (clarityVersion < ClarityVersion1 || clarityVersion > ClarityVersion4) {
+			err := fieldError(clarity_value.KindInvalid, versionOffset, "clarity_version", "unknown clarity version: %d", clarityVersion)
+			return payload, clarity_value.WithPathSegment(err, "versioned_smart_contract")
 		}
 		payload.ClarityVersion = &clarityVersion
+
+		smartContract, err := decodeSmartContractPayload(reader, opts)
+		if err != nil {
+			return payload, clarity_value.WithPathSegment(err, "versioned_smart_contract")
+		}
+		payload.SmartContract = &smartContract
 	case TransactionPayloadIDTenureChange:
-		tenureChange, err := decodeTenureChangePayload(reader)
+		tenureChange, err := decodeTenureChangePayload(reader, opts)
 		if err != nil {
-			return payload, fmt.Errorf("tenure change: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "tenure_change")
 		}
 		payload.TenureChange = &tenureChange
 	case TransactionPayloadIDNakamotoCoinbase:
 		coinbase, err := decodeCoinbasePayload(reader)
 		if err != nil {
-			return payload, fmt.Errorf("nakamoto coinbase: %v", err)
+			return payload, clarity_value.WithPathSegment(err, "nakamoto_coinbase")
 		}
 		payload.Coinbase = &coinbase
 
-		// Optional alt recipient
-		var hasAltRecipient uint8
-		if err = binary.Read(reader, binary.BigEndian, &hasAltRecipient); err != nil {
-			return payload, fmt.Errorf("has alt recipient: %v", err)
-		}
-
-		if hasAltRecipient == 1 {
-			altRecipient, err := decodePrincipalData(reader)
-			if err != nil {
-				return payload, fmt.Errorf("nakamoto alt recipient: %v", err)
-			}
-			payload.AltRecipient = &altRecipient
+		// The alt recipient is serialized as an optional Clarity principal
+		altRecipient, err := decodeOptionalPrincipalData(reader, opts)
+		if err != nil {
+			return payload, clarity_value.WithPathSegment(err, "nakamoto_coinbase.alt_recipient")
 		}
+		payload.AltRecipient = altRecipient
 
 		// VRF proof
+		proofOffset := clarity_value.InputOffset(reader)
 		var vrfProofLen uint32
-		if err = binary.Read(reader, binary.BigEndian, &vrfProofLen); err != nil {
-			return payload, fmt.Errorf("vrf proof length: %v", err)
+		if err = readField(reader, "vrf_proof", &vrfProofLen); err != nil {
+			return payload, clarity_value.WithPathSegment(err, "nakamoto_coinbase")
+		}
+		if !opts.Lenient && vrfProofLen != VRFProofLength {
+			err := fieldError(clarity_value.KindInvalid, proofOffset, "vrf_proof", "VRF proof length %d, expected %d", vrfProofLen, VRFProofLength)
+			return payload, clarity_value.WithPathSegment(err, "nakamoto_coinbase")
 		}
 
 		vrfProof := make([]byte, vrfProofLen)
-		if _, err = io.ReadFull(reader, vrfProof); err != nil {
-			return payload, fmt.Errorf("vrf proof: %v", err)
+		if err = readField(reader, "vrf_proof", vrfProof); err != nil {
+			return payload, clarity_value.WithPathSegment(err, "nakamoto_coinbase")
 		}
 		payload.VRFProof = &vrfProof
+	default:
+		return payload, fieldError(clarity_value.KindBadPrefix, offset, "payload_type", "unknown payload type: %d", payload.PayloadType)
 	}
 
 	return payload, nil
 }
 
-func decodeTokenTransferPayload(reader io.Reader) (TokenTransferPayload, error) {
+func decodeTokenTransferPayload(reader io.Reader, opts DecodeOptions) (TokenTransferPayload, error) {
 	var payload TokenTransferPayload
 	var err error
 
 	// Decode recipient
-	if payload.Recipient, err = decodePrincipalData(reader); err != nil {
-		return payload, fmt.Errorf("recipient: %v", err)
+	if payload.Recipient, err = decodePrincipalData(reader, opts); err != nil {
+		return payload, clarity_value.WithPathSegment(err, "recipient")
 	}
 
 	// Decode amount
-	if err = binary.Read(reader, binary.BigEndian, &payload.Amount); err != nil {
-		return payload, fmt.Errorf("amount: %v", err)
+	if err = readField(reader, "amount", &payload.Amount); err != nil {
+		return payload, err
 	}
 
 	// Decode memo
-	if _, err = io.ReadFull(reader, payload.Memo[:]); err != nil {
-		return payload, fmt.Errorf("memo: %v", err)
+	if err = readField(reader, "memo", payload.Memo[:]); err != nil {
+		return payload, err
 	}
 
 	return payload, nil
 }
 
-func decodeContractCallPayload(reader io.Reader) (ContractCallPayload, error) {
+func decodeContractCallPayload(reader io.Reader, opts DecodeOptions) (ContractCallPayload, error) {
 	var payload ContractCallPayload
 	var err error
 
 	// Decode address
-	if err = binary.Read(reader, binary.BigEndian, &payload.Address.Version); err != nil {
-		return payload, fmt.Errorf("address version: %v", err)
+	if err = readField(reader, "address.version", &payload.Address.Version); err != nil {
+		return payload, err
 	}
 
-	if _, err = io.ReadFull(reader, payload.Address.Hash160[:]); err != nil {
-		return payload, fmt.Errorf("address hash160: %v", err)
+	if err = readField(reader, "address.hash160", payload.Address.Hash160[:]); err != nil {
+		return payload, err
 	}
 
 	// Decode contract name
-	var nameLen uint8
-	if err = binary.Read(reader, binary.BigEndian, &nameLen); err != nil {
-		return payload, fmt.Errorf("contract name length: %v", err)
-	}
-
-	payload.ContractName = make([]byte, nameLen)
-	if _, err = io.ReadFull(reader, payload.ContractName); err != nil {
-		return payload, fmt.Errorf("contract name: %v", err)
+	if payload.ContractName, err = readName(reader, "contract_name", opts, validateContractName); err != nil {
+		return payload, err
 	}
 
 	// Decode function name
-	if err = binary.Read(reader, binary.BigEndian, &nameLen); err != nil {
-		return payload, fmt.Errorf("function name length: %v", err)
-	}
-
-	payload.FunctionName = make([]byte, nameLen)
-	if _, err = io.ReadFull(reader, payload.FunctionName); err != nil {
-		return payload, fmt.Errorf("function name: %v", err)
+	if payload.FunctionName, err = readName(reader, "function_name", opts, validateClarityName); err != nil {
+		return payload, err
 	}
 
 	// Decode function args
 	var argsCount uint32
-	if err = binary.Read(reader, binary.BigEndian, &argsCount); err != nil {
-		return payload, fmt.Errorf("args count: %v", err)
+	if err = readField(reader, "args_count", &argsCount); err != nil {
+		return payload, err
 	}
 
-	payload.FunctionArgs = make([]ClarityValue, argsCount)
+	// Each argument carries its serialized bytes; the count is untrusted, so
+	// grow the slice as arguments are decoded
+	payload.FunctionArgs = []clarity_value.ClarityValue{}
+	decoder := clarity_value.NewDecoder(reader)
 	for i := uint32(0); i < argsCount; i++ {
-		arg, err := decodeClarityValue(reader)
+		arg, err := decoder.DecodeWithOptions(clarity_value.DecodeOptions{WithBytes: true, Path: fmt.Sprintf("args[%d]", i)})
 		if err != nil {
-			return payload, fmt.Errorf("arg %d: %v", i, err)
+			return payload, err
 		}
-		payload.FunctionArgs[i] = arg
+		payload.FunctionArgs = append(payload.FunctionArgs, arg)
 	}
 
 	return payload, nil
 }
 
-func decodeSmartContractPayload(reader io.Reader) (SmartContractPayload, error) {
+func decodeSmartContractPayload(reader io.Reader, opts DecodeOptions) (SmartContractPayload, error) {
 	var payload SmartContractPayload
 	var err error
 
 	// Decode name
-	var nameLen uint8
-	if err = binary.Read(reader, binary.BigEndian, &nameLen); err != nil {
-		return payload, fmt.Errorf("name length: %v", err)
-	}
-
-	payload.Name = make([]byte, nameLen)
-	if _, err = io.ReadFull(reader, payload.Name); err != nil {
-		return payload, fmt.Errorf("name: %v", err)
+	if payload.Name, err = readName(reader, "name", opts, validateContractName); err != nil {
+		return payload, err
 	}
 
 	// Decode code body
 	var codeLen uint32
-	if err = binary.Read(reader, binary.BigEndian, &codeLen); err != nil {
-		return payload, fmt.Errorf("code length: %v", err)
+	if err = readField(reader, "code_length", &codeLen); err != nil {
+		return payload, err
 	}
 
 	payload.CodeBody = make([]byte, codeLen)
-	if _, err = io.ReadFull(reader, payload.CodeBody); err != nil {
-		return payload, fmt.Errorf("code body: %v", err)
+	if err = readField(reader, "code_body", payload.CodeBody); err != nil {
+		return payload, err
 	}
 
 	return payload, nil
@@ -662,12 +793,12 @@ func decodePoisonMicroblockPayload(reader io.Reader) (PoisonMicroblockPayload, e
 
 	// Decode header 1
 	if payload.Header1, err = decodeMicroblockHeader(reader); err != nil {
-		return payload, fmt.Errorf("header 1: %v", err)
+		return payload, clarity_value.WithPathSegment(err, "header_1")
 	}
 
 	// Decode header 2
 	if payload.Header2, err = decodeMicroblockHeader(reader); err != nil {
-		return payload, fmt.Errorf("header 2: %v", err)
+		return payload, clarity_value.WithPathSegment(err, "header_2")
 	}
 
 	return payload, nil
@@ -677,54 +808,36 @@ func decodeMicroblockHeader(reader io.Reader) (StacksMicroblockHeader, error) {
 	var header StacksMicroblockHeader
 	var err error
 
-	// Save starting position
-	bufReader, ok := reader.(*bytes.Reader)
-	var startPos int64
-	if ok {
-		startPos = bufReader.Size() - int64(bufReader.Len())
-	}
+	// Capture the serialized bytes as the header is read
+	var serialized bytes.Buffer
+	reader = clarity_value.NewOffsetReader(io.TeeReader(reader, &serialized), clarity_value.InputOffset(reader))
 
 	// Decode version
-	if err = binary.Read(reader, binary.BigEndian, &header.Version); err != nil {
-		return header, fmt.Errorf("version: %v", err)
+	if err = readField(reader, "version", &header.Version); err != nil {
+		return header, err
 	}
 
 	// Decode sequence
-	if err = binary.Read(reader, binary.BigEndian, &header.Sequence); err != nil {
-		return header, fmt.Errorf("sequence: %v", err)
+	if err = readField(reader, "sequence", &header.Sequence); err != nil {
+		return header, err
 	}
 
 	// Decode prev block
-	if _, err = io.ReadFull(reader, header.PrevBlock[:]); err != nil {
-		return header, fmt.Errorf("prev block: %v", err)
+	if err = readField(reader, "prev_block", header.PrevBlock[:]); err != nil {
+		return header, err
 	}
 
 	// Decode tx merkle root
-	if _, err = io.ReadFull(reader, header.TxMerkleRoot[:]); err != nil {
-		return header, fmt.Errorf("tx merkle root: %v", err)
+	if err = readField(reader, "tx_merkle_root", header.TxMerkleRoot[:]); err != nil {
+		return header, err
 	}
 
 	// Decode signature
-	if _, err = io.ReadFull(reader, header.Signature[:]); err != nil {
-		return header, fmt.Errorf("signature: %v", err)
+	if err = readField(reader, "signature", header.Signature[:]); err != nil {
+		return header, err
 	}
 
-	// If we have a bytes.Reader, save serialized bytes
-	if ok {
-		endPos := bufReader.Size() - int64(bufReader.Len())
-		serializedLen := endPos - startPos
-
-		// Reset to start position
-		if _, err = bufReader.Seek(startPos, io.SeekStart); err != nil {
-			return header, fmt.Errorf("reset position: %v", err)
-		}
-
-		// Read serialized bytes
-		header.SerializedBytes = make([]byte, serializedLen)
-		if _, err = io.ReadFull(reader, header.SerializedBytes); err != nil {
-			return header, fmt.Errorf("serialized bytes: %v", err)
-		}
-	}
+	header.SerializedBytes = serialized.Bytes()
 
 	return header, nil
 }
@@ -734,143 +847,142 @@ func decodeCoinbasePayload(reader io.Reader) (CoinbasePayload, error) {
 	var err error
 
 	// Decode data
-	if _, err = io.ReadFull(reader, payload.Data[:]); err != nil {
-		return payload, fmt.Errorf("data: %v", err)
+	if err = readField(reader, "data", payload.Data[:]); err != nil {
+		return payload, err
 	}
 
 	return payload, nil
 }
 
-func decodeTenureChangePayload(reader io.Reader) (TenureChangePayload, error) {
+func decodeTenureChangePayload(reader io.Reader, opts DecodeOptions) (TenureChangePayload, error) {
 	var payload TenureChangePayload
 	var err error
 
 	// Decode tenure consensus hash
-	if _, err = io.ReadFull(reader, payload.TenureConsensusHash[:]); err != nil {
-		return payload, fmt.Errorf("tenure consensus hash: %v", err)
+	if err = readField(reader, "tenure_consensus_hash", payload.TenureConsensusHash[:]); err != nil {
+		return payload, err
 	}
 
 	// Decode prev tenure consensus hash
-	if _, err = io.ReadFull(reader, payload.PrevTenureConsensusHash[:]); err != nil {
-		return payload, fmt.Errorf("prev tenure consensus hash: %v", err)
+	if err = readField(reader, "prev_tenure_consensus_hash", payload.PrevTenureConsensusHash[:]); err != nil {
+		return payload, err
 	}
 
 	// Decode burn view consensus hash
-	if _, err = io.ReadFull(reader, payload.BurnViewConsensusHash[:]); err != nil {
-		return payload, fmt.Errorf("burn view consensus hash: %v", err)
+	if err = readField(reader, "burn_view_consensus_hash", payload.BurnViewConsensusHash[:]); err != nil {
+		return payload, err
 	}
 
 	// Decode previous tenure end
-	if _, err = io.ReadFull(reader, payload.PreviousTenureEnd[:]); err != nil {
-		return payload, fmt.Errorf("previous tenure end: %v", err)
+	if err = readField(reader, "previous_tenure_end", payload.PreviousTenureEnd[:]); err != nil {
+		return payload, err
 	}
 
 	// Decode previous tenure blocks
-	if err = binary.Read(reader, binary.BigEndian, &payload.PreviousTenureBlocks); err != nil {
-		return payload, fmt.Errorf("previous tenure blocks: %v", err)
+	if err = readField(reader, "previous_tenure_blocks", &payload.PreviousTenureBlocks); err != nil {
+		return payload, err
 	}
 
 	// Decode cause
-	if err = binary.Read(reader, binary.BigEndian, &payload.Cause); err != nil {
-		return payload, fmt.Errorf("cause: %v", err)
+	offset := clarity_value.InputOffset(reader)
+	if err = readField(reader, "cause", &payload.Cause); err != nil {
+		return payload, err
+	}
+	if !opts.Lenient && payload.Cause != TenureChangeCauseBlockFound && payload.Cause != TenureChangeCauseExtended {
+		return payload, fieldError(clarity_value.KindInvalid, offset, "cause", "unknown tenure change cause: %d", payload.Cause)
 	}
 
 	// Decode pubkey hash
-	if _, err = io.ReadFull(reader, payload.PubkeyHash[:]); err != nil {
-		return payload, fmt.Errorf("pubkey hash: %v", err)
+	if err = readField(reader, "pubkey_hash", payload.PubkeyHash[:]); err != nil {
+		return payload, err
 	}
 
 	return payload, nil
 }
 
-func decodePrincipalData(reader io.Reader) (PrincipalData, error) {
+func decodePrincipalData(reader io.Reader, opts DecodeOptions) (PrincipalData, error) {
 	var principal PrincipalData
 	var err error
 
-	// Decode type
-	if err = binary.Read(reader, binary.BigEndian, &principal.Type); err != nil {
-		return principal, fmt.Errorf("type: %v", err)
-	}
+	offset := clarity_value.InputOffset(reader)
 
-	// Special handling for test vector
-	// The test vector has a non-standard principal type (0xBF or 191)
-	// For testing purposes, we'll treat it as a standard principal
-	if principal.Type == 0xBF {
-		principal.Type = PrincipalTypeStandard
+	// Decode type
+	if err = readField(reader, "type", &principal.Type); err != nil {
+		return principal, err
 	}
 
 	switch principal.Type {
 	case PrincipalTypeStandard:
 		standardData, err := decodeStandardPrincipalData(reader)
 		if err != nil {
-			return principal, fmt.Errorf("standard data: %v", err)
+			return principal, err
 		}
 		principal.StandardData = &standardData
 	case PrincipalTypeContract:
-		contractData, err := decodeQualifiedContractIdentifier(reader)
+		contractData, err := decodeQualifiedContractIdentifier(reader, opts)
 		if err != nil {
-			return principal, fmt.Errorf("contract data: %v", err)
+			return principal, err
 		}
 		principal.ContractData = &contractData
 	default:
-		return principal, fmt.Errorf("invalid principal type: %d", principal.Type)
+		return principal, fieldError(clarity_value.KindBadPrefix, offset, "type", "invalid principal type: %d", principal.Type)
 	}
 
 	return principal, nil
 }
 
+// decodeOptionalPrincipalData decodes a principal serialized as a Clarity optional
+func decodeOptionalPrincipalData(reader io.Reader, opts DecodeOptions) (*PrincipalData, error) {
+	offset := clarity_value.InputOffset(reader)
+	var prefix uint8
+	if err := readField(reader, "type", &prefix); err != nil {
+		return nil, err
+	}
+
+	switch clarity_value.TypePrefix(prefix) {
+	case clarity_value.PrefixOptionalNone:
+		return nil, nil
+	case clarity_value.PrefixOptionalSome:
+		principal, err := decodePrincipalData(reader, opts)
+		if err != nil {
+			return nil, clarity_value.WithPathSegment(err, "some")
+		}
+		return &principal, nil
+	default:
+		return nil, fieldError(clarity_value.KindBadPrefix, offset, "type", "invalid optional type: %d", prefix)
+	}
+}
+
 func decodeStandardPrincipalData(reader io.Reader) (StandardPrincipalData, error) {
 	var data StandardPrincipalData
 	var err error
 
 	// Decode version
-	if err = binary.Read(reader, binary.BigEndian, &data.Version); err != nil {
-		return data, fmt.Errorf("version: %v", err)
+	if err = readField(reader, "version", &data.Version); err != nil {
+		return data, err
 	}
 
 	// Decode address
-	if _, err = io.ReadFull(reader, data.Address[:]); err != nil {
-		return data, fmt.Errorf("address: %v", err)
+	if err = readField(reader, "address", data.Address[:]); err != nil {
+		return data, err
 	}
 
 	return data, nil
 }
 
-func decodeQualifiedContractIdentifier(reader io.Reader) (QualifiedContractIdentifier, error) {
+func decodeQualifiedContractIdentifier(reader io.Reader, opts DecodeOptions) (QualifiedContractIdentifier, error) {
 	var data QualifiedContractIdentifier
 	var err error
 
 	// Decode issuer
 	if data.Issuer, err = decodeStandardPrincipalData(reader); err != nil {
-		return data, fmt.Errorf("issuer: %v", err)
+		return data, clarity_value.WithPathSegment(err, "issuer")
 	}
 
 	// Decode name
-	var nameLen uint8
-	if err = binary.Read(reader, binary.BigEndian, &nameLen); err != nil {
-		return data, fmt.Errorf("name length: %v", err)
-	}
-
-	data.Name = make([]byte, nameLen)
-	if _, err = io.ReadFull(reader, data.Name); err != nil {
-		return data, fmt.Errorf("name: %v", err)
+	if data.Name, err = readName(reader, "name", opts, validateContractName); err != nil {
+		return data, err
 	}
 
 	return data, nil
 }
-
-func decodeClarityValue(reader io.Reader) (ClarityValue, error) {
-	var value ClarityValue
-	var err error
-
-	// For simplicity, we're not fully implementing Clarity value deserialization
-	// as it's not directly required for the test. Just capturing the type ID.
-	if err = binary.Read(reader, binary.BigEndian, &value.TypeID); err != nil {
-		return value, fmt.Errorf("type ID: %v", err)
-	}
-
-	// In a real implementation, we would deserialize the value based on the type ID
-	// For now, we'll just return the type ID and empty data
-
-	return value, nil
-}
@@ -0,0 +1,181 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+// ErrInvalidSignature matches every signature verification failure
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Verify checks the origin signatures and, for sponsored transactions, the
+// sponsor signatures, as stacks-core does before accepting a transaction
+func (tx *StacksTransaction) Verify() error {
+	if err := tx.VerifyOrigin(); err != nil {
+		return err
+	}
+	if tx.Auth.AuthType == TransactionAuthFlagSponsored {
+		return tx.VerifySponsor()
+	}
+	return nil
+}
+
+// VerifyOrigin recovers the public keys from the origin spending condition's
+// signatures over the sighash chain and checks that they hash to its signer
+func (tx *StacksTransaction) VerifyOrigin() error {
+	initial, err := tx.InitialSighash()
+	if err != nil {
+		return err
+	}
+	if _, err := verifySpendingCondition(tx.Auth.SpendingCondition, initial, TransactionAuthFlagStandard); err != nil {
+		return fmt.Errorf("failed to verify origin: %w", err)
+	}
+	return nil
+}
+
+// VerifySponsor checks the sponsor spending condition's signatures the same
+// way. The sponsor signs after the origin, so its sighash chain continues from
+// the origin's signatures; these are not verified themselves.
+func (tx *StacksTransaction) VerifySponsor() error {
+	if tx.Auth.AuthType != TransactionAuthFlagSponsored || tx.Auth.SponsorSpendingCondition == nil {
+		return fmt.Errorf("failed to verify sponsor: %w: transaction is not sponsored", ErrInvalidSignature)
+	}
+
+	initial, err := tx.InitialSighash()
+	if err != nil {
+		return err
+	}
+	_, originSighash, err := tx.Auth.SpendingCondition.Sighashes(initial, TransactionAuthFlagStandard)
+	if err != nil {
+		return fmt.Errorf("failed to verify sponsor: %w: %v", ErrInvalidSignature, err)
+	}
+
+	if _, err := verifySpendingCondition(*tx.Auth.SponsorSpendingCondition, originSighash, TransactionAuthFlagSponsored); err != nil {
+		return fmt.Errorf("failed to verify sponsor: %w", err)
+	}
+	return nil
+}
+
+// verifySpendingCondition verifies the condition's signatures starting from
+// curSighash and returns the sighash the next condition starts from
+func verifySpendingCondition(condition TransactionSpendingCondition, curSighash [32]byte, authFlag uint8) ([32]byte, error) {
+	presigns, nextSighash, err := condition.Sighashes(curSighash, authFlag)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	var publicKeys [][]byte
+	switch condition.HashMode {
+	case SinglesigHashModeP2PKH, SinglesigHashModeP2WPKH:
+		keyEncoding := PublicKeyEncodingCompressed
+		if condition.KeyEncoding != nil {
+			keyEncoding = *condition.KeyEncoding
+		}
+		if condition.HashMode == SinglesigHashModeP2WPKH && keyEncoding != PublicKeyEncodingCompressed {
+			return [32]byte{}, fmt.Errorf("%w: uncompressed public key in P2WPKH spending condition", ErrInvalidSignature)
+		}
+		publicKey, err := RecoverPublicKey(presigns[0], *condition.Signature, keyEncoding)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		publicKeys = append(publicKeys, publicKey)
+
+	default:
+		if condition.SignaturesRequired == nil {
+			return [32]byte{}, fmt.Errorf("%w: missing signatures required", ErrInvalidSignature)
+		}
+
+		signatures := 0
+		for i, field := range condition.Fields {
+			var publicKey []byte
+			if field.isSignature() {
+				publicKey, err = RecoverPublicKey(presigns[signatures], *field.Signature, field.keyEncoding())
+				if err != nil {
+					return [32]byte{}, fmt.Errorf("auth field %d: %w", i, err)
+				}
+				signatures++
+			} else {
+				if field.PublicKey == nil {
+					return [32]byte{}, fmt.Errorf("%w: auth field %d is missing its public key", ErrInvalidSignature, i)
+				}
+				publicKey, err = serializePublicKey(field.PublicKey[:], field.keyEncoding())
+				if err != nil {
+					return [32]byte{}, fmt.Errorf("auth field %d: %w", i, err)
+				}
+			}
+			if field.keyEncoding() != PublicKeyEncodingCompressed &&
+				(condition.HashMode == MultisigHashModeP2WSH || condition.HashMode == MultisigHashModeP2WSHNonSequential) {
+				return [32]byte{}, fmt.Errorf("%w: uncompressed public key in P2WSH spending condition", ErrInvalidSignature)
+			}
+			publicKeys = append(publicKeys, publicKey)
+		}
+
+		// Sequential signers sign exactly as many times as required; extra
+		// non-sequential signatures are allowed
+		required := int(*condition.SignaturesRequired)
+		sequential := condition.HashMode == MultisigHashModeP2SH || condition.HashMode == MultisigHashModeP2WSH
+		if signatures < required || (sequential && signatures != required) {
+			return [32]byte{}, fmt.Errorf("%w: %d signatures, %d required", ErrInvalidSignature, signatures, required)
+		}
+	}
+
+	signer, err := signerHash(condition.HashMode, publicKeys, condition.SignaturesRequired)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer != condition.Signer {
+		return [32]byte{}, fmt.Errorf("%w: signer %x does not match public keys hashing to %x", ErrInvalidSignature, condition.Signer, signer)
+	}
+
+	return nextSighash, nil
+}
+
+// RecoverPublicKey recovers the public key that made a recoverable signature
+// (recovery ID followed by r and s) over sighash, serialized per keyEncoding
+func RecoverPublicKey(sighash [32]byte, signature [65]byte, keyEncoding uint8) ([]byte, error) {
+	if signature[0] > 3 {
+		return nil, fmt.Errorf("%w: invalid recovery ID: %d", ErrInvalidSignature, signature[0])
+	}
+
+	// The compact format recovered by ecdsa.RecoverCompact takes the recovery
+	// ID offset by 27 first, followed by r and s as the signature has them
+	var compact [65]byte
+	compact[0] = 27 + signature[0]
+	copy(compact[1:], signature[1:])
+
+	publicKey, _, err := ecdsa.RecoverCompact(compact[:], sighash[:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if keyEncoding == PublicKeyEncodingUncompressed {
+		return publicKey.SerializeUncompressed(), nil
+	}
+	return publicKey.SerializeCompressed(), nil
+}
+
+// serializePublicKey re-serializes a compressed public key per keyEncoding
+func serializePublicKey(compressed []byte, keyEncoding uint8) ([]byte, error) {
+	publicKey, err := secp256k1.ParsePubKey(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key: %v", ErrInvalidSignature, err)
+	}
+	if keyEncoding == PublicKeyEncodingUncompressed {
+		return publicKey.SerializeUncompressed(), nil
+	}
+	return publicKey.SerializeCompressed(), nil
+}
+
+// signerHash computes the signer hash of the public keys for the hash mode,
+// as the address of the spending condition commits to it
+func signerHash(hashMode uint8, publicKeys [][]byte, signaturesRequired *uint16) ([20]byte, error) {
+	required := 1
+	if signaturesRequired != nil {
+		required = int(*signaturesRequired)
+	}
+	return address.PublicKeysToHash160(address.AddressHashMode(hashMode), required, publicKeys)
+}
@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// ErrDerivation matches every error about deriving a BIP32 key
+var ErrDerivation = errors.New("failed to derive key")
+
+// HardenedOffset is added to a child index to derive a hardened child
+const HardenedOffset uint32 = 0x80000000
+
+// ExtendedKey is a BIP32 extended private key
+type ExtendedKey struct {
+	key       secp256k1.ModNScalar
+	ChainCode [32]byte
+	Depth     uint8
+	// Index is the child index the key was derived with, 0 for a master key
+	Index uint32
+}
+
+// NewMasterKey derives the master key of a 16- to 64-byte seed
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("%w: seed of %d bytes", ErrDerivation, len(seed))
+	}
+	return newKey(hmacSHA512([]byte("Bitcoin seed"), seed), nil, 0, 0)
+}
+
+// Child derives the child key at index, hardened if index is at least HardenedOffset
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.Depth == 255 {
+		return nil, fmt.Errorf("%w: maximum depth reached", ErrDerivation)
+	}
+
+	var data []byte
+	if index >= HardenedOffset {
+		key := k.key.Bytes()
+		data = append([]byte{0x00}, key[:]...)
+	} else {
+		data = k.PublicKey()
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	return newKey(hmacSHA512(k.ChainCode[:], data), &k.key, k.Depth+1, index)
+}
+
+// Derive derives the key at a path relative to this key, such as "0/1'/2"
+// or, from a master key, "m/44'/5757'/0'/0/0"
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, index := range indexes {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// PrivateKey returns the 32-byte private key
+func (k *ExtendedKey) PrivateKey() []byte {
+	key := k.key.Bytes()
+	return key[:]
+}
+
+// PublicKey returns the compressed public key
+func (k *ExtendedKey) PublicKey() []byte {
+	return secp256k1.NewPrivateKey(&k.key).PubKey().SerializeCompressed()
+}
+
+// ParsePath parses a derivation path into child indexes. Hardened indexes are
+// marked with ' or h, and an optional leading "m" stands for the key the path
+// is derived from.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] == "m" {
+		segments = segments[1:]
+	}
+
+	indexes := make([]uint32, 0, len(segments))
+	for _, segment := range segments {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("%w: invalid path %q", ErrDerivation, path)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+// newKey creates a key from the output of HMAC-SHA512: the left half is the
+// key, added to the parent key for a child, and the right half the chain code
+func newKey(sum []byte, parent *secp256k1.ModNScalar, depth uint8, index uint32) (*ExtendedKey, error) {
+	child := &ExtendedKey{Depth: depth, Index: index}
+	if overflow := child.key.SetByteSlice(sum[:32]); overflow {
+		return nil, fmt.Errorf("%w: key at index %d is out of range", ErrDerivation, index)
+	}
+	if parent != nil {
+		child.key.Add(parent)
+	}
+	if child.key.IsZero() {
+		return nil, fmt.Errorf("%w: key at index %d is zero", ErrDerivation, index)
+	}
+	copy(child.ChainCode[:], sum[32:])
+	return child, nil
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidMnemonic matches every error about a mnemonic that is not a valid
+// BIP39 English mnemonic
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// SeedLength is the length of the seed a mnemonic is turned into
+const SeedLength = 64
+
+// english is the BIP39 English wordlist
+//
+//go:embed english.txt
+var english string
+
+var (
+	wordlist  = strings.Fields(english)
+	wordIndex = make(map[string]int, len(wordlist))
+)
+
+func init() {
+	if len(wordlist) != 2048 {
+		panic("wallet: the BIP39 wordlist must have 2048 words")
+	}
+	for i, word := range wordlist {
+		wordIndex[word] = i
+	}
+}
+
+// NewMnemonic generates a mnemonic from bits of random entropy: 128 bits for
+// 12 words up to 256 bits for 24 words
+func NewMnemonic(bits int) (string, error) {
+	if err := checkEntropyLength(bits / 8); err != nil || bits%8 != 0 {
+		return "", fmt.Errorf("%w: entropy of %d bits", ErrInvalidMnemonic, bits)
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes 16, 20, 24, 28 or 32 bytes of entropy as a
+// mnemonic of 12, 15, 18, 21 or 24 words
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	if err := checkEntropyLength(len(entropy)); err != nil {
+		return "", err
+	}
+
+	// The entropy is followed by the first len(entropy)/4 bits of its hash and
+	// split into 11-bit word indexes
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte(nil), entropy...), checksum[0])
+	words := make([]string, len(entropy)*3/4)
+	for i := range words {
+		index := 0
+		for bit := i * 11; bit < (i+1)*11; bit++ {
+			index = index<<1 | int(data[bit/8]>>(7-bit%8)&1)
+		}
+		words[i] = wordlist[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes a mnemonic to its entropy, checking its words and checksum
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words)%3 != 0 || checkEntropyLength(len(words)*4/3) != nil {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(words))
+	}
+
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		for bit := 0; bit < 11; bit++ {
+			if index>>(10-bit)&1 == 1 {
+				pos := i*11 + bit
+				data[pos/8] |= 1 << (7 - pos%8)
+			}
+		}
+	}
+
+	entropy := data[:len(words)*4/3]
+	checksumBits := len(entropy) / 4
+	checksum := sha256.Sum256(entropy)
+	if data[len(entropy)]>>(8-checksumBits) != checksum[0]>>(8-checksumBits) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks that the mnemonic is a valid BIP39 English mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed validates the mnemonic and turns it into a seed, salted with
+// the optional passphrase
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key(sha512.New, sentence, []byte(salt), 2048, SeedLength)
+}
+
+// checkEntropyLength checks that length is a BIP39 entropy length in bytes
+func checkEntropyLength(length int) error {
+	if length < 16 || length > 32 || length%4 != 0 {
+		return fmt.Errorf("%w: entropy of %d bytes", ErrInvalidMnemonic, length)
+	}
+	return nil
+}
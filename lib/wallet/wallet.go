@@ -0,0 +1,141 @@
+// Package wallet derives Stacks accounts from a BIP39 mnemonic or seed along
+// the BIP44 path the Stacks wallets use, m/44'/5757'/0'/0/i for account i
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/network"
+)
+
+// DerivationPath is the path of the key whose children are the accounts
+const DerivationPath = "m/44'/5757'/0'/0"
+
+// WIF versions of mainnet and testnet private keys
+const (
+	WIFVersionMainnet byte = 0x80
+	WIFVersionTestnet byte = 0xef
+)
+
+// Wallet derives the accounts of a seed
+type Wallet struct {
+	accounts *ExtendedKey
+}
+
+// New creates the wallet of a mnemonic and optional passphrase
+func New(mnemonic, passphrase string) (*Wallet, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return FromSeed(seed)
+}
+
+// FromSeed creates the wallet of a seed
+func FromSeed(seed []byte) (*Wallet, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := master.Derive(DerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{accounts: accounts}, nil
+}
+
+// Account derives the account at index
+func (w *Wallet) Account(index uint32) (*Account, error) {
+	if index >= HardenedOffset {
+		return nil, fmt.Errorf("%w: account index %d is hardened", ErrDerivation, index)
+	}
+	key, err := w.accounts.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{Index: index, key: key}, nil
+}
+
+// Accounts derives count accounts starting at index start
+func (w *Wallet) Accounts(start uint32, count int) ([]*Account, error) {
+	accounts := make([]*Account, 0, count)
+	for i := 0; i < count; i++ {
+		account, err := w.Account(start + uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// Scan derives accounts in order until gapLimit consecutive accounts have an
+// unused address on the network, as reported by isUsed. It returns the
+// accounts up to and including the last used one, or none if no account is used.
+func (w *Wallet) Scan(n network.Network, gapLimit int, isUsed func(address.StacksAddress) (bool, error)) ([]*Account, error) {
+	if gapLimit < 1 {
+		return nil, fmt.Errorf("invalid gap limit: %d", gapLimit)
+	}
+
+	var accounts []*Account
+	used := 0
+	for index := uint32(0); len(accounts)-used < gapLimit; index++ {
+		account, err := w.Account(index)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+
+		ok, err := isUsed(account.Address(n))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check account %d: %w", index, err)
+		}
+		if ok {
+			used = len(accounts)
+		}
+	}
+	return accounts[:used], nil
+}
+
+// Account is the key pair of an account
+type Account struct {
+	Index uint32
+	key   *ExtendedKey
+}
+
+// PublicKey returns the compressed public key
+func (a *Account) PublicKey() []byte {
+	return a.key.PublicKey()
+}
+
+// PrivateKey returns the private key with the 0x01 suffix that marks its public
+// key as compressed, as accepted by transaction.Signer
+func (a *Account) PrivateKey() []byte {
+	return append(a.key.PrivateKey(), 0x01)
+}
+
+// PrivateKeyHex returns the hex encoding of PrivateKey
+func (a *Account) PrivateKeyHex() string {
+	return hex.EncodeToString(a.PrivateKey())
+}
+
+// WIF returns the private key in wallet import format for the network
+func (a *Account) WIF(n network.Network) string {
+	version := WIFVersionTestnet
+	if n.IsMainnet() {
+		version = WIFVersionMainnet
+	}
+	return address.EncodeBase58Check(append([]byte{version}, a.PrivateKey()...))
+}
+
+// Address returns the singlesig address of the account on the network
+func (a *Account) Address(n network.Network) address.StacksAddress {
+	hash160, err := address.PublicKeysToHash160(address.SerializeP2PKH, 1, [][]byte{a.PublicKey()})
+	if err != nil {
+		// The public key of a derived key is always valid
+		panic(err)
+	}
+	return address.NewStacksAddress(n.AddressVersionSinglesig, hash160)
+}
@@ -0,0 +1,223 @@
+package address_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+func TestDecodeBech32ValidChecksums(t *testing.T) {
+	// Valid checksum test vectors from BIP-173 and BIP-350
+	tests := []struct {
+		input    string
+		encoding address.Bech32Encoding
+	}{
+		{"A12UEL5L", address.Bech32},
+		{"a12uel5l", address.Bech32},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", address.Bech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", address.Bech32},
+		{"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j", address.Bech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", address.Bech32},
+		{"?1ezyfcl", address.Bech32},
+		{"A1LQFN3A", address.Bech32m},
+		{"a1lqfn3a", address.Bech32m},
+		{"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", address.Bech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", address.Bech32m},
+		{"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8", address.Bech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", address.Bech32m},
+		{"?1v759aa", address.Bech32m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			hrp, data, encoding, err := address.DecodeBech32(tt.input)
+			if err != nil {
+				t.Fatalf("DecodeBech32(%s) failed: %v", tt.input, err)
+			}
+			if encoding != tt.encoding {
+				t.Errorf("DecodeBech32(%s) got encoding %v, want %v", tt.input, encoding, tt.encoding)
+			}
+
+			encoded, err := address.EncodeBech32(hrp, data, encoding)
+			if err != nil {
+				t.Fatalf("EncodeBech32() failed: %v", err)
+			}
+			if encoded != strings.ToLower(tt.input) {
+				t.Errorf("EncodeBech32() got %s, want %s", encoded, strings.ToLower(tt.input))
+			}
+		})
+	}
+}
+
+func TestDecodeBech32InvalidChecksums(t *testing.T) {
+	// Invalid test vectors from BIP-173 and BIP-350
+	invalid := []string{
+		"\x201nwldj5", // HRP character out of range
+		"\x7f1axkwrx", // HRP character out of range
+		"\x801eym55h", // HRP character out of range
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", // Overall max length exceeded
+		"pzry9x0s0muk",  // No separator character
+		"1pzry9x0s0muk", // Empty HRP
+		"x1b4n0q5v",     // Invalid data character
+		"li1dgmt3",      // Too short checksum
+		"de1lg7wt\xff",  // Invalid character in checksum
+		"A1G7SGD8",      // Checksum calculated with uppercase form of HRP
+		"10a06t8",       // Empty HRP
+		"1qzzfhee",      // Empty HRP
+		"M1VUXWEZ",      // Checksum calculated with uppercase form of HRP
+		"qyrz8wqd2c9m",  // No separator character
+		"1qyrz8wqd2c9m", // Empty HRP
+		"y1b0jsk6g",     // Invalid data character
+		"lt1igcx5c0",    // Invalid data character
+		"in1muywd",      // Too short checksum
+		"mm1crxm3i",     // Invalid character in checksum
+		"au1s5cgom",     // Invalid character in checksum
+		"16plkw9",       // Empty HRP
+		"1p2gdwpf",      // Empty HRP
+		"abcdef1Qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", // Mixed case
+	}
+
+	for _, input := range invalid {
+		t.Run(input, func(t *testing.T) {
+			if _, _, _, err := address.DecodeBech32(input); err == nil {
+				t.Errorf("DecodeBech32(%q) should have failed", input)
+			}
+		})
+	}
+}
+
+func TestSegwitAddresses(t *testing.T) {
+	// Valid segwit address test vectors from BIP-350, with their scriptPubKeys
+	tests := []struct {
+		address      string
+		scriptPubKey string
+		addrType     address.BitcoinAddressType
+		networkID    address.BitcoinNetworkType
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6", address.WitnessPubKeyHash, address.Mainnet},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262", address.WitnessScriptHash, address.Testnet},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6", address.WitnessUnknown, address.Mainnet},
+		{"BC1SW50QGDZ25J", "6002751e", address.WitnessUnknown, address.Mainnet},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "5210751e76e8199196d454941c45d1b3a323", address.WitnessUnknown, address.Mainnet},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433", address.WitnessScriptHash, address.Testnet},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433", address.Taproot, address.Testnet},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", address.Taproot, address.Mainnet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			decoded, err := address.DecodeBitcoinAddress(tt.address)
+			if err != nil {
+				t.Fatalf("DecodeBitcoinAddress(%s) failed: %v", tt.address, err)
+			}
+			if decoded.AddrType != tt.addrType || decoded.NetworkID != tt.networkID {
+				t.Errorf("DecodeBitcoinAddress(%s) got type %v on network %v, want %v on %v",
+					tt.address, decoded.AddrType, decoded.NetworkID, tt.addrType, tt.networkID)
+			}
+
+			// A witness scriptPubKey is OP_n followed by a push of the program
+			versionOp := byte(0x00)
+			if decoded.WitnessVersion > 0 {
+				versionOp = 0x50 + decoded.WitnessVersion
+			}
+			script := append([]byte{versionOp, byte(len(decoded.WitnessProgram))}, decoded.WitnessProgram...)
+			if hex.EncodeToString(script) != tt.scriptPubKey {
+				t.Errorf("DecodeBitcoinAddress(%s) got scriptPubKey %x, want %s", tt.address, script, tt.scriptPubKey)
+			}
+
+			encoded, err := address.EncodeBitcoinAddress(decoded)
+			if err != nil {
+				t.Fatalf("EncodeBitcoinAddress() failed: %v", err)
+			}
+			if encoded != strings.ToLower(tt.address) {
+				t.Errorf("EncodeBitcoinAddress() got %s, want %s", encoded, strings.ToLower(tt.address))
+			}
+		})
+	}
+}
+
+func TestInvalidSegwitAddresses(t *testing.T) {
+	// Invalid segwit address test vectors from BIP-350
+	invalid := []string{
+		"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", // Invalid human-readable part
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", // Invalid checksum (Bech32 instead of Bech32m)
+		"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", // Invalid checksum (Bech32 instead of Bech32m)
+		"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", // Invalid checksum (Bech32 instead of Bech32m)
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",                     // Invalid checksum (Bech32m instead of Bech32)
+		"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", // Invalid checksum (Bech32m instead of Bech32)
+		"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", // Invalid character in checksum
+		"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", // Invalid witness version
+		"bc1pw5dgrnzv", // Invalid program length (1 byte)
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", // Invalid program length (41 bytes)
+		"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P",                                         // Invalid program length for witness version 0
+		"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq",               // Mixed case
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf",             // Zero padding of more than 4 bits
+		"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j",               // Non-zero padding in 8-to-5 conversion
+		"bc1gmk9yu", // Empty data section
+	}
+
+	for _, addr := range invalid {
+		t.Run(addr, func(t *testing.T) {
+			if _, err := address.DecodeBitcoinAddress(addr); err == nil {
+				t.Errorf("DecodeBitcoinAddress(%s) should have failed", addr)
+			}
+		})
+	}
+}
+
+func TestEncodeSegwitAddress(t *testing.T) {
+	program, _ := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+
+	tests := []struct {
+		networkID address.BitcoinNetworkType
+		expected  string
+	}{
+		{address.Mainnet, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{address.Testnet, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{address.Regtest, "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			addr := &address.BitcoinAddress{AddrType: address.WitnessPubKeyHash, NetworkID: tt.networkID, WitnessProgram: program}
+			encoded, err := address.EncodeBitcoinAddress(addr)
+			if err != nil {
+				t.Fatalf("EncodeBitcoinAddress() failed: %v", err)
+			}
+			if encoded != tt.expected {
+				t.Errorf("EncodeBitcoinAddress() got %s, want %s", encoded, tt.expected)
+			}
+
+			decoded, err := address.DecodeBitcoinAddress(encoded)
+			if err != nil {
+				t.Fatalf("DecodeBitcoinAddress(%s) failed: %v", encoded, err)
+			}
+			if decoded.NetworkID != tt.networkID || hex.EncodeToString(decoded.Hash160Bytes[:]) != hex.EncodeToString(program) {
+				t.Errorf("DecodeBitcoinAddress(%s) got network %v and hash %x", encoded, decoded.NetworkID, decoded.Hash160Bytes)
+			}
+		})
+	}
+
+	// Witness version 0 programs must be 20 or 32 bytes
+	invalid := &address.BitcoinAddress{AddrType: address.WitnessUnknown, WitnessProgram: program[:16]}
+	if _, err := address.EncodeBitcoinAddress(invalid); err == nil {
+		t.Errorf("EncodeBitcoinAddress() should have failed for an invalid program")
+	}
+
+	// A pay-to-witness-pubkey-hash address falls back to its Hash160Bytes
+	p2wpkh := &address.BitcoinAddress{AddrType: address.WitnessPubKeyHash, NetworkID: address.Mainnet}
+	copy(p2wpkh.Hash160Bytes[:], program)
+	encoded, err := address.EncodeBitcoinAddress(p2wpkh)
+	if err != nil {
+		t.Fatalf("EncodeBitcoinAddress() failed: %v", err)
+	}
+	if encoded != "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4" {
+		t.Errorf("EncodeBitcoinAddress() got %s, want bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", encoded)
+	}
+
+	if _, err := address.BitcoinToStacksAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"); err == nil {
+		t.Errorf("BitcoinToStacksAddress() should have failed for a segwit address")
+	}
+}
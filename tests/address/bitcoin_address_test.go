@@ -56,7 +56,10 @@ func TestBitcoinAddressEncoding(t *testing.T) {
 			}
 
 			// Test encoding
-			encoded := address.EncodeBitcoinAddress(decoded)
+			encoded, err := address.EncodeBitcoinAddress(decoded)
+			if err != nil {
+				t.Fatalf("EncodeBitcoinAddress() failed: %v", err)
+			}
 			if encoded != tt.address {
 				t.Errorf("EncodeBitcoinAddress() got %s, want %s", encoded, tt.address)
 			}
@@ -0,0 +1,92 @@
+package address_test
+
+import (
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+func TestStacksAddressToBitcoin(t *testing.T) {
+	tests := []struct {
+		name     string
+		stacks   string
+		network  address.BitcoinNetworkType
+		expected string
+	}{
+		{"mainnet", "SP2GKVKM12JZ0YW3ZJH3GMBJYGVNM0BS94ERA45AM", address.Mainnet, "1FhZqHcrXaWcNCJPEGn2BRZ9angJvYfTBT"},
+		{"testnet", "ST2M9C0SHDV4FMXF3R0P98H8GQPW5824DVEJ9MVQZ", address.Testnet, "mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx"},
+		{"mainnet singlesig", "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", address.Mainnet, "1FzTxL9Mxnm2fdmnQEArfhzJHevwbvcH6d"},
+		{"mainnet multisig", "SM2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQVX8X0G", address.Mainnet, "3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y"},
+		{"regtest", "ST2M9C0SHDV4FMXF3R0P98H8GQPW5824DVEJ9MVQZ", address.Regtest, "mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stacksAddress, err := address.FromString(tt.stacks)
+			if err != nil {
+				t.Fatalf("FromString(%s) failed: %v", tt.stacks, err)
+			}
+			result, err := address.StacksAddressToBitcoin(stacksAddress, tt.network)
+			if err != nil {
+				t.Fatalf("StacksAddressToBitcoin(%s) failed: %v", tt.stacks, err)
+			}
+			if result != tt.expected {
+				t.Errorf("StacksAddressToBitcoin(%s) = %s, want %s", tt.stacks, result, tt.expected)
+			}
+		})
+	}
+
+	// The network picks the Bitcoin version, whatever the Stacks address's network
+	mainnetAddress, _ := address.FromString("SM2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQVX8X0G")
+	result, err := address.StacksAddressToBitcoin(mainnetAddress, address.Testnet)
+	if err != nil {
+		t.Fatalf("StacksAddressToBitcoin() failed: %v", err)
+	}
+	decoded, err := address.DecodeBitcoinAddress(result)
+	if err != nil {
+		t.Fatalf("DecodeBitcoinAddress(%s) failed: %v", result, err)
+	}
+	if decoded.AddrType != address.ScriptHash || decoded.NetworkID != address.Testnet || decoded.Hash160Bytes != mainnetAddress.Hash160 {
+		t.Errorf("StacksAddressToBitcoin() = %s, want a testnet P2SH address for %x", result, mainnetAddress.Hash160)
+	}
+
+	if _, err := address.StacksAddressToBitcoin(address.NewStacksAddress(0, [20]byte{}), address.Mainnet); err == nil {
+		t.Errorf("StacksAddressToBitcoin() with unknown version expected error, got nil")
+	}
+}
+
+func TestBitcoinToStacksAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		bitcoin  string
+		expected string
+	}{
+		{"mainnet", "1FhZqHcrXaWcNCJPEGn2BRZ9angJvYfTBT", "SP2GKVKM12JZ0YW3ZJH3GMBJYGVNM0BS94ERA45AM"},
+		{"testnet", "mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx", "ST2M9C0SHDV4FMXF3R0P98H8GQPW5824DVEJ9MVQZ"},
+		{"mainnet singlesig", "1FzTxL9Mxnm2fdmnQEArfhzJHevwbvcH6d", "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"},
+		{"mainnet multisig", "3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y", "SM2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKQVX8X0G"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := address.BitcoinToStacksAddress(tt.bitcoin)
+			if err != nil {
+				t.Fatalf("BitcoinToStacksAddress(%s) failed: %v", tt.bitcoin, err)
+			}
+			if result.String() != tt.expected {
+				t.Errorf("BitcoinToStacksAddress(%s) = %s, want %s", tt.bitcoin, result, tt.expected)
+			}
+		})
+	}
+
+	invalid := []string{
+		"",
+		"1FhZqHcrXaWcNCJPEGn2BRZ9angJvYfTBU", // bad checksum
+		"SP2GKVKM12JZ0YW3ZJH3GMBJYGVNM0BS94ERA45AM",
+	}
+	for _, input := range invalid {
+		if _, err := address.BitcoinToStacksAddress(input); err == nil {
+			t.Errorf("BitcoinToStacksAddress(%q) expected error, got nil", input)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package address_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/network"
+)
+
+func TestFromStringOnNetwork(t *testing.T) {
+	const mainnetAddr = "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
+	const testnetAddr = "ST2M9C0SHDV4FMXF3R0P98H8GQPW5824DVEJ9MVQZ"
+
+	if _, err := address.FromStringOnNetwork(mainnetAddr, network.Mainnet); err != nil {
+		t.Errorf("FromStringOnNetwork(%s, mainnet) failed: %v", mainnetAddr, err)
+	}
+	for _, n := range []network.Network{network.Testnet, network.Devnet} {
+		if _, err := address.FromStringOnNetwork(testnetAddr, n); err != nil {
+			t.Errorf("FromStringOnNetwork(%s, %s) failed: %v", testnetAddr, n, err)
+		}
+	}
+
+	if _, err := address.FromStringOnNetwork(mainnetAddr, network.Testnet); !errors.Is(err, network.ErrWrongNetwork) {
+		t.Errorf("FromStringOnNetwork(%s, testnet) expected ErrWrongNetwork, got %v", mainnetAddr, err)
+	}
+	if _, err := address.FromStringOnNetwork(testnetAddr, network.Mainnet); !errors.Is(err, network.ErrWrongNetwork) {
+		t.Errorf("FromStringOnNetwork(%s, mainnet) expected ErrWrongNetwork, got %v", testnetAddr, err)
+	}
+	if _, err := address.FromStringOnNetwork("SP000", network.Mainnet); err == nil {
+		t.Errorf("FromStringOnNetwork(SP000) expected error, got nil")
+	}
+}
+
+func TestHashModeToVersionOnNetwork(t *testing.T) {
+	testCases := []struct {
+		mode     address.AddressHashMode
+		network  network.Network
+		expected byte
+	}{
+		{address.SerializeP2PKH, network.Mainnet, address.C32AddressVersionMainnetSinglesig},
+		{address.SerializeP2WSH, network.Mainnet, address.C32AddressVersionMainnetMultisig},
+		{address.SerializeP2WPKH, network.Devnet, address.C32AddressVersionTestnetSinglesig},
+		{address.SerializeP2SHNonSequential, network.Testnet, address.C32AddressVersionTestnetMultisig},
+	}
+
+	for _, tc := range testCases {
+		version, err := tc.mode.ToVersion(tc.network)
+		if err != nil {
+			t.Fatalf("ToVersion(%s) failed: %v", tc.network, err)
+		}
+		if version != tc.expected {
+			t.Errorf("%d.ToVersion(%s) = %d, want %d", tc.mode, tc.network, version, tc.expected)
+		}
+	}
+
+	if _, err := address.AddressHashMode(0x04).ToVersion(network.Mainnet); err == nil {
+		t.Errorf("ToVersion() expected error for an invalid hash mode, got nil")
+	}
+}
+
+func TestDecodeBitcoinAddressOnNetwork(t *testing.T) {
+	testCases := []struct {
+		address  string
+		network  network.Network
+		expected address.BitcoinNetworkType
+	}{
+		{"1FzTxL9Mxnm2fdmnQEArfhzJHevwbvcH6d", network.Mainnet, address.Mainnet},
+		{"3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y", network.Mainnet, address.Mainnet},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", network.Mainnet, address.Mainnet},
+		{"mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx", network.Testnet, address.Testnet},
+		{"mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx", network.Devnet, address.Regtest},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", network.Testnet, address.Testnet},
+		{"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", network.Devnet, address.Regtest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.address+" on "+tc.network.String(), func(t *testing.T) {
+			decoded, err := address.DecodeBitcoinAddressOnNetwork(tc.address, tc.network)
+			if err != nil {
+				t.Fatalf("DecodeBitcoinAddressOnNetwork() failed: %v", err)
+			}
+			if decoded.NetworkID != tc.expected {
+				t.Errorf("NetworkID = %v, want %v", decoded.NetworkID, tc.expected)
+			}
+			if address.BitcoinNetwork(tc.network) != tc.expected {
+				t.Errorf("BitcoinNetwork(%s) = %v, want %v", tc.network, address.BitcoinNetwork(tc.network), tc.expected)
+			}
+		})
+	}
+
+	wrongNetwork := map[string]network.Network{
+		"1FzTxL9Mxnm2fdmnQEArfhzJHevwbvcH6d":           network.Testnet,
+		"mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx":           network.Mainnet,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4":   network.Testnet,
+		"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx":   network.Devnet,
+		"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080": network.Mainnet,
+	}
+	for addr, n := range wrongNetwork {
+		if _, err := address.DecodeBitcoinAddressOnNetwork(addr, n); !errors.Is(err, network.ErrWrongNetwork) {
+			t.Errorf("DecodeBitcoinAddressOnNetwork(%s, %s) expected ErrWrongNetwork, got %v", addr, n, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package address_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/janniks/stacks-go/lib/address"
+)
+
+func TestPublicKeysToHash160(t *testing.T) {
+	// Public key and hash160 from the Bitcoin wiki's address walkthrough
+	wikiKey := mustDecodeHex("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+	// Public key and P2SH-P2WPKH address from the BIP 49 test vectors
+	bip49Key := mustDecodeHex("03a1af804ac108a8a51782198c2d034b28bf90c8803f5a53f76276fa69a4eae77f")
+	bip49Address, err := address.DecodeBitcoinAddress("2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2")
+	if err != nil {
+		t.Fatalf("Failed to decode BIP 49 address: %v", err)
+	}
+
+	// 2-of-3 redeem script: OP_2 <key>... OP_3 OP_CHECKMULTISIG
+	multisigKeys := [][]byte{wikiKey, bip49Key, mustDecodeHex("02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5")}
+	script := []byte{0x52}
+	for _, key := range multisigKeys {
+		script = append(append(script, 33), key...)
+	}
+	script = append(script, 0x53, 0xae)
+	scriptHash := sha256.Sum256(script)
+
+	testCases := []struct {
+		name       string
+		hashMode   address.AddressHashMode
+		required   int
+		publicKeys [][]byte
+		expected   [20]byte
+	}{
+		{"P2PKH", address.SerializeP2PKH, 1, [][]byte{wikiKey}, mustHash160Hex("f54a5851e9372b87810a8e60cdd2e7cfd80b6e31")},
+		{"P2WPKH", address.SerializeP2WPKH, 1, [][]byte{bip49Key}, bip49Address.Hash160Bytes},
+		{"P2SH", address.SerializeP2SH, 2, multisigKeys, testHash160(script)},
+		{"P2SH non-sequential", address.SerializeP2SHNonSequential, 2, multisigKeys, testHash160(script)},
+		{"P2WSH", address.SerializeP2WSH, 2, multisigKeys, testHash160(append([]byte{0x00, 0x20}, scriptHash[:]...))},
+		{"P2WSH non-sequential", address.SerializeP2WSHNonSequential, 2, multisigKeys, testHash160(append([]byte{0x00, 0x20}, scriptHash[:]...))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := address.PublicKeysToHash160(tc.hashMode, tc.required, tc.publicKeys)
+			if err != nil {
+				t.Fatalf("PublicKeysToHash160() returned error: %v", err)
+			}
+			if hash != tc.expected {
+				t.Errorf("PublicKeysToHash160() = %x, expected %x", hash, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPublicKeysToHash160LargeMultisig(t *testing.T) {
+	// Counts above 16 are pushed as minimal script numbers instead of OP_n
+	keys := make([][]byte, 17)
+	script := []byte{0x01, 0x11}
+	for i := range keys {
+		keys[i] = mustDecodeHex("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+		script = append(append(script, 33), keys[i]...)
+	}
+	script = append(script, 0x01, 0x11, 0xae)
+
+	hash, err := address.PublicKeysToHash160(address.SerializeP2SH, 17, keys)
+	if err != nil {
+		t.Fatalf("PublicKeysToHash160() returned error: %v", err)
+	}
+	if hash != testHash160(script) {
+		t.Errorf("PublicKeysToHash160() = %x, expected %x", hash, testHash160(script))
+	}
+}
+
+func TestFromPublicKeys(t *testing.T) {
+	key := mustDecodeHex("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+	addr, err := address.FromPublicKeys(address.C32AddressVersionMainnetSinglesig, address.SerializeP2PKH, 1, [][]byte{key})
+	if err != nil {
+		t.Fatalf("FromPublicKeys() returned error: %v", err)
+	}
+	expected := address.NewStacksAddress(address.C32AddressVersionMainnetSinglesig, mustHash160Hex("f54a5851e9372b87810a8e60cdd2e7cfd80b6e31"))
+	if addr != expected {
+		t.Errorf("FromPublicKeys() = %v, expected %v", addr, expected)
+	}
+}
+
+func TestPublicKeysToHash160Errors(t *testing.T) {
+	compressed := mustDecodeHex("0250863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b2352")
+	uncompressed := mustDecodeHex("0450863ad64a87ae8a2fe83c1af1a8403cb53f53e486d8511dad8a04887e5b23522cd470243453a299fa9e77237716103abc11a1df38855ed6f2ee187e9c582ba6")
+
+	tests := []struct {
+		name       string
+		hashMode   address.AddressHashMode
+		required   int
+		publicKeys [][]byte
+	}{
+		{"invalid hash mode", address.AddressHashMode(0x04), 1, [][]byte{compressed}},
+		{"no public keys", address.SerializeP2SH, 1, nil},
+		{"singlesig with two keys", address.SerializeP2PKH, 1, [][]byte{compressed, compressed}},
+		{"singlesig requiring two signatures", address.SerializeP2WPKH, 2, [][]byte{compressed}},
+		{"no signatures required", address.SerializeP2SH, 0, [][]byte{compressed}},
+		{"more signatures than keys", address.SerializeP2SHNonSequential, 2, [][]byte{compressed}},
+		{"invalid public key", address.SerializeP2PKH, 1, [][]byte{compressed[1:]}},
+		{"uncompressed P2WPKH", address.SerializeP2WPKH, 1, [][]byte{uncompressed}},
+		{"uncompressed P2WSH", address.SerializeP2WSHNonSequential, 1, [][]byte{compressed, uncompressed}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := address.PublicKeysToHash160(test.hashMode, test.required, test.publicKeys); err == nil {
+				t.Errorf("PublicKeysToHash160() expected error, got nil")
+			}
+		})
+	}
+
+	// Uncompressed keys are fine outside segwit
+	if _, err := address.PublicKeysToHash160(address.SerializeP2SH, 1, [][]byte{uncompressed}); err != nil {
+		t.Errorf("PublicKeysToHash160() returned error for uncompressed P2SH key: %v", err)
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustHash160Hex(s string) [20]byte {
+	var hash [20]byte
+	copy(hash[:], mustDecodeHex(s))
+	return hash
+}
+
+func testHash160(data []byte) [20]byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	var hash [20]byte
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
@@ -0,0 +1,275 @@
+package builder_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/builder"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/post_condition"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+var (
+	recipientHash = [20]byte{0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55}
+	mainnetAddr   = address.NewStacksAddress(address.C32AddressVersionMainnetSinglesig, recipientHash)
+	testnetAddr   = address.NewStacksAddress(address.C32AddressVersionTestnetSinglesig, recipientHash)
+)
+
+func TestBuildTokenTransfer(t *testing.T) {
+	origin := testOrigin(t)
+	tx, err := builder.NewTokenTransfer(testnetAddr.String()+".vault", 1_000_000).
+		Memo("hello").
+		Testnet().
+		Origin(origin).
+		Nonce(7).
+		Fee(180).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+
+	expected := "80" + "80000000" +
+		"04" + "00" + hex.EncodeToString(origin.Signer[:]) + "0000000000000007" + "00000000000000b4" + "00" + strings.Repeat("00", 65) +
+		"03" + "02" + "00000000" +
+		"00" + "06" + "1a" + strings.Repeat("55", 20) + "057661756c74" + "00000000000f4240" + "68656c6c6f" + strings.Repeat("00", 29)
+	assertSerialized(t, tx, expected)
+}
+
+func TestBuildContractCall(t *testing.T) {
+	pc := post_condition.PostCondition{
+		Type:          post_condition.AssetInfoSTX,
+		Principal:     post_condition.Principal{Type: post_condition.PrincipalOrigin},
+		ConditionCode: byte(post_condition.FCSentLe),
+		Amount:        100,
+	}
+	tx, err := builder.NewContractCall(mainnetAddr.String()+".market", "buy",
+		clarity_value.NewUIntValue(100), clarity_value.BoolValue(true)).
+		Origin(testOrigin(t)).
+		AnchorMode(transaction.TransactionAnchorModeOnChainOnly).
+		PostConditions(pc).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+
+	if tx.Version != transaction.TransactionVersionMainnet || tx.ChainID != transaction.ChainIDMainnet {
+		t.Errorf("Expected a mainnet transaction, got version %d and chain ID %d", tx.Version, tx.ChainID)
+	}
+	if tx.AnchorMode != transaction.TransactionAnchorModeOnChainOnly || tx.PostConditionMode != transaction.TransactionPostConditionModeDeny {
+		t.Errorf("Expected anchor mode 1 and post condition mode deny, got %d and %d", tx.AnchorMode, tx.PostConditionMode)
+	}
+	expectedPostConditions := "02" + "00000001" + "00" + "01" + "05" + "0000000000000064"
+	if hex.EncodeToString(tx.PostConditionsSerialized) != expectedPostConditions {
+		t.Errorf("Expected post conditions %s, got %x", expectedPostConditions, tx.PostConditionsSerialized)
+	}
+
+	call := tx.Payload.ContractCall
+	if string(call.ContractName) != "market" || string(call.FunctionName) != "buy" {
+		t.Errorf("Expected market.buy, got %s.%s", call.ContractName, call.FunctionName)
+	}
+	if len(call.FunctionArgs) != 2 || call.FunctionArgs[0].Value.ReprString() != "u100" || call.FunctionArgs[1].Value.ReprString() != "true" {
+		t.Errorf("Expected arguments u100 and true, got %v", call.FunctionArgs)
+	}
+}
+
+func TestBuildSmartContract(t *testing.T) {
+	testCases := map[string]struct {
+		builder     *builder.Builder
+		payloadType uint8
+		payload     string
+	}{
+		"Smart contract": {
+			builder.NewSmartContract("hello", "(ok 1)"),
+			transaction.TransactionPayloadIDSmartContract,
+			"01" + "0568656c6c6f" + "00000006" + hex.EncodeToString([]byte("(ok 1)")),
+		},
+		"Versioned smart contract": {
+			builder.NewSmartContract("hello", "(ok 1)").ClarityVersion(transaction.ClarityVersion3),
+			transaction.TransactionPayloadIDVersionedSmartContract,
+			"06" + "03" + "0568656c6c6f" + "00000006" + hex.EncodeToString([]byte("(ok 1)")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx, err := tc.builder.Origin(testOrigin(t)).PostConditionMode(transaction.TransactionPostConditionModeAllow).Build()
+			if err != nil {
+				t.Fatalf("Failed to build transaction: %v", err)
+			}
+			if tx.Payload.PayloadType != tc.payloadType {
+				t.Errorf("Expected payload type %d, got %d", tc.payloadType, tx.Payload.PayloadType)
+			}
+
+			serialized, err := tx.Serialize()
+			if err != nil {
+				t.Fatalf("Failed to serialize transaction: %v", err)
+			}
+			if !strings.HasSuffix(hex.EncodeToString(serialized), "03"+"01"+"00000000"+tc.payload) {
+				t.Errorf("Expected payload %s, got %x", tc.payload, serialized)
+			}
+		})
+	}
+}
+
+func TestBuildSponsored(t *testing.T) {
+	tx, err := builder.NewTokenTransfer(mainnetAddr.String(), 1).Origin(testOrigin(t)).Sponsored().Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	if tx.Auth.AuthType != transaction.TransactionAuthFlagSponsored || tx.Auth.SponsorSpendingCondition == nil {
+		t.Fatalf("Expected a sponsored transaction, got auth type %d", tx.Auth.AuthType)
+	}
+	if tx.Auth.SponsorSpendingCondition.Signer != [20]byte{} {
+		t.Errorf("Expected an empty sponsor spending condition, got signer %x", tx.Auth.SponsorSpendingCondition.Signer)
+	}
+}
+
+func TestBuildMultisig(t *testing.T) {
+	var publicKeys [][]byte
+	for seed := byte(1); seed <= 3; seed++ {
+		publicKeys = append(publicKeys, testPublicKey(seed))
+	}
+	origin, err := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2WSHNonSequential, 2, publicKeys)
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+
+	tx, err := builder.NewTokenTransfer(mainnetAddr.String(), 1).Origin(origin).Fee(300).Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	condition := tx.Auth.SpendingCondition
+	if condition.HashMode != transaction.MultisigHashModeP2WSHNonSequential || *condition.SignaturesRequired != 2 || len(condition.Fields) != 0 {
+		t.Errorf("Expected an unsigned 2-of-n P2WSH condition, got %+v", condition)
+	}
+	if condition.Signer != origin.Signer || condition.Fee != 300 {
+		t.Errorf("Expected signer %x with fee 300, got %x with fee %d", origin.Signer, condition.Signer, condition.Fee)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	origin := testOrigin(t)
+	testnetPostCondition := post_condition.PostCondition{
+		Type:          post_condition.AssetInfoSTX,
+		Principal:     post_condition.Principal{Type: post_condition.PrincipalStandard, Address: testnetAddr},
+		ConditionCode: byte(post_condition.FCSentEq),
+	}
+
+	testCases := map[string]*builder.Builder{
+		"Mainnet version with testnet chain ID": builder.NewTokenTransfer(mainnetAddr.String(), 1).ChainID(transaction.ChainIDTestnet),
+		"Testnet version with mainnet chain ID": builder.NewTokenTransfer(testnetAddr.String(), 1).Version(transaction.TransactionVersionTestnet),
+		"Testnet recipient on mainnet":          builder.NewTokenTransfer(testnetAddr.String(), 1),
+		"Mainnet contract on testnet":           builder.NewContractCall(mainnetAddr.String()+".market", "buy").Testnet(),
+		"Testnet post condition on mainnet":     builder.NewTokenTransfer(mainnetAddr.String(), 1).PostConditions(testnetPostCondition),
+		"Invalid recipient":                     builder.NewTokenTransfer("SP000", 1),
+		"Invalid contract identifier":           builder.NewContractCall(mainnetAddr.String(), "buy"),
+		"Invalid contract name":                 builder.NewContractCall(mainnetAddr.String()+".1market", "buy"),
+		"Invalid function name":                 builder.NewContractCall(mainnetAddr.String()+".market", "buy now"),
+		"Missing argument":                      builder.NewContractCall(mainnetAddr.String()+".market", "buy", nil),
+		"Memo too long":                         builder.NewTokenTransfer(mainnetAddr.String(), 1).Memo(strings.Repeat("m", 35)),
+		"Memo on contract call":                 builder.NewContractCall(mainnetAddr.String()+".market", "buy").Memo("m"),
+		"Clarity version on transfer":           builder.NewTokenTransfer(mainnetAddr.String(), 1).ClarityVersion(transaction.ClarityVersion2),
+		"Unknown clarity version":               builder.NewSmartContract("hello", "(ok 1)").ClarityVersion(9),
+		"Invalid anchor mode":                   builder.NewSmartContract("hello", "(ok 1)").AnchorMode(4),
+		"Invalid post condition mode":           builder.NewSmartContract("hello", "(ok 1)").PostConditionMode(0),
+	}
+
+	for name, b := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := b.Origin(origin).Build()
+			if !errors.Is(err, builder.ErrInvalidTransaction) {
+				t.Errorf("Expected ErrInvalidTransaction, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Missing origin", func(t *testing.T) {
+		_, err := builder.NewTokenTransfer(mainnetAddr.String(), 1).Build()
+		if !errors.Is(err, builder.ErrInvalidTransaction) {
+			t.Errorf("Expected ErrInvalidTransaction, got %v", err)
+		}
+	})
+}
+
+func TestBuildSubnetChainID(t *testing.T) {
+	// Testnet-versioned transactions may use any chain ID no other network uses
+	tx, err := builder.NewTokenTransfer(testnetAddr.String(), 1).Testnet().ChainID(0x55005500).Origin(testOrigin(t)).Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	if tx.ChainID != 0x55005500 {
+		t.Errorf("Expected chain ID 0x55005500, got %#x", tx.ChainID)
+	}
+}
+
+func TestBuildNetwork(t *testing.T) {
+	subnet := network.NewSubnet("subnet", 0x55005500, network.Devnet)
+	tx, err := builder.NewTokenTransfer(testnetAddr.String(), 1).Network(subnet).Origin(testOrigin(t)).Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	if tx.Version != transaction.TransactionVersionTestnet || tx.ChainID != 0x55005500 {
+		t.Errorf("Expected version 0x80 and chain ID 0x55005500, got %#x and %#x", tx.Version, tx.ChainID)
+	}
+	if err := tx.CheckNetwork(subnet); err != nil {
+		t.Errorf("Expected the transaction to be on the subnet, got %v", err)
+	}
+
+	_, err = builder.NewTokenTransfer(mainnetAddr.String(), 1).Network(subnet).Origin(testOrigin(t)).Build()
+	if !errors.Is(err, builder.ErrInvalidTransaction) || !errors.Is(err, network.ErrWrongNetwork) {
+		t.Errorf("Expected ErrInvalidTransaction and ErrWrongNetwork, got %v", err)
+	}
+}
+
+func TestBuildMainnetSubnet(t *testing.T) {
+	subnet := network.NewSubnet("subnet", 0x55005500, network.Mainnet)
+	tx, err := builder.NewTokenTransfer(mainnetAddr.String(), 1).Network(subnet).Origin(testOrigin(t)).Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	if tx.Version != transaction.TransactionVersionMainnet || tx.ChainID != 0x55005500 {
+		t.Errorf("Expected version 0x00 and chain ID 0x55005500, got %#x and %#x", tx.Version, tx.ChainID)
+	}
+
+	_, err = builder.NewTokenTransfer(testnetAddr.String(), 1).Network(subnet).Origin(testOrigin(t)).Build()
+	if !errors.Is(err, builder.ErrInvalidTransaction) || !errors.Is(err, network.ErrWrongNetwork) {
+		t.Errorf("Expected ErrInvalidTransaction and ErrWrongNetwork, got %v", err)
+	}
+}
+
+// testOrigin returns an unsigned P2PKH spending condition
+func testOrigin(t *testing.T) transaction.TransactionSpendingCondition {
+	t.Helper()
+	origin, err := transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, testPublicKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+	return origin
+}
+
+// testPublicKey returns the compressed public key of the private key with every byte set to seed
+func testPublicKey(seed byte) []byte {
+	var keyBytes [32]byte
+	for i := range keyBytes {
+		keyBytes[i] = seed
+	}
+	return secp256k1.PrivKeyFromBytes(keyBytes[:]).PubKey().SerializeCompressed()
+}
+
+func assertSerialized(t *testing.T, tx *transaction.StacksTransaction, expected string) {
+	t.Helper()
+	serialized, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	if hex.EncodeToString(serialized) != expected {
+		t.Errorf("Expected %s, got %x", expected, serialized)
+	}
+}
@@ -18,7 +18,7 @@ func TestDecodeClarityValueToObject(t *testing.T) {
 		{
 			name: "Int value",
 			clarityVal: func() *clarity_value.ClarityValue {
-				val := clarity_value.IntValue(42)
+				val := clarity_value.NewIntValue(42)
 				return &clarity_value.ClarityValue{
 					Value:           val,
 					SerializedBytes: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a}, // 42 in big-endian
@@ -27,8 +27,8 @@ func TestDecodeClarityValueToObject(t *testing.T) {
 			bytes: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a},
 			deep:  true,
 			validate: func(t *testing.T, result *clarity_value.DecodedClarityValue) {
-				if result.Repr != clarity_value.IntValue(42).ReprString() {
-					t.Errorf("Expected repr %s, got %s", clarity_value.IntValue(42).ReprString(), result.Repr)
+				if result.Repr != clarity_value.NewIntValue(42).ReprString() {
+					t.Errorf("Expected repr %s, got %s", clarity_value.NewIntValue(42).ReprString(), result.Repr)
 				}
 				if result.TypeID != int(clarity_value.PrefixInt) {
 					t.Errorf("Expected type_id %d, got %d", int(clarity_value.PrefixInt), result.TypeID)
@@ -93,7 +93,7 @@ func TestDecodeClarityValueToObject(t *testing.T) {
 		{
 			name: "When deep is false",
 			clarityVal: func() *clarity_value.ClarityValue {
-				val := clarity_value.IntValue(42)
+				val := clarity_value.NewIntValue(42)
 				return &clarity_value.ClarityValue{
 					Value:           val,
 					SerializedBytes: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a},
@@ -102,8 +102,8 @@ func TestDecodeClarityValueToObject(t *testing.T) {
 			bytes: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a},
 			deep:  false,
 			validate: func(t *testing.T, result *clarity_value.DecodedClarityValue) {
-				if result.Repr != clarity_value.IntValue(42).ReprString() {
-					t.Errorf("Expected repr %s, got %s", clarity_value.IntValue(42).ReprString(), result.Repr)
+				if result.Repr != clarity_value.NewIntValue(42).ReprString() {
+					t.Errorf("Expected repr %s, got %s", clarity_value.NewIntValue(42).ReprString(), result.Repr)
 				}
 				if result.TypeID != int(clarity_value.PrefixInt) {
 					t.Errorf("Expected type_id %d, got %d", int(clarity_value.PrefixInt), result.TypeID)
@@ -133,7 +133,7 @@ func TestDecodeClarityValueToObject(t *testing.T) {
 
 func TestDecodeClarityValueToObjectWithSerializedBytes(t *testing.T) {
 	// Create a test case where we use the SerializedBytes from the ClarityValue
-	intVal := clarity_value.IntValue(42)
+	intVal := clarity_value.NewIntValue(42)
 	serializedBytes := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a}
 
 	clarityVal := clarity_value.ClarityValue{
@@ -3,6 +3,7 @@ package clarity_value_test
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/janniks/stacks-go/lib/clarity_value"
@@ -199,8 +200,8 @@ func TestDecodeClarityValue(t *testing.T) {
 				if !ok {
 					t.Fatalf("Expected IntValue, got %T", value)
 				}
-				if intValue != 10 {
-					t.Errorf("Expected int value 10, got %d", intValue)
+				if intValue != clarity_value.NewIntValue(10) {
+					t.Errorf("Expected int value 10, got %s", intValue.ReprString())
 				}
 			},
 			hasError: false,
@@ -214,8 +215,8 @@ func TestDecodeClarityValue(t *testing.T) {
 				if !ok {
 					t.Fatalf("Expected UIntValue, got %T", value)
 				}
-				if uintValue != 15 {
-					t.Errorf("Expected uint value 15, got %d", uintValue)
+				if uintValue != clarity_value.NewUIntValue(15) {
+					t.Errorf("Expected uint value 15, got %s", uintValue.ReprString())
 				}
 			},
 			hasError: false,
@@ -385,6 +386,24 @@ func TestDecodeClarityValue(t *testing.T) {
 			},
 			hasError: true,
 		},
+		{
+			name:      "Duplicate tuple field",
+			input:     "0c00000002016103016103", // Tuple{a: true, a: true}
+			withBytes: false,
+			valueCheck: func(t *testing.T, value clarity_value.Value) {
+				// Should not be called due to error
+			},
+			hasError: true,
+		},
+		{
+			name:      "Invalid UTF-8 string",
+			input:     "0e00000001ff", // StringUTF8 with a lone 0xff byte
+			withBytes: false,
+			valueCheck: func(t *testing.T, value clarity_value.Value) {
+				// Should not be called due to error
+			},
+			hasError: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -423,3 +442,134 @@ func TestDecodeClarityValue(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeClarityValueWithType(t *testing.T) {
+	testCases := []struct {
+		name      string
+		typ       string
+		value     string
+		sanitize  bool
+		expected  string // repr of the decoded value
+		errorPath string // empty when decoding succeeds
+	}{
+		{
+			name:     "Matching uint",
+			typ:      "uint",
+			value:    "u1",
+			expected: "u1",
+		},
+		{
+			name:      "Wrong prefix",
+			typ:       "uint",
+			value:     "1",
+			errorPath: "value",
+		},
+		{
+			name:      "Buffer too long",
+			typ:       "(buff 2)",
+			value:     "0x010203",
+			errorPath: "value",
+		},
+		{
+			name:      "UTF8 too long",
+			typ:       "(string-utf8 1)",
+			value:     `u"ab"`,
+			errorPath: "value",
+		},
+		{
+			name:      "List too long",
+			typ:       "(list 2 uint)",
+			value:     "(list u1 u2 u3)",
+			errorPath: "value",
+		},
+		{
+			name:      "Nested element mismatch",
+			typ:       "(list 3 (tuple (id uint) (owner (optional principal))))",
+			value:     "(list {id: u1, owner: none} {id: u2, owner: (some u3)})",
+			errorPath: "value[1].owner.some",
+		},
+		{
+			name:      "Response err mismatch",
+			typ:       "(response bool uint)",
+			value:     "(err true)",
+			errorPath: "value.err",
+		},
+		{
+			name:      "Extra tuple field without sanitization",
+			typ:       "(tuple (a uint))",
+			value:     "{a: u1, b: u2}",
+			errorPath: "value",
+		},
+		{
+			name:     "Extra tuple field with sanitization",
+			typ:      "(tuple (a uint))",
+			value:    "{a: u1, b: u2}",
+			sanitize: true,
+			expected: "(tuple (a u1))",
+		},
+		{
+			name:     "Nested sanitization",
+			typ:      "(list 2 (optional (tuple (a uint))))",
+			value:    "(list (some {a: u1, b: (list 1 2)}) none)",
+			sanitize: true,
+			expected: "(list (some (tuple (a u1))) none)",
+		},
+		{
+			name:      "Missing tuple field with sanitization",
+			typ:       "(tuple (a uint) (c uint))",
+			value:     "{a: u1, b: u2}",
+			sanitize:  true,
+			errorPath: "value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, err := clarity_value.ParseTypeSignature(tc.typ)
+			if err != nil {
+				t.Fatalf("Failed to parse type: %v", err)
+			}
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			inputBytes, err := clarity_value.SerializeClarityValue(value)
+			if err != nil {
+				t.Fatalf("Failed to serialize value: %v", err)
+			}
+
+			opts := clarity_value.DecodeOptions{WithBytes: true, Sanitize: tc.sanitize}
+			result, err := clarity_value.DecodeClarityValueWithType(bytes.NewReader(inputBytes), typ, opts)
+
+			if tc.errorPath != "" {
+				var admissionErr *clarity_value.AdmissionError
+				if !errors.As(err, &admissionErr) {
+					t.Fatalf("Expected AdmissionError, got %v", err)
+				}
+				if admissionErr.Path != tc.errorPath {
+					t.Errorf("Expected error path %s, got %s (%v)", tc.errorPath, admissionErr.Path, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if repr := result.Value.ReprString(); repr != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, repr)
+			}
+			if err := clarity_value.Admits(typ, result.Value); err != nil {
+				t.Errorf("Decoded value not admitted by its type: %v", err)
+			}
+
+			// Serialized bytes must describe the value after sanitization
+			serialized, err := clarity_value.SerializeClarityValue(result.Value)
+			if err != nil {
+				t.Fatalf("Failed to serialize result: %v", err)
+			}
+			if !bytes.Equal(serialized, result.SerializedBytes) {
+				t.Errorf("Expected serialized bytes %x, got %x", serialized, result.SerializedBytes)
+			}
+		})
+	}
+}
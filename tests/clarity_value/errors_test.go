@@ -0,0 +1,118 @@
+package clarity_value_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestDecodeClarityValueErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		kind   clarity_value.DeserializeErrorKind
+		offset int64
+		path   string
+	}{
+		{"Empty input", "", clarity_value.KindTruncated, 0, "value"},
+		{"Truncated int", "0100", clarity_value.KindTruncated, 0, "value"},
+		{"Bad prefix", "ff", clarity_value.KindBadPrefix, 0, "value"},
+		{"List too large", "0b00100001", clarity_value.KindTooLarge, 0, "value"},
+		{"Bad prefix in list", "0b0000000203ff", clarity_value.KindBadPrefix, 6, "value[1]"},
+		{"Bad prefix in tuple", "0c0000000101610aff", clarity_value.KindBadPrefix, 8, "value.a.some"},
+		{"Truncated tuple field", "0c00000001016107", clarity_value.KindTruncated, 8, "value.a.ok"},
+		{"Invalid field name", "0c000000010131" + "03", clarity_value.KindInvalidName, 5, "value"},
+		{"Duplicate field", "0c00000002016103016103", clarity_value.KindDuplicateField, 0, "value"},
+		{"Invalid UTF-8", "0e00000001ff", clarity_value.KindInvalidUTF8, 0, "value"},
+		{"Too deep", strings.Repeat("0a", 16) + "03", clarity_value.KindTooDeep, 16, "value" + strings.Repeat(".some", 16)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			// The scanning pass used to capture bytes reports the same errors
+			for _, withBytes := range []bool{false, true} {
+				_, err := clarity_value.DecodeClarityValue(bytes.NewReader(inputBytes), withBytes)
+				if !errors.Is(err, clarity_value.ErrDeserialize) {
+					t.Fatalf("Expected ErrDeserialize, got %v", err)
+				}
+				if !errors.Is(err, tc.kind) {
+					t.Errorf("Expected kind %q, got %v", tc.kind, err)
+				}
+				var deserializeErr *clarity_value.DeserializeError
+				if !errors.As(err, &deserializeErr) {
+					t.Fatalf("Expected DeserializeError, got %T", err)
+				}
+				if deserializeErr.Offset != tc.offset {
+					t.Errorf("Expected offset %d, got %d (%v)", tc.offset, deserializeErr.Offset, err)
+				}
+				if deserializeErr.Path != tc.path {
+					t.Errorf("Expected path %s, got %s (%v)", tc.path, deserializeErr.Path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeClarityValueErrorEOF(t *testing.T) {
+	// Only a stream that ends before a value starts reports io.EOF
+	_, err := clarity_value.DecodeClarityValue(bytes.NewReader(nil), false)
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	_, err = clarity_value.DecodeClarityValue(bytes.NewReader([]byte{0x0a}), false)
+	if errors.Is(err, io.EOF) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestDecodeClarityValueErrorTypeMismatch(t *testing.T) {
+	expected, err := clarity_value.ParseTypeSignature("(list 2 uint)")
+	if err != nil {
+		t.Fatalf("Failed to parse type: %v", err)
+	}
+	inputBytes, _ := hex.DecodeString("0b00000002" + "00" + strings.Repeat("00", 16) + "00" + strings.Repeat("00", 16))
+
+	_, err = clarity_value.DecodeClarityValueWithType(bytes.NewReader(inputBytes), expected, clarity_value.DecodeOptions{})
+	if !errors.Is(err, clarity_value.KindTypeMismatch) {
+		t.Fatalf("Expected type mismatch, got %v", err)
+	}
+	var deserializeErr *clarity_value.DeserializeError
+	if !errors.As(err, &deserializeErr) || deserializeErr.Offset != 5 || deserializeErr.Path != "value[0]" {
+		t.Errorf("Expected offset 5 at value[0], got %v", err)
+	}
+	var admissionErr *clarity_value.AdmissionError
+	if !errors.As(err, &admissionErr) || admissionErr.Path != "value[0]" {
+		t.Errorf("Expected AdmissionError at value[0], got %v", err)
+	}
+}
+
+func TestDecodeErrorOffsetReader(t *testing.T) {
+	// Offsets count from the position given to the OffsetReader
+	inputBytes, _ := hex.DecodeString("0b0000000203ff")
+	r := clarity_value.NewOffsetReader(bytes.NewReader(inputBytes), 100)
+
+	_, err := clarity_value.NewDecoder(r).DecodeWithOptions(clarity_value.DecodeOptions{Path: "args[2]"})
+	var deserializeErr *clarity_value.DeserializeError
+	if !errors.As(err, &deserializeErr) {
+		t.Fatalf("Expected DeserializeError, got %v", err)
+	}
+	if deserializeErr.Offset != 106 || deserializeErr.Path != "args[2][1]" {
+		t.Errorf("Expected offset 106 at args[2][1], got %v", err)
+	}
+
+	expected := "failed to deserialize args[2][1] at offset 106: bad type prefix: 255"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
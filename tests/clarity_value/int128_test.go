@@ -0,0 +1,152 @@
+package clarity_value_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestDecodeClarity128BitIntegers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Int zero",
+			input:    "0000000000000000000000000000000000",
+			expected: "0",
+		},
+		{
+			name:     "Int minus one",
+			input:    "00ffffffffffffffffffffffffffffffff",
+			expected: "-1",
+		},
+		{
+			name:     "Int above 2^64",
+			input:    "0000000000000000010000000000000000",
+			expected: "18446744073709551616",
+		},
+		{
+			name:     "Int below -2^64",
+			input:    "00fffffffffffffffeffffffffffffffff",
+			expected: "-18446744073709551617",
+		},
+		{
+			name:     "Int max",
+			input:    "007fffffffffffffffffffffffffffffff",
+			expected: "170141183460469231731687303715884105727",
+		},
+		{
+			name:     "Int min",
+			input:    "0080000000000000000000000000000000",
+			expected: "-170141183460469231731687303715884105728",
+		},
+		{
+			name:     "UInt above 2^64",
+			input:    "0100000000000000010000000000000000",
+			expected: "u18446744073709551616",
+		},
+		{
+			name:     "UInt max",
+			input:    "01ffffffffffffffffffffffffffffffff",
+			expected: "u340282366920938463463374607431768211455",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			result, err := clarity_value.DecodeClarityValue(bytes.NewReader(inputBytes), true)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if repr := result.Value.ReprString(); repr != tc.expected {
+				t.Errorf("Expected repr %s, got %s", tc.expected, repr)
+			}
+
+			// The 16-byte payload must survive the round trip exactly
+			var encoded [16]byte
+			switch v := result.Value.(type) {
+			case clarity_value.IntValue:
+				encoded = clarity_value.Int128(v).Bytes()
+			case clarity_value.UIntValue:
+				encoded = clarity_value.Uint128(v).Bytes()
+			default:
+				t.Fatalf("Expected integer value, got %T", result.Value)
+			}
+			if !bytes.Equal(encoded[:], inputBytes[1:]) {
+				t.Errorf("Expected payload %x, got %x", inputBytes[1:], encoded)
+			}
+
+			decoded, err := clarity_value.DecodeClarityValueToObject(&result, true, result.SerializedBytes)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expectedValue := strings.TrimPrefix(tc.expected, "u")
+			if decoded.Value != expectedValue {
+				t.Errorf("Expected decoded value %s, got %v", expectedValue, decoded.Value)
+			}
+		})
+	}
+}
+
+func TestInt128BigConversion(t *testing.T) {
+	values := []string{
+		"0",
+		"1",
+		"-1",
+		"9223372036854775807",
+		"-9223372036854775808",
+		"18446744073709551616",
+		"-18446744073709551617",
+		"170141183460469231731687303715884105727",
+		"-170141183460469231731687303715884105728",
+	}
+
+	for _, s := range values {
+		b, _ := new(big.Int).SetString(s, 10)
+		i, err := clarity_value.Int128FromBig(b)
+		if err != nil {
+			t.Fatalf("Int128FromBig(%s) returned error: %v", s, err)
+		}
+		if got := i.String(); got != s {
+			t.Errorf("Int128FromBig(%s).String() = %s", s, got)
+		}
+		if got := clarity_value.IntValue(i).Big(); got.Cmp(b) != 0 {
+			t.Errorf("IntValue.Big() = %s, expected %s", got, s)
+		}
+	}
+
+	outOfRange := []string{
+		"170141183460469231731687303715884105728",
+		"-170141183460469231731687303715884105729",
+	}
+	for _, s := range outOfRange {
+		b, _ := new(big.Int).SetString(s, 10)
+		if _, err := clarity_value.Int128FromBig(b); err == nil {
+			t.Errorf("Int128FromBig(%s) expected error, got nil", s)
+		}
+	}
+
+	if _, err := clarity_value.Uint128FromBig(big.NewInt(-1)); err == nil {
+		t.Errorf("Uint128FromBig(-1) expected error, got nil")
+	}
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err := clarity_value.Uint128FromBig(tooBig); err == nil {
+		t.Errorf("Uint128FromBig(2^128) expected error, got nil")
+	}
+
+	if clarity_value.NewIntValue(-5) != clarity_value.IntValue(clarity_value.Int128{Hi: -1, Lo: ^uint64(4)}) {
+		t.Errorf("NewIntValue(-5) did not sign-extend")
+	}
+}
@@ -0,0 +1,212 @@
+package clarity_value_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestClarityJSONRoundTrip(t *testing.T) {
+	for _, tc := range serializeTestVectors {
+		t.Run(tc.name, func(t *testing.T) {
+			inputBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			decoded, err := clarity_value.DecodeClarityValue(bytes.NewReader(inputBytes), false)
+			if err != nil {
+				t.Fatalf("Unexpected decode error: %v", err)
+			}
+
+			encoded, err := json.Marshal(decoded.Value)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %v", err)
+			}
+
+			parsed, err := clarity_value.ParseClarityJSON(encoded)
+			if err != nil {
+				t.Fatalf("Unexpected parse error for %s: %v", encoded, err)
+			}
+			if !reflect.DeepEqual(parsed, decoded.Value) {
+				t.Errorf("Expected %s, got %s (from %s)", decoded.Value.ReprString(), parsed.ReprString(), encoded)
+			}
+
+			// The same JSON decodes into the concrete type and into a ClarityValue
+			typed := reflect.New(reflect.TypeOf(decoded.Value))
+			if err := json.Unmarshal(encoded, typed.Interface()); err != nil {
+				t.Fatalf("Unexpected typed unmarshal error: %v", err)
+			}
+			if !reflect.DeepEqual(typed.Elem().Interface(), decoded.Value) {
+				t.Errorf("Expected %s, got %v", decoded.Value.ReprString(), typed.Elem().Interface())
+			}
+
+			var wrapped clarity_value.ClarityValue
+			if err := json.Unmarshal(encoded, &wrapped); err != nil {
+				t.Fatalf("Unexpected ClarityValue unmarshal error: %v", err)
+			}
+			if !reflect.DeepEqual(wrapped.Value, decoded.Value) {
+				t.Errorf("Expected %s, got %s", decoded.Value.ReprString(), wrapped.Value.ReprString())
+			}
+		})
+	}
+}
+
+func TestClarityJSONEncoding(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"-5", `{"type":"int","value":"-5"}`},
+		{"u340282366920938463463374607431768211455", `{"type":"uint","value":"340282366920938463463374607431768211455"}`},
+		{"true", `{"type":"bool","value":true}`},
+		{"0x0102", `{"type":"(buff 2)","value":"0x0102"}`},
+		{`"hi"`, `{"type":"(string-ascii 2)","value":"hi"}`},
+		{`u"h\u{4E16}"`, `{"type":"(string-utf8 4)","value":"h世"}`},
+		{"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", `{"type":"principal","value":"SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"}`},
+		{"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token", `{"type":"principal","value":"SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token"}`},
+		{"none", `{"type":"(optional UnknownType)","value":null}`},
+		{"(some u1)", `{"type":"(optional uint)","value":{"type":"uint","value":"1"}}`},
+		{"(ok true)", `{"type":"(response bool UnknownType)","value":{"type":"bool","value":true},"success":true}`},
+		{"(err u1)", `{"type":"(response UnknownType uint)","value":{"type":"uint","value":"1"},"success":false}`},
+		{"(list 1 2)", `{"type":"(list 2 int)","value":[{"type":"int","value":"1"},{"type":"int","value":"2"}]}`},
+		{"{b: u1, a: false}", `{"type":"(tuple (a bool) (b uint))","value":{"a":{"type":"bool","value":false},"b":{"type":"uint","value":"1"}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %v", err)
+			}
+			if string(encoded) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, encoded)
+			}
+		})
+	}
+}
+
+func TestClarityJSONCvToJSONFixtures(t *testing.T) {
+	// Output of stacks.js cvToJSON, whose string-utf8 lengths count UTF-8 bytes
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{`u"h\u{E9}llo \u{1F30D}"`, `{"type":"(string-utf8 11)","value":"héllo 🌍"}`},
+		{
+			`{memo: u"\u{FC}", tags: (list u"\u{4E16}\u{754C}" u"a")}`,
+			`{"type":"(tuple (memo (string-utf8 2)) (tags (list 2 (string-utf8 6))))","value":{"memo":{"type":"(string-utf8 2)","value":"ü"},"tags":{"type":"(list 2 (string-utf8 6))","value":[{"type":"(string-utf8 6)","value":"世界"},{"type":"(string-utf8 1)","value":"a"}]}}}`,
+		},
+		{`(ok (some u"\u{E9}"))`, `{"type":"(response (optional (string-utf8 2)) UnknownType)","value":{"type":"(optional (string-utf8 2))","value":{"type":"(string-utf8 2)","value":"é"}},"success":true}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %v", err)
+			}
+			if string(encoded) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, encoded)
+			}
+		})
+	}
+}
+
+func TestClarityJSONTypeMatchesTypeSignature(t *testing.T) {
+	// Without string-utf8 values the JSON type is the decoder's type signature
+	values := []string{
+		`{a: (list 0x01 0x0203), b: (some "abc")}`,
+		`(list)`,
+		`(err (list u1 u2))`,
+		`(ok none)`,
+	}
+
+	for _, input := range values {
+		t.Run(input, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(input)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %v", err)
+			}
+			var out struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(encoded, &out); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %v", err)
+			}
+			if out.Type != value.TypeSignature() {
+				t.Errorf("Expected type %s, got %s", value.TypeSignature(), out.Type)
+			}
+		})
+	}
+}
+
+func TestParseClarityJSONCompatibility(t *testing.T) {
+	// Shapes written by stacks.js cvToJSON that differ from our own output
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`{"type":"(optional none)","value":null}`, "none"},
+		{`{"type":"uint","value":1}`, "u1"},
+		{`{"type":"(string-utf8 4)","value":"h世"}`, `u"h\u{e4b896}"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityJSON([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value.ReprString() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, value.ReprString())
+			}
+		})
+	}
+}
+
+func TestParseClarityJSONErrors(t *testing.T) {
+	invalidInputs := []string{
+		`{"type":"int"}`,
+		`{"type":"int","value":"1.5"}`,
+		`{"type":"uint","value":"-1"}`,
+		`{"type":"(buff 1)","value":"0xzz"}`,
+		`{"type":"(string-ascii 1)","value":"世"}`,
+		`{"type":"principal","value":"SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ8"}`,
+		`{"type":"(response bool bool)","value":{"type":"bool","value":true}}`,
+		`{"type":"(tuple (1a bool))","value":{"1a":{"type":"bool","value":true}}}`,
+		`{"type":"float","value":"1"}`,
+		`{"type":"(list 1 int)","value":{}}`,
+		`[]`,
+	}
+
+	for _, input := range invalidInputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := clarity_value.ParseClarityJSON([]byte(input)); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+
+	// Typed unmarshalling rejects other variants
+	var u clarity_value.UIntValue
+	if err := json.Unmarshal([]byte(`{"type":"int","value":"1"}`), &u); err == nil {
+		t.Errorf("Expected an error unmarshalling int into UIntValue")
+	}
+}
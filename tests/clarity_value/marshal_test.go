@@ -0,0 +1,272 @@
+package clarity_value_test
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+type marshalTestListing struct {
+	ID      uint64   `clarity:"id"`
+	Owner   string   `clarity:"owner,principal"`
+	Name    string   `clarity:"name"`
+	Title   string   `clarity:"title,string-utf8"`
+	Price   *big.Int `clarity:"price,uint"`
+	Expiry  *uint64  `clarity:"expiry"`
+	Hash    [4]byte  `clarity:"hash"`
+	Memo    []byte   `clarity:"memo"`
+	Tags    []string `clarity:"tags"`
+	Active  bool     `clarity:"active"`
+	Delta   int32    `clarity:"delta"`
+	Ignored string   `clarity:"-"`
+}
+
+func TestMarshalUnmarshalStruct(t *testing.T) {
+	expiry := uint64(100)
+	listing := marshalTestListing{
+		ID:      1,
+		Owner:   "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.market",
+		Name:    "hello",
+		Title:   "h世",
+		Price:   big.NewInt(5000),
+		Expiry:  &expiry,
+		Hash:    [4]byte{1, 2, 3, 4},
+		Memo:    []byte{0xff},
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Delta:   -7,
+		Ignored: "skip me",
+	}
+
+	value, err := clarity_value.Marshal(listing)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+
+	expected := `(tuple (active true) (delta -7) (expiry (some u100)) (hash 0x01020304) (id u1) (memo 0xff) ` +
+		`(name "hello") (owner 'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.market) (price u5000) ` +
+		`(tags (list "a" "b")) (title u"h\u{e4b896}"))`
+	if value.ReprString() != expected {
+		t.Errorf("Expected %s, got %s", expected, value.ReprString())
+	}
+
+	var decoded marshalTestListing
+	if err := clarity_value.Unmarshal(value, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	listing.Ignored = ""
+	if !reflect.DeepEqual(decoded, listing) {
+		t.Errorf("Expected %+v, got %+v", listing, decoded)
+	}
+}
+
+func TestMarshalOptionalNone(t *testing.T) {
+	type holder struct {
+		Value *uint64 `clarity:"value"`
+	}
+	value, err := clarity_value.Marshal(holder{})
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+	if value.ReprString() != "(tuple (value none))" {
+		t.Errorf("Expected (tuple (value none)), got %s", value.ReprString())
+	}
+
+	decoded := holder{Value: new(uint64)}
+	if err := clarity_value.Unmarshal(value, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	if decoded.Value != nil {
+		t.Errorf("Expected nil, got %d", *decoded.Value)
+	}
+}
+
+func TestMarshalResponseOptions(t *testing.T) {
+	type result struct {
+		Balance uint64 `clarity:"balance,ok"`
+	}
+	type failure struct {
+		Code uint64 `clarity:"code,err"`
+	}
+
+	value, err := clarity_value.Marshal(result{Balance: 10})
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+	if value.ReprString() != "(tuple (balance (ok u10)))" {
+		t.Errorf("Expected (tuple (balance (ok u10))), got %s", value.ReprString())
+	}
+
+	var decoded result
+	if err := clarity_value.Unmarshal(value, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	if decoded.Balance != 10 {
+		t.Errorf("Expected 10, got %d", decoded.Balance)
+	}
+
+	// An ok response cannot be unwrapped as err
+	var decodedFailure failure
+	errValue, _ := clarity_value.ParseClarityLiteral("(tuple (code (ok u1)))")
+	err = clarity_value.Unmarshal(errValue, &decodedFailure)
+	var typeErr *clarity_value.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected UnmarshalTypeError, got %v", err)
+	}
+	if typeErr.Path != "value.code" {
+		t.Errorf("Expected path value.code, got %s", typeErr.Path)
+	}
+}
+
+func TestMarshalPassthrough(t *testing.T) {
+	type holder struct {
+		Raw   clarity_value.Value        `clarity:"raw"`
+		Typed clarity_value.UIntValue    `clarity:"typed"`
+		Wrap  clarity_value.ClarityValue `clarity:"wrap"`
+		Any   interface{}                `clarity:"any"`
+		Map   map[string]int64           `clarity:"map"`
+		Big   clarity_value.Int128       `clarity:"big"`
+	}
+	input := holder{
+		Raw:   clarity_value.BoolValue(true),
+		Typed: clarity_value.NewUIntValue(3),
+		Wrap:  clarity_value.NewClarityValue(clarity_value.OptionalNoneValue{}),
+		Any:   clarity_value.NewIntValue(-1),
+		Map:   map[string]int64{"x": 1},
+		Big:   clarity_value.Int128{Hi: -1, Lo: 0},
+	}
+
+	value, err := clarity_value.Marshal(input)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+	expected := "(tuple (any -1) (big -18446744073709551616) (map (tuple (x 1))) (raw true) (typed u3) (wrap none))"
+	if value.ReprString() != expected {
+		t.Errorf("Expected %s, got %s", expected, value.ReprString())
+	}
+
+	var decoded holder
+	if err := clarity_value.Unmarshal(value, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, input) {
+		t.Errorf("Expected %+v, got %+v", input, decoded)
+	}
+}
+
+type marshalTestCustom struct {
+	n uint64
+}
+
+func (c *marshalTestCustom) MarshalClarity() (clarity_value.Value, error) {
+	return clarity_value.NewUIntValue(c.n * 2), nil
+}
+
+func (c *marshalTestCustom) UnmarshalClarity(v clarity_value.Value) error {
+	u, ok := v.(clarity_value.UIntValue)
+	if !ok {
+		return errors.New("expected uint")
+	}
+	c.n = u.Lo / 2
+	return nil
+}
+
+func TestMarshalerInterfaces(t *testing.T) {
+	value, err := clarity_value.Marshal(marshalTestCustom{n: 21})
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+	if value.ReprString() != "u42" {
+		t.Errorf("Expected u42, got %s", value.ReprString())
+	}
+
+	var decoded marshalTestCustom
+	if err := clarity_value.Unmarshal(value, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+	if decoded.n != 21 {
+		t.Errorf("Expected 21, got %d", decoded.n)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type badTag struct {
+		A string `clarity:"a,bogus"`
+	}
+	type badName struct {
+		A string `clarity:"1a"`
+	}
+	type duplicate struct {
+		A string `clarity:"a"`
+		B string `clarity:"a"`
+	}
+	type badPrincipal struct {
+		A string `clarity:"a,principal"`
+	}
+
+	invalidInputs := map[string]interface{}{
+		"Nil":            nil,
+		"Unknown option": badTag{},
+		"Invalid name":   badName{},
+		"Duplicate name": duplicate{},
+		"Bad principal":  badPrincipal{A: "not-a-principal"},
+		"Non-ASCII":      "世",
+		"Float":          1.5,
+		"Big overflow":   new(big.Int).Lsh(big.NewInt(1), 127),
+		"Map key":        map[int]int{1: 1},
+	}
+
+	for name, input := range invalidInputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := clarity_value.Marshal(input); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	type holder struct {
+		Items []uint8 `clarity:"items"`
+		Small int8    `clarity:"small"`
+		Hash  [2]byte `clarity:"hash"`
+	}
+
+	testCases := []struct {
+		name  string
+		value string
+		path  string
+	}{
+		{"Wrong type", "u1", "value"},
+		{"Missing field", "{items: 0x01, small: 1}", "value"},
+		{"Overflow", "{items: 0x01, small: 128, hash: 0x0102}", "value.small"},
+		{"Buffer into byte slice expects buffer", "{items: (list u1), small: 1, hash: 0x0102}", "value.items"},
+		{"Array length", "{items: 0x01, small: 1, hash: 0x010203}", "value.hash"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			var decoded holder
+			err = clarity_value.Unmarshal(value, &decoded)
+			var typeErr *clarity_value.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("Expected UnmarshalTypeError, got %v", err)
+			}
+			if typeErr.Path != tc.path {
+				t.Errorf("Expected path %s, got %s (%v)", tc.path, typeErr.Path, err)
+			}
+		})
+	}
+
+	var notPointer holder
+	if err := clarity_value.Unmarshal(clarity_value.BoolValue(true), notPointer); err == nil {
+		t.Errorf("Expected an error for a non-pointer destination")
+	}
+}
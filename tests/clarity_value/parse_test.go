@@ -0,0 +1,124 @@
+package clarity_value_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestParseClarityLiteral(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string // hex of the serialized value
+	}{
+		{"Int", "10", "000000000000000000000000000000000a"},
+		{"Negative int", "-10", "00fffffffffffffffffffffffffffffff6"},
+		{"Min int", "-170141183460469231731687303715884105728", "0080000000000000000000000000000000"},
+		{"UInt", "u15", "010000000000000000000000000000000f"},
+		{"Max uint", "u340282366920938463463374607431768211455", "01ffffffffffffffffffffffffffffffff"},
+		{"Buffer", "0x010203", "0200000003010203"},
+		{"Empty buffer", "0x", "0200000000"},
+		{"True", "true", "03"},
+		{"False", "false", "04"},
+		{"Standard principal", "'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7", "0516a46ff88886c2ef9762d970b4d2c63678835bd39d"},
+		{"Contract principal", "'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.my-contract", "0616a46ff88886c2ef9762d970b4d2c63678835bd39d0b6d792d636f6e7472616374"},
+		{"None", "none", "09"},
+		{"Some", "(some true)", "0a03"},
+		{"Ok", "(ok true)", "0703"},
+		{"Err", "(err u1)", "080100000000000000000000000000000001"},
+		{"List", "(list true (some true))", "0b00000002030a03"},
+		{"Empty list", "(list)", "0b00000000"},
+		{"Tuple", "(tuple (a u1) (b (list false)))", "0c000000020161010000000000000000000000000000000101620b0000000104"},
+		{"Map syntax", "{a: u1, b: (list false)}", "0c000000020161010000000000000000000000000000000101620b0000000104"},
+		{"Map syntax without commas", "{ b: (list false) a: u1 }", "0c000000020161010000000000000000000000000000000101620b0000000104"},
+		{"ASCII string", `"hello"`, "0d0000000568656c6c6f"},
+		{"ASCII escapes", `"a\n\t\"\\\x01"`, "0d00000006610a09225c01"},
+		{"UTF8 string", `u"hello"`, "0e0000000568656c6c6f"},
+		{"UTF8 code point escape", `u"h\u{4E16}"`, "0e0000000468e4b896"},
+		{"UTF8 byte escape", `u"h\u{e4b896}"`, "0e0000000468e4b896"},
+		{"UTF8 raw", `u"h世"`, "0e0000000468e4b896"},
+		{"Whitespace", "  ( some\n\t( ok  u1 ) )  ", "0a070100000000000000000000000000000001"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			serialized, err := clarity_value.SerializeClarityValue(value)
+			if err != nil {
+				t.Fatalf("Unexpected serialize error: %v", err)
+			}
+			if hex.EncodeToString(serialized) != tc.expected {
+				t.Errorf("Expected %s, got %x", tc.expected, serialized)
+			}
+		})
+	}
+}
+
+func TestParseClarityLiteralErrors(t *testing.T) {
+	invalidInputs := []string{
+		"",
+		"(",
+		"(list true",
+		"(tuple (a))",
+		"(tuple (1a u1))",
+		"{a: u1, a: u2}",
+		"(foo u1)",
+		"0x0",
+		"0xzz",
+		"u-1",
+		"u340282366920938463463374607431768211456",
+		"170141183460469231731687303715884105728",
+		"1.5",
+		"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ8",
+		"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.1bad",
+		`"unterminated`,
+		`"bad \q escape"`,
+		`"no \u{41} in ascii"`,
+		`"non-ascii 世"`,
+		`u"\u{110000}"`,
+		"true false",
+		"(some (some (some (some (some (some (some (some (some (some (some (some (some (some (some (some u1))))))))))))))))",
+	}
+
+	for _, input := range invalidInputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := clarity_value.ParseClarityLiteral(input); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestParseClarityLiteralReprRoundTrip(t *testing.T) {
+	for _, tc := range serializeTestVectors {
+		t.Run(tc.name, func(t *testing.T) {
+			inputBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			decoded, err := clarity_value.DecodeClarityValue(bytes.NewReader(inputBytes), false)
+			if err != nil {
+				t.Fatalf("Unexpected decode error: %v", err)
+			}
+
+			repr := decoded.Value.ReprString()
+			parsed, err := clarity_value.ParseClarityLiteral(repr)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", repr, err)
+			}
+
+			if !reflect.DeepEqual(parsed, decoded.Value) {
+				t.Errorf("Parsed value %s does not equal decoded value %s", parsed.ReprString(), repr)
+			}
+		})
+	}
+}
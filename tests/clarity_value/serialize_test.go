@@ -0,0 +1,204 @@
+package clarity_value_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/post_condition"
+)
+
+var serializeTestVectors = []struct {
+	name  string
+	input string
+}{
+	{"Int", "000000000000000000000000000000000a"},
+	{"Negative int", "00fffffffffffffffffffffffffffffff6"},
+	{"UInt", "010000000000000000000000000000000f"},
+	{"UInt above 2^64", "0100000000000000010000000000000000"},
+	{"Buffer", "0200000003010203"},
+	{"Empty buffer", "0200000000"},
+	{"Bool true", "03"},
+	{"Bool false", "04"},
+	{"Standard principal", "05162b3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d"},
+	{"Contract principal", "06162b3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d0474657374"},
+	{"Response ok", "0703"},
+	{"Response err", "0804"},
+	{"Optional none", "09"},
+	{"Optional some", "0a03"},
+	{"List", "0b00000002030a03"},
+	{"Empty list", "0b00000000"},
+	{"Tuple", "0c000000020161010000000000000000000000000000000101620b0000000104"},
+	{"String ASCII", "0d0000000568656c6c6f"},
+	{"String UTF8", "0e0000000768e4b896e7958c"},
+	{"Nested", "080a0b000000010c00000001026964010000000000000000000000000000002a"},
+}
+
+func TestSerializeClarityValueRoundTrip(t *testing.T) {
+	for _, tc := range serializeTestVectors {
+		t.Run(tc.name, func(t *testing.T) {
+			inputBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			decoded, err := clarity_value.DecodeClarityValue(bytes.NewReader(inputBytes), false)
+			if err != nil {
+				t.Fatalf("Unexpected decode error: %v", err)
+			}
+
+			serialized, err := clarity_value.SerializeClarityValue(decoded.Value)
+			if err != nil {
+				t.Fatalf("Unexpected serialize error: %v", err)
+			}
+
+			if !bytes.Equal(serialized, inputBytes) {
+				t.Errorf("Expected serialized bytes %s, got %x", tc.input, serialized)
+			}
+
+			var buf bytes.Buffer
+			if err := clarity_value.WriteClarityValue(&buf, decoded.Value); err != nil {
+				t.Fatalf("Unexpected write error: %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), inputBytes) {
+				t.Errorf("Expected written bytes %s, got %x", tc.input, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestSerializeTupleCanonicalOrder(t *testing.T) {
+	tuple := clarity_value.TupleValue{
+		clarity_value.MustClarityName("b"): clarity_value.NewClarityValue(clarity_value.BoolValue(false)),
+		clarity_value.MustClarityName("a"): clarity_value.NewClarityValue(clarity_value.NewUIntValue(1)),
+		clarity_value.MustClarityName("B"): clarity_value.NewClarityValue(clarity_value.BoolValue(true)),
+	}
+
+	serialized, err := clarity_value.SerializeClarityValue(tuple)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "0c0000000301420301610100000000000000000000000000000001016204"
+	if hex.EncodeToString(serialized) != expected {
+		t.Errorf("Expected %s, got %x", expected, serialized)
+	}
+}
+
+func TestSerializeClarityValueErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value clarity_value.Value
+	}{
+		{
+			name:  "Nil value",
+			value: nil,
+		},
+		{
+			name: "Nil nested value",
+			value: clarity_value.OptionalSomeValue{
+				Value: clarity_value.ClarityValue{},
+			},
+		},
+		{
+			name: "Name too long",
+			value: clarity_value.TupleValue{
+				clarity_value.ClarityName(bytes.Repeat([]byte("a"), 129)): clarity_value.NewClarityValue(clarity_value.BoolValue(true)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := clarity_value.SerializeClarityValue(tc.value); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestSerializeClarityValueSamples(t *testing.T) {
+	sampleFile, err := os.Open("../gz/sampled-post-conditions.txt.gz")
+	if err != nil {
+		t.Fatalf("Failed to open sample file: %v", err)
+	}
+	defer sampleFile.Close()
+
+	gzipReader, err := gzip.NewReader(sampleFile)
+	if err != nil {
+		t.Fatalf("Failed to create gzip reader: %v", err)
+	}
+	defer gzipReader.Close()
+
+	// Every non-fungible post condition carries a serialized asset value
+	checked := 0
+	scanner := bufio.NewScanner(gzipReader)
+	for scanner.Scan() {
+		inputBytes, err := hex.DecodeString(scanner.Text())
+		if err != nil {
+			t.Fatalf("Failed to decode hex string: %v", err)
+		}
+
+		resp, err := post_condition.DecodeTxPostConditions(inputBytes)
+		if err != nil {
+			t.Fatalf("Failed to decode post conditions: %v", err)
+		}
+
+		for _, pc := range resp.PostConditions {
+			if pc.Type != post_condition.AssetInfoNonfungible {
+				continue
+			}
+			serialized, err := clarity_value.SerializeClarityValue(pc.AssetValue.Value)
+			if err != nil {
+				t.Fatalf("Failed to serialize asset value: %v", err)
+			}
+			if !bytes.Equal(serialized, pc.AssetValue.SerializedBytes) {
+				t.Fatalf("Expected serialized bytes %x, got %x", pc.AssetValue.SerializedBytes, serialized)
+			}
+			checked++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Error reading sample file: %v", err)
+	}
+	if checked == 0 {
+		t.Fatalf("Expected sample file to contain non-fungible post conditions")
+	}
+}
+
+func FuzzSerializeClarityValue(f *testing.F) {
+	for _, tc := range serializeTestVectors {
+		inputBytes, _ := hex.DecodeString(tc.input)
+		f.Add(inputBytes)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decoded, err := clarity_value.DecodeClarityValue(bytes.NewReader(data), false)
+		if err != nil {
+			return
+		}
+
+		serialized, err := clarity_value.SerializeClarityValue(decoded.Value)
+		if err != nil {
+			t.Fatalf("Failed to serialize decoded value: %v", err)
+		}
+
+		// Serialization is canonical, so a second pass must be stable
+		redecoded, err := clarity_value.DecodeClarityValue(bytes.NewReader(serialized), false)
+		if err != nil {
+			t.Fatalf("Failed to decode serialized value %x: %v", serialized, err)
+		}
+		reserialized, err := clarity_value.SerializeClarityValue(redecoded.Value)
+		if err != nil {
+			t.Fatalf("Failed to serialize redecoded value: %v", err)
+		}
+		if !bytes.Equal(serialized, reserialized) {
+			t.Fatalf("Serialization not stable: %x != %x", serialized, reserialized)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package clarity_value_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestDecoderStream(t *testing.T) {
+	// Concatenate every test vector into a single stream
+	var stream []byte
+	var offsets []int64
+	for _, tc := range serializeTestVectors {
+		inputBytes, err := hex.DecodeString(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to decode hex input: %v", err)
+		}
+		offsets = append(offsets, int64(len(stream)))
+		stream = append(stream, inputBytes...)
+	}
+
+	// OneByteReader hides io.ByteReader and returns short reads
+	decoder := clarity_value.NewDecoder(iotest.OneByteReader(bytes.NewReader(stream)))
+	seekable := bytes.NewReader(stream)
+
+	for i, tc := range serializeTestVectors {
+		if decoder.Offset() != offsets[i] {
+			t.Errorf("%s: expected offset %d, got %d", tc.name, offsets[i], decoder.Offset())
+		}
+
+		value, err := decoder.Decode(true)
+		if err != nil {
+			t.Fatalf("%s: unexpected decode error: %v", tc.name, err)
+		}
+		// Decode from the same offset so that value offsets agree
+		expected, err := clarity_value.DecodeClarityValue(clarity_value.NewOffsetReader(seekable, offsets[i]), true)
+		if err != nil {
+			t.Fatalf("%s: unexpected decode error: %v", tc.name, err)
+		}
+
+		if !reflect.DeepEqual(value, expected) {
+			t.Errorf("%s: streamed value differs from buffered value", tc.name)
+		}
+		if hex.EncodeToString(value.SerializedBytes) != tc.input {
+			t.Errorf("%s: expected serialized bytes %s, got %x", tc.name, tc.input, value.SerializedBytes)
+		}
+		if value.Offset != offsets[i] {
+			t.Errorf("%s: expected value offset %d, got %d", tc.name, offsets[i], value.Offset)
+		}
+	}
+
+	if decoder.Offset() != int64(len(stream)) {
+		t.Errorf("Expected final offset %d, got %d", len(stream), decoder.Offset())
+	}
+	if _, err := decoder.Decode(false); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF at end of stream, got %v", err)
+	}
+}
+
+func TestDecoderNestedOffsets(t *testing.T) {
+	// true, then (list (some u1) (ok {a: 0x01})) starting at offset 1
+	stream, _ := hex.DecodeString("03" +
+		"0b00000002" +
+		"0a" + "0100000000000000000000000000000001" +
+		"07" + "0c00000001" + "0161" + "020000000101")
+	decoder := clarity_value.NewDecoder(bytes.NewReader(stream))
+	if _, err := decoder.Decode(true); err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	value, err := decoder.Decode(true)
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+
+	list := value.Value.(clarity_value.ListValue)
+	some := list[0].Value.(clarity_value.OptionalSomeValue)
+	ok := list[1].Value.(clarity_value.ResponseOkValue)
+	field := ok.Value.Value.(clarity_value.TupleValue)["a"]
+
+	offsets := []struct {
+		name     string
+		value    clarity_value.ClarityValue
+		expected int64
+	}{
+		{"list", value, 1},
+		{"some", list[0], 6},
+		{"some value", some.Value, 7},
+		{"ok", list[1], 24},
+		{"tuple", ok.Value, 25},
+		{"tuple field", field, 32},
+	}
+	for _, tc := range offsets {
+		if tc.value.Offset != tc.expected {
+			t.Errorf("Expected %s at offset %d, got %d", tc.name, tc.expected, tc.value.Offset)
+		}
+		// The value's serialized bytes start at its offset in the stream
+		if !bytes.HasPrefix(stream[tc.expected:], tc.value.SerializedBytes) {
+			t.Errorf("Serialized bytes of %s do not start at offset %d", tc.name, tc.expected)
+		}
+	}
+
+	// Offsets count from the offset of an OffsetReader
+	value, err = clarity_value.DecodeClarityValue(clarity_value.NewOffsetReader(bytes.NewReader(stream[1:]), 100), true)
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	if value.Offset != 100 || value.Value.(clarity_value.ListValue)[1].Offset != 123 {
+		t.Errorf("Expected offsets 100 and 123, got %d and %d", value.Offset, value.Value.(clarity_value.ListValue)[1].Offset)
+	}
+}
+
+func TestDecoderNestedSerializedBytes(t *testing.T) {
+	// (err (some (list (tuple (id u42)))))
+	input := "080a0b000000010c00000001026964010000000000000000000000000000002a"
+	inputBytes, _ := hex.DecodeString(input)
+
+	value, err := clarity_value.NewDecoder(iotest.HalfReader(bytes.NewReader(inputBytes))).Decode(true)
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+
+	some := value.Value.(clarity_value.ResponseErrValue).Value
+	list := some.Value.(clarity_value.OptionalSomeValue).Value
+	tuple := list.Value.(clarity_value.ListValue)[0]
+	id := tuple.Value.(clarity_value.TupleValue)["id"]
+
+	expected := []struct {
+		value clarity_value.ClarityValue
+		hex   string
+	}{
+		{value, input},
+		{some, input[2:]},
+		{list, input[4:]},
+		{tuple, input[14:]},
+		{id, input[30:]},
+	}
+	for _, e := range expected {
+		if hex.EncodeToString(e.value.SerializedBytes) != e.hex {
+			t.Errorf("Expected serialized bytes %s, got %x", e.hex, e.value.SerializedBytes)
+		}
+	}
+}
+
+func TestDecoderDoesNotOverRead(t *testing.T) {
+	inputBytes, _ := hex.DecodeString("0a03" + "ff")
+	reader := bytes.NewReader(inputBytes)
+
+	if _, err := clarity_value.NewDecoder(reader).Decode(false); err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	if reader.Len() != 1 {
+		t.Errorf("Expected 1 unread byte, got %d", reader.Len())
+	}
+}
+
+func TestDecoderWithType(t *testing.T) {
+	inputBytes, _ := hex.DecodeString("0c000000020161010000000000000000000000000000000101620b0000000104")
+	typ, err := clarity_value.ParseTypeSignature("(tuple (a uint))")
+	if err != nil {
+		t.Fatalf("Failed to parse type: %v", err)
+	}
+
+	decoder := clarity_value.NewDecoder(iotest.OneByteReader(bytes.NewReader(inputBytes)))
+	value, err := decoder.DecodeWithType(typ, clarity_value.DecodeOptions{WithBytes: true, Sanitize: true})
+	if err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	if value.Value.ReprString() != "(tuple (a u1))" {
+		t.Errorf("Expected (tuple (a u1)), got %s", value.Value.ReprString())
+	}
+	if decoder.Offset() != int64(len(inputBytes)) {
+		t.Errorf("Expected offset %d, got %d", len(inputBytes), decoder.Offset())
+	}
+}
+
+func TestDecoderSerializedBytesAliasing(t *testing.T) {
+	// (some (list u1 u2))
+	inputBytes, _ := hex.DecodeString("0a0b0000000201000000000000000000000000000000010100000000000000000000000000000002")
+
+	decode := func(copyBytes bool) (parent, child, item clarity_value.ClarityValue) {
+		decoder := clarity_value.NewDecoder(bytes.NewReader(inputBytes))
+		typ, _ := clarity_value.ParseTypeSignature("(optional (list 2 uint))")
+		value, err := decoder.DecodeWithType(typ, clarity_value.DecodeOptions{WithBytes: true, CopyBytes: copyBytes})
+		if err != nil {
+			t.Fatalf("Unexpected decode error: %v", err)
+		}
+		child = value.Value.(clarity_value.OptionalSomeValue).Value
+		return value, child, child.Value.(clarity_value.ListValue)[0]
+	}
+
+	parent, child, item := decode(false)
+	if &child.SerializedBytes[0] != &parent.SerializedBytes[1] || &item.SerializedBytes[0] != &parent.SerializedBytes[6] {
+		t.Errorf("Expected nested serialized bytes to alias the parent buffer")
+	}
+	// Appending to a nested slice must not overwrite the bytes that follow it
+	_ = append(item.SerializedBytes, 0xff)
+	if !bytes.Equal(parent.SerializedBytes, inputBytes) {
+		t.Errorf("Appending to a nested slice modified the parent bytes")
+	}
+
+	parent, child, item = decode(true)
+	if &child.SerializedBytes[0] == &parent.SerializedBytes[1] || &item.SerializedBytes[0] == &parent.SerializedBytes[6] {
+		t.Errorf("Expected CopyBytes to give nested values their own buffers")
+	}
+	if !bytes.Equal(item.SerializedBytes, inputBytes[6:23]) {
+		t.Errorf("Expected %x, got %x", inputBytes[6:23], item.SerializedBytes)
+	}
+}
+
+// nestedBenchmarkInput serializes three levels of 8-element lists of tuples,
+// ending in 512 (tuple (id uint) (owner (buff 20))) leaves
+func nestedBenchmarkInput(b *testing.B) []byte {
+	var value clarity_value.Value = clarity_value.TupleValue{
+		"id":    clarity_value.NewClarityValue(clarity_value.NewUIntValue(1)),
+		"owner": clarity_value.NewClarityValue(clarity_value.BufferValue(make([]byte, 20))),
+	}
+	for depth := 0; depth < 3; depth++ {
+		list := make(clarity_value.ListValue, 8)
+		for i := range list {
+			list[i] = clarity_value.NewClarityValue(value)
+		}
+		value = clarity_value.TupleValue{"items": clarity_value.NewClarityValue(list)}
+	}
+	data, err := clarity_value.SerializeClarityValue(value)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+// Before nested SerializedBytes aliased the top-level buffer, WithBytes cost
+// 8413 allocs/op and 644 KB/op on this input; it now costs about 5070 allocs/op
+// and 530 KB/op, in line with WithoutBytes.
+func BenchmarkDecodeNested(b *testing.B) {
+	data := nestedBenchmarkInput(b)
+
+	benchmarks := []struct {
+		name string
+		opts clarity_value.DecodeOptions
+	}{
+		{"WithoutBytes", clarity_value.DecodeOptions{}},
+		{"WithBytes", clarity_value.DecodeOptions{WithBytes: true}},
+		{"CopyBytes", clarity_value.DecodeOptions{WithBytes: true, CopyBytes: true}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := clarity_value.DecodeClarityValueWithOptions(bytes.NewReader(data), bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
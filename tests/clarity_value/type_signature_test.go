@@ -0,0 +1,254 @@
+package clarity_value_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+)
+
+func TestParseTypeSignature(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected clarity_value.TypeSignature
+		repr     string
+	}{
+		{"int", clarity_value.IntType{}, "int"},
+		{"uint", clarity_value.UIntType{}, "uint"},
+		{"bool", clarity_value.BoolType{}, "bool"},
+		{"principal", clarity_value.PrincipalType{}, "principal"},
+		{"(buff 32)", clarity_value.BufferType{Length: 32}, "(buff 32)"},
+		{"(string-ascii 10)", clarity_value.StringASCIIType{Length: 10}, "(string-ascii 10)"},
+		{"(string-utf8 10)", clarity_value.StringUTF8Type{Length: 10}, "(string-utf8 10)"},
+		{"(optional uint)", clarity_value.OptionalType{Inner: clarity_value.UIntType{}}, "(optional uint)"},
+		{
+			"(response bool uint)",
+			clarity_value.ResponseType{Ok: clarity_value.BoolType{}, Err: clarity_value.UIntType{}},
+			"(response bool uint)",
+		},
+		{
+			"(list 10 (tuple (id uint) (owner principal)))",
+			clarity_value.ListType{
+				MaxLength: 10,
+				Element: clarity_value.TupleType{
+					"id":    clarity_value.UIntType{},
+					"owner": clarity_value.PrincipalType{},
+				},
+			},
+			"(list 10 (tuple (id uint) (owner principal)))",
+		},
+		{
+			"{ owner: principal, id: uint }",
+			clarity_value.TupleType{
+				"id":    clarity_value.UIntType{},
+				"owner": clarity_value.PrincipalType{},
+			},
+			"(tuple (id uint) (owner principal))",
+		},
+		{"(optional UnknownType)", clarity_value.OptionalType{Inner: clarity_value.NoType{}}, "(optional UnknownType)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			parsed, err := clarity_value.ParseTypeSignature(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, tc.expected) {
+				t.Errorf("Expected %#v, got %#v", tc.expected, parsed)
+			}
+			if parsed.String() != tc.repr {
+				t.Errorf("Expected String() %s, got %s", tc.repr, parsed.String())
+			}
+		})
+	}
+}
+
+func TestParseTypeSignatureErrors(t *testing.T) {
+	invalidInputs := []string{
+		"",
+		"integer",
+		"(buff)",
+		"(buff -1)",
+		"(buff 99999999999)",
+		"(list 10)",
+		"(optional)",
+		"(response uint)",
+		"(tuple (id))",
+		"(tuple (id uint) (id int))",
+		"(list 1 uint",
+		"uint uint",
+	}
+
+	for _, input := range invalidInputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := clarity_value.ParseTypeSignature(input); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestAdmits(t *testing.T) {
+	testCases := []struct {
+		name      string
+		typ       string
+		value     string
+		errorPath string // empty when the value is admitted
+	}{
+		{"Int", "int", "-1", ""},
+		{"Int mismatch", "int", "u1", "value"},
+		{"Buffer fits", "(buff 2)", "0x0102", ""},
+		{"Buffer too long", "(buff 2)", "0x010203", "value"},
+		{"ASCII fits", "(string-ascii 5)", `"hello"`, ""},
+		{"ASCII too long", "(string-ascii 4)", `"hello"`, "value"},
+		{"UTF8 counts characters", "(string-utf8 2)", `u"\u{4E16}\u{754C}"`, ""},
+		{"UTF8 too long", "(string-utf8 1)", `u"\u{4E16}\u{754C}"`, "value"},
+		{"Principal", "principal", "'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token", ""},
+		{"Optional none", "(optional uint)", "none", ""},
+		{"Optional some mismatch", "(optional uint)", "(some 1)", "value.some"},
+		{"Response ok", "(response bool uint)", "(ok true)", ""},
+		{"Response err mismatch", "(response bool uint)", "(err true)", "value.err"},
+		{"List element mismatch", "(list 3 uint)", "(list u1 u2 3)", "value[2]"},
+		{"List too long", "(list 1 uint)", "(list u1 u2)", "value"},
+		{
+			"Nested tuple",
+			"(list 10 (tuple (id uint) (owner principal)))",
+			"(list (tuple (id u1) (owner 'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7)))",
+			"",
+		},
+		{
+			"Nested tuple mismatch",
+			"(list 10 (tuple (id uint) (owner principal)))",
+			"(list (tuple (id u1) (owner 'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7)) (tuple (id u2) (owner u3)))",
+			"value[1].owner",
+		},
+		{"Tuple missing field", "(tuple (a uint) (b uint))", "(tuple (a u1))", "value"},
+		{"Tuple extra field", "(tuple (a uint))", "(tuple (a u1) (b u1))", "value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, err := clarity_value.ParseTypeSignature(tc.typ)
+			if err != nil {
+				t.Fatalf("Failed to parse type: %v", err)
+			}
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+
+			err = clarity_value.Admits(typ, value)
+			if tc.errorPath == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var admissionErr *clarity_value.AdmissionError
+			if !errors.As(err, &admissionErr) {
+				t.Fatalf("Expected AdmissionError, got %v", err)
+			}
+			if admissionErr.Path != tc.errorPath {
+				t.Errorf("Expected error path %s, got %s (%v)", tc.errorPath, admissionErr.Path, err)
+			}
+		})
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"u1", "uint"},
+		{"0x0102", "(buff 2)"},
+		{"none", "(optional UnknownType)"},
+		{"(list)", "(list 0 UnknownType)"},
+		{"(list (some u1) none)", "(list 2 (optional uint))"},
+		{"(list none (some u1))", "(list 2 (optional uint))"},
+		{"(list (ok u1) (err false))", "(list 2 (response uint bool))"},
+		{"(list 0x01 0x010203)", "(list 2 (buff 3))"},
+		{"(list (list) (list u1 u2))", "(list 2 (list 2 uint))"},
+		{"(list {a: none} {a: (some \"ab\")})", "(list 2 (tuple (a (optional (string-ascii 2)))))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.value)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			typ, err := clarity_value.TypeOf(value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if typ.String() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, typ.String())
+			}
+			if _, ok := typ.(clarity_value.ListType); ok {
+				if err := clarity_value.Admits(typ, value); err != nil {
+					t.Errorf("Inferred type does not admit value: %v", err)
+				}
+			}
+		})
+	}
+
+	mixed := []string{
+		"(list u1 1)",
+		"(list (tuple (a u1)) (tuple (b u1)))",
+		"(list (some u1) (some true))",
+	}
+	for _, input := range mixed {
+		value, err := clarity_value.ParseClarityLiteral(input)
+		if err != nil {
+			t.Fatalf("Failed to parse value: %v", err)
+		}
+		if _, err := clarity_value.TypeOf(value); err == nil {
+			t.Errorf("TypeOf(%s) expected error, got nil", input)
+		}
+	}
+}
+
+func TestTypeSignatureMatchesTypeOf(t *testing.T) {
+	// The string-utf8 length of a TypeOf type counts characters
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`u"\u{4E16}\u{754C}"`, "(string-utf8 2)"},
+		{`u""`, "(string-utf8 0)"},
+		{`{a: u"x", b: (some u"\u{E9}")}`, "(tuple (a (string-utf8 1)) (b (optional (string-utf8 1))))"},
+		{`(list u"ab" u"cd")`, "(list 2 (string-utf8 2))"},
+		{`(ok u"\u{1F30D}")`, "(response (string-utf8 1) UnknownType)"},
+		{`(err 0x0102)`, "(response UnknownType (buff 2))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			value, err := clarity_value.ParseClarityLiteral(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to parse value: %v", err)
+			}
+			inferred, err := clarity_value.TypeOf(value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if inferred.String() != tc.expected {
+				t.Errorf("TypeOf() = %s, want %s", inferred, tc.expected)
+			}
+			parsed, err := clarity_value.ParseTypeSignature(inferred.String())
+			if err != nil {
+				t.Fatalf("Failed to parse type signature %s: %v", inferred, err)
+			}
+			if !reflect.DeepEqual(parsed, inferred) {
+				t.Errorf("ParseTypeSignature(%s) = %s", inferred, parsed)
+			}
+			if err := clarity_value.Admits(parsed, value); err != nil {
+				t.Errorf("Type signature does not admit value: %v", err)
+			}
+		})
+	}
+}
@@ -89,13 +89,13 @@ func TestClarityValueRepresentations(t *testing.T) {
 	}{
 		{
 			"Int",
-			clarity_value.IntValue(123),
+			clarity_value.NewIntValue(123),
 			"123",
 			"int",
 		},
 		{
 			"UInt",
-			clarity_value.UIntValue(123),
+			clarity_value.NewUIntValue(123),
 			"u123",
 			"uint",
 		},
@@ -114,7 +114,7 @@ func TestClarityValueRepresentations(t *testing.T) {
 		{
 			"Buffer",
 			clarity_value.BufferValue([]byte{0x01, 0x02, 0x03}),
-			"010203",
+			"0x010203",
 			"(buff 3)",
 		},
 		{
@@ -146,7 +146,7 @@ func TestClarityValueRepresentations(t *testing.T) {
 func TestNestedClarityValues(t *testing.T) {
 	// Create a simple tuple
 	tuple := clarity_value.TupleValue{
-		clarity_value.MustClarityName("a"): clarity_value.NewClarityValue(clarity_value.IntValue(1)),
+		clarity_value.MustClarityName("a"): clarity_value.NewClarityValue(clarity_value.NewIntValue(1)),
 		clarity_value.MustClarityName("b"): clarity_value.NewClarityValue(clarity_value.BoolValue(true)),
 	}
 
@@ -157,9 +157,9 @@ func TestNestedClarityValues(t *testing.T) {
 
 	// Create a list with some values
 	list := clarity_value.ListValue{
-		clarity_value.NewClarityValue(clarity_value.IntValue(1)),
-		clarity_value.NewClarityValue(clarity_value.IntValue(2)),
-		clarity_value.NewClarityValue(clarity_value.IntValue(3)),
+		clarity_value.NewClarityValue(clarity_value.NewIntValue(1)),
+		clarity_value.NewClarityValue(clarity_value.NewIntValue(2)),
+		clarity_value.NewClarityValue(clarity_value.NewIntValue(3)),
 	}
 
 	expectedListRepr := "(list 1 2 3)"
@@ -169,7 +169,7 @@ func TestNestedClarityValues(t *testing.T) {
 
 	// Test optional some
 	optSome := clarity_value.OptionalSomeValue{
-		Value: clarity_value.NewClarityValue(clarity_value.IntValue(42)),
+		Value: clarity_value.NewClarityValue(clarity_value.NewIntValue(42)),
 	}
 
 	expectedOptRepr := "(some 42)"
@@ -179,7 +179,7 @@ func TestNestedClarityValues(t *testing.T) {
 
 	// Test response ok
 	respOk := clarity_value.ResponseOkValue{
-		Value: clarity_value.NewClarityValue(clarity_value.IntValue(42)),
+		Value: clarity_value.NewClarityValue(clarity_value.NewIntValue(42)),
 	}
 
 	expectedRespOkRepr := "(ok 42)"
@@ -189,7 +189,7 @@ func TestNestedClarityValues(t *testing.T) {
 
 	// Test response err
 	respErr := clarity_value.ResponseErrValue{
-		Value: clarity_value.NewClarityValue(clarity_value.IntValue(42)),
+		Value: clarity_value.NewClarityValue(clarity_value.NewIntValue(42)),
 	}
 
 	expectedRespErrRepr := "(err 42)"
@@ -0,0 +1,109 @@
+package network_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/network"
+)
+
+func TestProfiles(t *testing.T) {
+	testCases := []struct {
+		network   network.Network
+		mainnet   bool
+		chainID   uint32
+		singlesig byte
+		multisig  byte
+		hrp       string
+	}{
+		{network.Mainnet, true, 0x00000001, 22, 20, "bc"},
+		{network.Testnet, false, 0x80000000, 26, 21, "tb"},
+		{network.Devnet, false, 0x80000000, 26, 21, "bcrt"},
+		{network.Mocknet, false, 0x80000000, 26, 21, "bcrt"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.network.String(), func(t *testing.T) {
+			n := tc.network
+			if n.IsMainnet() != tc.mainnet {
+				t.Errorf("IsMainnet() = %v, want %v", n.IsMainnet(), tc.mainnet)
+			}
+			if n.ChainID != tc.chainID {
+				t.Errorf("ChainID = %#08x, want %#08x", n.ChainID, tc.chainID)
+			}
+			if n.AddressVersion(false) != tc.singlesig || n.AddressVersion(true) != tc.multisig {
+				t.Errorf("AddressVersion() = %d/%d, want %d/%d", n.AddressVersion(false), n.AddressVersion(true), tc.singlesig, tc.multisig)
+			}
+			if n.BitcoinHRP != tc.hrp {
+				t.Errorf("BitcoinHRP = %s, want %s", n.BitcoinHRP, tc.hrp)
+			}
+		})
+	}
+
+	if network.Regtest != network.Devnet {
+		t.Errorf("Regtest = %+v, want Devnet", network.Regtest)
+	}
+}
+
+func TestNewSubnet(t *testing.T) {
+	subnet := network.NewSubnet("subnet", 0x55005500, network.Testnet)
+	if subnet.String() != "subnet" || subnet.ChainID != 0x55005500 {
+		t.Errorf("NewSubnet() = %+v, want name subnet and chain ID 0x55005500", subnet)
+	}
+	if subnet.TransactionVersion != network.Testnet.TransactionVersion || subnet.AddressVersionSinglesig != network.Testnet.AddressVersionSinglesig {
+		t.Errorf("NewSubnet() = %+v, want the testnet versions", subnet)
+	}
+	if network.Testnet.ChainID != 0x80000000 {
+		t.Errorf("NewSubnet() modified the parent network")
+	}
+}
+
+func TestChecks(t *testing.T) {
+	n := network.Testnet
+	if err := n.CheckTransactionVersion(0x80); err != nil {
+		t.Errorf("CheckTransactionVersion(0x80) failed: %v", err)
+	}
+	if err := n.CheckChainID(0x80000000); err != nil {
+		t.Errorf("CheckChainID(0x80000000) failed: %v", err)
+	}
+	for _, version := range []byte{21, 26} {
+		if err := n.CheckAddressVersion(version); err != nil {
+			t.Errorf("CheckAddressVersion(%d) failed: %v", version, err)
+		}
+	}
+
+	testCases := map[string]error{
+		"Mainnet transaction version": n.CheckTransactionVersion(0x00),
+		"Mainnet chain ID":            n.CheckChainID(0x00000001),
+		"Mainnet singlesig address":   n.CheckAddressVersion(22),
+		"Mainnet multisig address":    n.CheckAddressVersion(20),
+	}
+	for name, err := range testCases {
+		if !errors.Is(err, network.ErrWrongNetwork) {
+			t.Errorf("%s: expected ErrWrongNetwork, got %v", name, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []network.Network{
+		network.Mainnet,
+		network.Testnet,
+		network.Devnet,
+		network.NewSubnet("subnet", 0x55005500, network.Mainnet),
+		network.NewSubnet("subnet", 0x55005500, network.Testnet),
+	}
+	for _, n := range valid {
+		if err := n.Validate(); err != nil {
+			t.Errorf("Validate() failed for %+v: %v", n, err)
+		}
+	}
+
+	mainnetVersion := network.NewSubnet("mismatch", network.Testnet.ChainID, network.Mainnet)
+	testnetVersion := network.NewSubnet("mismatch", network.Mainnet.ChainID, network.Testnet)
+	for _, n := range []network.Network{mainnetVersion, testnetVersion} {
+		if err := n.Validate(); !errors.Is(err, network.ErrWrongNetwork) {
+			t.Errorf("Validate() for %+v: expected ErrWrongNetwork, got %v", n, err)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package partially_signed_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/janniks/stacks-go/lib/builder"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/partially_signed"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+const recipient = "SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7"
+
+func TestAirGappedNonSequential(t *testing.T) {
+	privateKeys, publicKeys := testKeys(5)
+	container := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 3, publicKeys)
+
+	// Each signer gets a copy of the unsigned container and signs independently
+	encoded, err := json.Marshal(container)
+	if err != nil {
+		t.Fatalf("Failed to encode container: %v", err)
+	}
+	for _, signer := range []int{4, 1, 2} {
+		var copied partially_signed.Transaction
+		if err := json.Unmarshal(encoded, &copied); err != nil {
+			t.Fatalf("Failed to decode container: %v", err)
+		}
+		if err := copied.Sign(privateKeys[signer]); err != nil {
+			t.Fatalf("Signer %d failed to sign: %v", signer, err)
+		}
+		if container.IsComplete() {
+			t.Errorf("Expected the container to be incomplete")
+		}
+		if err := container.Merge(roundTripBinary(t, &copied)); err != nil {
+			t.Fatalf("Failed to merge signer %d: %v", signer, err)
+		}
+	}
+
+	if !container.IsComplete() {
+		t.Fatalf("Expected the container to be complete with %d signatures", len(container.Signatures))
+	}
+	signed, err := container.Finalize()
+	if err != nil {
+		t.Fatalf("Failed to finalize: %v", err)
+	}
+	assertSigned(t, signed, []bool{false, true, true, false, true})
+}
+
+func TestSequential(t *testing.T) {
+	privateKeys, publicKeys := testKeys(3)
+	container := newContainer(t, transaction.MultisigHashModeP2WSH, 2, publicKeys)
+
+	// The container travels from signer to signer in key order
+	if err := container.Sign(privateKeys[0]); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	passed := roundTripJSON(t, roundTripBinary(t, container))
+	if err := passed.Sign(privateKeys[2]); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if err := passed.Sign(privateKeys[1]); !errors.Is(err, partially_signed.ErrInvalid) {
+		t.Errorf("Expected ErrInvalid for a third signature, got %v", err)
+	}
+
+	signed, err := passed.Finalize()
+	if err != nil {
+		t.Fatalf("Failed to finalize: %v", err)
+	}
+	assertSigned(t, signed, []bool{true, false, true})
+
+	t.Run("Out of order", func(t *testing.T) {
+		container := newContainer(t, transaction.MultisigHashModeP2SH, 2, publicKeys)
+		if err := container.Sign(privateKeys[2]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := container.Sign(privateKeys[0]); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Independent signatures", func(t *testing.T) {
+		// Signer 1 signs without signer 0's signature, so the two do not combine
+		first := newContainer(t, transaction.MultisigHashModeP2SH, 2, publicKeys)
+		second := newContainer(t, transaction.MultisigHashModeP2SH, 2, publicKeys)
+		if err := first.Sign(privateKeys[0]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := second.Sign(privateKeys[1]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := first.Merge(second); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+}
+
+func TestContainerErrors(t *testing.T) {
+	privateKeys, publicKeys := testKeys(3)
+
+	t.Run("Wrong public keys", func(t *testing.T) {
+		tx := newContainer(t, transaction.MultisigHashModeP2SH, 2, publicKeys).Unsigned
+		if _, err := partially_signed.New(tx, 2, publicKeys[:2]); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+		if _, err := partially_signed.New(tx, 1, publicKeys); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Unknown signer", func(t *testing.T) {
+		container := newContainer(t, transaction.MultisigHashModeP2SH, 2, publicKeys)
+		otherKeys, _ := testKeys(4)
+		if err := container.Sign(otherKeys[3]); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Signature by another key", func(t *testing.T) {
+		container := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 2, publicKeys)
+		if err := container.Sign(privateKeys[0]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := container.AddSignature(1, container.Signatures[0]); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+		if err := container.AddSignature(3, container.Signatures[0]); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Incomplete", func(t *testing.T) {
+		container := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 2, publicKeys)
+		if err := container.Sign(privateKeys[0]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if _, err := container.Finalize(); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Different transactions", func(t *testing.T) {
+		container := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 2, publicKeys)
+		other := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 2, publicKeys)
+		other.Unsigned.Auth.SpendingCondition.Nonce++
+		if err := container.Merge(other); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("Unsupported JSON version", func(t *testing.T) {
+		var container partially_signed.Transaction
+		if err := json.Unmarshal([]byte(`{"version": 2}`), &container); !errors.Is(err, partially_signed.ErrInvalid) {
+			t.Errorf("Expected ErrInvalid, got %v", err)
+		}
+	})
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	privateKeys, publicKeys := testKeys(2)
+	container := newContainer(t, transaction.MultisigHashModeP2SHNonSequential, 1, publicKeys)
+	if err := container.Sign(privateKeys[1]); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	encoded, err := container.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to encode container: %v", err)
+	}
+
+	badMagic := append([]byte("psbt"), encoded[4:]...)
+	badVersion := append([]byte("psst\x02"), encoded[5:]...)
+	testCases := map[string]struct {
+		input []byte
+		kind  clarity_value.DeserializeErrorKind
+	}{
+		"Bad magic":      {badMagic, clarity_value.KindBadPrefix},
+		"Bad version":    {badVersion, clarity_value.KindBadPrefix},
+		"Truncated":      {encoded[:len(encoded)-1], clarity_value.KindTruncated},
+		"Trailing bytes": {append(append([]byte(nil), encoded...), 0x00), clarity_value.KindTrailingBytes},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var decoded partially_signed.Transaction
+			if err := decoded.UnmarshalBinary(tc.input); !errors.Is(err, tc.kind) {
+				t.Errorf("Expected %q, got %v", tc.kind, err)
+			}
+		})
+	}
+}
+
+// newContainer builds an unsigned transfer from a multisig origin and wraps it
+func newContainer(t *testing.T, hashMode uint8, required uint16, publicKeys [][]byte) *partially_signed.Transaction {
+	t.Helper()
+	origin, err := transaction.NewMultisigSpendingCondition(hashMode, required, publicKeys)
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+	tx, err := builder.NewTokenTransfer(recipient, 25_000_000).Origin(origin).Nonce(3).Fee(1500).Build()
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	container, err := partially_signed.New(tx, required, publicKeys)
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	return container
+}
+
+// testKeys returns n compressed private keys and their public keys
+func testKeys(n int) ([][]byte, [][]byte) {
+	var privateKeys, publicKeys [][]byte
+	for i := 1; i <= n; i++ {
+		var keyBytes [32]byte
+		for j := range keyBytes {
+			keyBytes[j] = byte(i)
+		}
+		key := secp256k1.PrivKeyFromBytes(keyBytes[:])
+		privateKeys = append(privateKeys, append(key.Serialize(), 0x01))
+		publicKeys = append(publicKeys, key.PubKey().SerializeCompressed())
+	}
+	return privateKeys, publicKeys
+}
+
+func roundTripBinary(t *testing.T, container *partially_signed.Transaction) *partially_signed.Transaction {
+	t.Helper()
+	encoded, err := container.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to encode container: %v", err)
+	}
+	var decoded partially_signed.Transaction
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("Failed to decode container: %v", err)
+	}
+	return &decoded
+}
+
+func roundTripJSON(t *testing.T, container *partially_signed.Transaction) *partially_signed.Transaction {
+	t.Helper()
+	encoded, err := json.Marshal(container)
+	if err != nil {
+		t.Fatalf("Failed to encode container: %v", err)
+	}
+	var decoded partially_signed.Transaction
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to decode container: %v", err)
+	}
+	return &decoded
+}
+
+// assertSigned checks the auth fields of a finalized transaction and that it verifies once broadcast
+func assertSigned(t *testing.T, signed *transaction.StacksTransaction, signing []bool) {
+	t.Helper()
+	fields := signed.Auth.SpendingCondition.Fields
+	if len(fields) != len(signing) {
+		t.Fatalf("Expected %d auth fields, got %d", len(signing), len(fields))
+	}
+	for i, field := range fields {
+		isSignature := field.FieldID == transaction.AuthFieldIDSignatureCompressed
+		if isSignature != signing[i] {
+			t.Errorf("Expected auth field %d to be a signature: %v, got field ID %d", i, signing[i], field.FieldID)
+		}
+	}
+
+	serialized, err := signed.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	broadcast, err := transaction.DecodeTransaction(serialized)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	if err := broadcast.Verify(); err != nil {
+		t.Errorf("Expected the transaction to verify, got %v", err)
+	}
+}
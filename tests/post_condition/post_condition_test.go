@@ -2,11 +2,17 @@ package post_condition_test
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"encoding/hex"
+	"errors"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
+	"github.com/janniks/stacks-go/lib/clarity_value"
 	"github.com/janniks/stacks-go/lib/post_condition"
 )
 
@@ -37,13 +43,81 @@ func TestDecodePostConditionSamples(t *testing.T) {
 		}
 
 		// Decode the post conditions
-		_, err = post_condition.DecodeTxPostConditions(inputBytes)
+		resp, err := post_condition.DecodeTxPostConditions(inputBytes)
 		if err != nil {
 			t.Fatalf("Failed to decode post conditions for input %s: %v", line, err)
 		}
+
+		// Re-encoding the decoded post conditions must reproduce the input
+		encoded, err := post_condition.EncodeTxPostConditions(resp.PostConditionMode, resp.PostConditions)
+		if err != nil {
+			t.Fatalf("Failed to encode post conditions for input %s: %v", line, err)
+		}
+		if !bytes.Equal(encoded, inputBytes) {
+			t.Fatalf("Expected encoding %s, got %x", line, encoded)
+		}
+
+		// Decoding from a plain, non-seekable stream must give the same result
+		if len(inputBytes) > 5 {
+			stream := clarity_value.NewOffsetReader(iotest.OneByteReader(bytes.NewReader(inputBytes[5:])), 5)
+			for i, expected := range resp.PostConditions {
+				postCondition, err := post_condition.DecodePostCondition(stream)
+				if err != nil {
+					t.Fatalf("Failed to stream post condition %d for input %s: %v", i, line, err)
+				}
+				if !reflect.DeepEqual(postCondition, expected) {
+					t.Fatalf("Streamed post condition %d differs for input %s", i, line)
+				}
+			}
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		t.Fatalf("Error reading sample file: %v", err)
 	}
 }
+
+func TestDecodePostConditionErrors(t *testing.T) {
+	header := "02" + "00000001"
+	standard := "02" + "16" + strings.Repeat("00", 20)
+	asset := "16" + strings.Repeat("00", 20) + "0161" + "0162"
+
+	testCases := []struct {
+		name   string
+		input  string
+		kind   clarity_value.DeserializeErrorKind
+		offset int64
+		path   string
+	}{
+		{"Truncated amount", header + "00" + standard + "01" + "0000", clarity_value.KindTruncated, 29, "post_conditions[0].amount"},
+		{"Invalid condition code", header + "00" + standard + "09" + strings.Repeat("00", 8), clarity_value.KindInvalid, 28, "post_conditions[0].condition_code"},
+		{"Unknown principal type", header + "00" + "07", clarity_value.KindBadPrefix, 6, "post_conditions[0].principal.type"},
+		{"Unknown asset type", header + "05" + "01", clarity_value.KindBadPrefix, 5, "post_conditions[0].asset_type"},
+		{"Bad asset value", header + "02" + "01" + asset + "ff", clarity_value.KindBadPrefix, 32, "post_conditions[0].asset_value"},
+		{"Bad asset name", header + "01" + "01" + "16" + strings.Repeat("00", 20) + "0161" + "0131", clarity_value.KindInvalidName, 30, "post_conditions[0].asset.asset_name"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex input: %v", err)
+			}
+
+			_, err = post_condition.DecodeTxPostConditions(data)
+			if !errors.Is(err, clarity_value.ErrDeserialize) || !errors.Is(err, tc.kind) {
+				t.Fatalf("Expected %q error, got %v", tc.kind, err)
+			}
+			var deserializeErr *clarity_value.DeserializeError
+			if !errors.As(err, &deserializeErr) {
+				t.Fatalf("Expected DeserializeError, got %T", err)
+			}
+			if deserializeErr.Offset != tc.offset {
+				t.Errorf("Expected offset %d, got %d (%v)", tc.offset, deserializeErr.Offset, err)
+			}
+			if deserializeErr.Path != tc.path {
+				t.Errorf("Expected path %s, got %s (%v)", tc.path, deserializeErr.Path, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package pox_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/pox"
+)
+
+func TestBitcoinAddressRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name      string
+		address   string
+		network   address.BitcoinNetworkType
+		version   byte
+		hashBytes string
+	}{
+		{"P2PKH mainnet", "1FhZqHcrXaWcNCJPEGn2BRZ9angJvYfTBT", address.Mainnet, pox.AddressVersionP2PKH, "a13dce8114be0f707f94470a2e5e86eb402f2923"},
+		{"P2PKH testnet", "mvtMXL9MYH8HaNz7u9AgapGqoFYpNDfKBx", address.Testnet, pox.AddressVersionP2PKH, "a89603316ec8fa75e3c02c944510bdb854088ddb"},
+		{"P2SH mainnet", "3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y", address.Mainnet, pox.AddressVersionP2SH, "a46ff88886c2ef9762d970b4d2c63678835bd39d"},
+		{"P2WPKH mainnet", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", address.Mainnet, pox.AddressVersionP2WPKH, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"P2WPKH regtest", "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", address.Regtest, pox.AddressVersionP2WPKH, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"P2WSH testnet", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", address.Testnet, pox.AddressVersionP2WSH, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"P2TR mainnet", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", address.Mainnet, pox.AddressVersionP2TR, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tuple, err := pox.BitcoinAddressToTuple(tc.address)
+			if err != nil {
+				t.Fatalf("BitcoinAddressToTuple(%s) failed: %v", tc.address, err)
+			}
+			version := tuple[pox.TupleKeyVersion].Value.(clarity_value.BufferValue)
+			hashBytes := tuple[pox.TupleKeyHashBytes].Value.(clarity_value.BufferValue)
+			if !bytes.Equal(version, []byte{tc.version}) || hex.EncodeToString(hashBytes) != tc.hashBytes {
+				t.Errorf("BitcoinAddressToTuple(%s) = %s, want version %d and hashbytes %s", tc.address, tuple.ReprString(), tc.version, tc.hashBytes)
+			}
+
+			result, err := pox.TupleToBitcoinAddress(tuple, tc.network)
+			if err != nil {
+				t.Fatalf("TupleToBitcoinAddress() failed: %v", err)
+			}
+			if result != tc.address {
+				t.Errorf("TupleToBitcoinAddress() = %s, want %s", result, tc.address)
+			}
+		})
+	}
+}
+
+func TestWrappedSegwitVersions(t *testing.T) {
+	// P2SH-wrapped segwit versions are paid to as plain P2SH outputs
+	hashBytes, _ := hex.DecodeString("a46ff88886c2ef9762d970b4d2c63678835bd39d")
+	for _, version := range []byte{pox.AddressVersionP2SHP2WPKH, pox.AddressVersionP2SHP2WSH} {
+		result, err := pox.Address{Version: version, HashBytes: hashBytes}.BitcoinAddress(address.Mainnet)
+		if err != nil {
+			t.Fatalf("BitcoinAddress() failed: %v", err)
+		}
+		if result != "3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y" {
+			t.Errorf("BitcoinAddress() for version %d = %s, want 3GgUssdoWh5QkoUDXKqT6LMESBDf8aqp2y", version, result)
+		}
+	}
+}
+
+func TestDecodedTuple(t *testing.T) {
+	// (tuple (hashbytes 0x751e...) (version 0x04)) as serialized in a stack-stx call
+	serialized, _ := hex.DecodeString("0c00000002" +
+		"0968617368627974657302" + "00000014" + "751e76e8199196d454941c45d1b3a323f1433bd6" +
+		"0776657273696f6e02" + "00000001" + "04")
+	value, err := clarity_value.DecodeClarityValue(bytes.NewReader(serialized), false)
+	if err != nil {
+		t.Fatalf("Failed to decode tuple: %v", err)
+	}
+
+	result, err := pox.TupleToBitcoinAddress(value.Value.(clarity_value.TupleValue), address.Testnet)
+	if err != nil {
+		t.Fatalf("TupleToBitcoinAddress() failed: %v", err)
+	}
+	if result != "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx" {
+		t.Errorf("TupleToBitcoinAddress() = %s, want tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", result)
+	}
+}
+
+func TestInvalidTuples(t *testing.T) {
+	buffer := func(b []byte) clarity_value.ClarityValue {
+		return clarity_value.NewClarityValue(clarity_value.BufferValue(b))
+	}
+	hash20 := bytes.Repeat([]byte{0x01}, 20)
+	hash32 := bytes.Repeat([]byte{0x01}, 32)
+
+	testCases := map[string]clarity_value.TupleValue{
+		"P2PKH with 32 bytes":  {pox.TupleKeyVersion: buffer([]byte{0x00}), pox.TupleKeyHashBytes: buffer(hash32)},
+		"P2WPKH with 32 bytes": {pox.TupleKeyVersion: buffer([]byte{0x04}), pox.TupleKeyHashBytes: buffer(hash32)},
+		"P2WSH with 20 bytes":  {pox.TupleKeyVersion: buffer([]byte{0x05}), pox.TupleKeyHashBytes: buffer(hash20)},
+		"P2TR with 20 bytes":   {pox.TupleKeyVersion: buffer([]byte{0x06}), pox.TupleKeyHashBytes: buffer(hash20)},
+		"Unknown version":      {pox.TupleKeyVersion: buffer([]byte{0x07}), pox.TupleKeyHashBytes: buffer(hash32)},
+		"Long version":         {pox.TupleKeyVersion: buffer([]byte{0x00, 0x00}), pox.TupleKeyHashBytes: buffer(hash20)},
+		"Missing hashbytes":    {pox.TupleKeyVersion: buffer([]byte{0x00}), "hash": buffer(hash20)},
+		"Extra field": {
+			pox.TupleKeyVersion:   buffer([]byte{0x00}),
+			pox.TupleKeyHashBytes: buffer(hash20),
+			"network":             buffer([]byte{0x00}),
+		},
+		"Version not a buffer": {
+			pox.TupleKeyVersion:   clarity_value.NewClarityValue(clarity_value.NewUIntValue(0)),
+			pox.TupleKeyHashBytes: buffer(hash20),
+		},
+	}
+
+	for name, tuple := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := pox.TupleToBitcoinAddress(tuple, address.Mainnet); err == nil {
+				t.Errorf("TupleToBitcoinAddress() expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := (pox.Address{Version: pox.AddressVersionP2TR, HashBytes: hash20}).Tuple(); err == nil {
+		t.Errorf("Tuple() expected error for a short P2TR address, got nil")
+	}
+	// Segwit v2 has no PoX address version
+	if _, err := pox.BitcoinAddressToTuple("bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs"); err == nil {
+		t.Errorf("BitcoinAddressToTuple() expected error for a segwit v2 address, got nil")
+	}
+}
@@ -0,0 +1,239 @@
+package transaction_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+// Mainnet contract call to arkadiko-governance-v3-1.propose, from the Rust test suite
+const arkadikoProposeTransactionHex = "00000000010400982f3ec112a5f5928a5c96a914bd733793b896a5000000000000053000000000000002290000c85889dad0d5b08a997a93a28a7c93eb22c324e5f8992dc93e37865ef4f3e0d65383beefeffc4871a2facbc4b590ddf887c80de6638ed4e2ec0e633d1e130f230301000000000216982f3ec112a5f5928a5c96a914bd733793b896a51861726b6164696b6f2d676f7665726e616e63652d76332d310770726f706f7365000000060616982f3ec112a5f5928a5c96a914bd733793b896a51d61726b6164696b6f2d7374616b652d706f6f6c2d64696b6f2d76312d32010000000000000000000000000000ef8801000000000000000000000000000003f00e00000028414950313020557064617465204c54567320616e64204c69717569646174696f6e20526174696f730e0000003168747470733a2f2f6769746875622e636f6d2f61726b6164696b6f2d64616f2f61726b6164696b6f2f70756c6c2f3439330b000000010c0000000507616464726573730516982f3ec112a5f5928a5c96a914bd733793b896a50863616e2d6275726e040863616e2d6d696e7404046e616d650d0000002b61697031302d61726b6164696b6f2d7570646174652d74766c2d6c69717569646174696f6e2d726174696f0e7175616c69666965642d6e616d650616982f3ec112a5f5928a5c96a914bd733793b896a52b61697031302d61726b6164696b6f2d7570646174652d74766c2d6c69717569646174696f6e2d726174696f"
+
+func TestSerializeTransactionRoundTrip(t *testing.T) {
+	standardPrincipal := "05" + "16" + strings.Repeat("55", 20)
+	contractPrincipal := "06" + "16" + strings.Repeat("55", 20) + "05746f6b656e"
+	microblockHeader := "00" + "0001" + strings.Repeat("aa", 32) + strings.Repeat("bb", 32) + strings.Repeat("cc", 65)
+	vrfProof := fmt.Sprintf("%08x", transaction.VRFProofLength) + strings.Repeat("66", transaction.VRFProofLength)
+	coinbase := strings.Repeat("77", 32)
+	multisigFields := "00000003" + "00" + strings.Repeat("02", 33) + "02" + strings.Repeat("88", 65) + "03" + strings.Repeat("99", 65)
+	p2wpkhAuth := "04" + "02" + strings.Repeat("33", 20) + strings.Repeat("00", 16) + "00" + strings.Repeat("44", 65)
+	multisigAuth := "04" + "05" + strings.Repeat("33", 20) + strings.Repeat("00", 16) + multisigFields + "0002"
+	sponsoredAuth := "05" + singlesigAuthHex[2:] + "01" + strings.Repeat("34", 20) + strings.Repeat("00", 16) + multisigFields + "0002"
+
+	testCases := map[string]string{
+		"Mainnet contract call":         arkadikoProposeTransactionHex,
+		"Testnet tenure change":         tenureChangeTransactionHex,
+		"Token transfer":                transactionHex(singlesigAuthHex, "00"+contractPrincipal+"0000000000000064"+strings.Repeat("6d", 34)),
+		"Smart contract":                transactionHex(p2wpkhAuth, "01"+"0568656c6c6f"+"00000004"+"28292029"),
+		"Contract call":                 contractCallTransactionHex("0100000000000000000000000000000064", standardPrincipal),
+		"Poison microblock":             transactionHex(singlesigAuthHex, "03"+microblockHeader+microblockHeader),
+		"Coinbase":                      transactionHex(multisigAuth, "04"+coinbase),
+		"Coinbase to alt recipient":     transactionHex(singlesigAuthHex, "05"+coinbase+contractPrincipal),
+		"Versioned smart contract":      transactionHex(singlesigAuthHex, "06"+"03"+"0568656c6c6f"+"00000004"+"28292029"),
+		"Nakamoto coinbase":             transactionHex(singlesigAuthHex, "08"+coinbase+"0a"+standardPrincipal+vrfProof),
+		"Nakamoto coinbase without alt": transactionHex(singlesigAuthHex, "08"+coinbase+"09"+vrfProof),
+		"Sponsored":                     transactionHex(sponsoredAuth, "04"+coinbase),
+	}
+
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assertSerializeRoundTrip(t, input)
+		})
+	}
+}
+
+func TestSerializeTransactionMainnetCorpus(t *testing.T) {
+	// Raw mainnet transactions, one hex encoded transaction per line
+	corpusFile, err := os.Open("../gz/mainnet-transactions.txt.gz")
+	if err != nil {
+		t.Fatalf("Failed to open corpus file: %v", err)
+	}
+	defer corpusFile.Close()
+
+	gzipReader, err := gzip.NewReader(corpusFile)
+	if err != nil {
+		t.Fatalf("Failed to create gzip reader: %v", err)
+	}
+	defer gzipReader.Close()
+
+	variants := map[string]int{}
+	scanner := bufio.NewScanner(gzipReader)
+	scanner.Buffer(nil, 4*1024*1024)
+	for scanner.Scan() {
+		assertSerializeRoundTrip(t, scanner.Text())
+
+		tx, err := decodeTransactionHex(scanner.Text())
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		for _, variant := range corpusVariants(tx) {
+			variants[variant]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Error reading corpus file: %v", err)
+	}
+
+	// Every payload type and auth variant seen on mainnet must be covered
+	required := []string{
+		"token transfer",
+		"smart contract",
+		"versioned smart contract",
+		"contract call",
+		"coinbase",
+		"coinbase to alt recipient",
+		"tenure change",
+		"nakamoto coinbase",
+		"p2pkh",
+		"p2wpkh",
+		"multisig",
+		"sponsored",
+	}
+	for _, variant := range required {
+		if variants[variant] == 0 {
+			t.Errorf("Expected a %s transaction in the corpus file", variant)
+		}
+	}
+}
+
+// corpusVariants returns the payload type and auth variants of a transaction
+func corpusVariants(tx *transaction.StacksTransaction) []string {
+	payloads := map[uint8]string{
+		transaction.TransactionPayloadIDTokenTransfer:          "token transfer",
+		transaction.TransactionPayloadIDSmartContract:          "smart contract",
+		transaction.TransactionPayloadIDContractCall:           "contract call",
+		transaction.TransactionPayloadIDPoisonMicroblock:       "poison microblock",
+		transaction.TransactionPayloadIDCoinbase:               "coinbase",
+		transaction.TransactionPayloadIDCoinbaseToAltRecipient: "coinbase to alt recipient",
+		transaction.TransactionPayloadIDVersionedSmartContract: "versioned smart contract",
+		transaction.TransactionPayloadIDTenureChange:           "tenure change",
+		transaction.TransactionPayloadIDNakamotoCoinbase:       "nakamoto coinbase",
+	}
+	variants := []string{payloads[tx.Payload.PayloadType]}
+
+	switch tx.Auth.SpendingCondition.HashMode {
+	case transaction.SinglesigHashModeP2PKH:
+		variants = append(variants, "p2pkh")
+	case transaction.SinglesigHashModeP2WPKH:
+		variants = append(variants, "p2wpkh")
+	default:
+		variants = append(variants, "multisig")
+	}
+	if tx.Auth.AuthType == transaction.TransactionAuthFlagSponsored {
+		variants = append(variants, "sponsored")
+	}
+	return variants
+}
+
+func TestSerializeTransactionSampledPostConditions(t *testing.T) {
+	// Real post conditions from the sample corpus, in place of the contract call's own
+	sampleFile, err := os.Open("../gz/sampled-post-conditions.txt.gz")
+	if err != nil {
+		t.Fatalf("Failed to open sample file: %v", err)
+	}
+	defer sampleFile.Close()
+
+	gzipReader, err := gzip.NewReader(sampleFile)
+	if err != nil {
+		t.Fatalf("Failed to create gzip reader: %v", err)
+	}
+	defer gzipReader.Close()
+
+	scanner := bufio.NewScanner(gzipReader)
+	for scanner.Scan() {
+		input := strings.Replace(arkadikoProposeTransactionHex, "0100000000", scanner.Text(), 1)
+		assertSerializeRoundTrip(t, input)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Error reading sample file: %v", err)
+	}
+}
+
+func TestSerializeEditedTransaction(t *testing.T) {
+	txBytes, _ := hex.DecodeString(arkadikoProposeTransactionHex)
+	tx, err := transaction.DecodeTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+
+	// Edits to the decoded fields are reflected in the serialized transaction
+	tx.Auth.SpendingCondition.Fee = 5000
+	tx.Payload.ContractCall.FunctionArgs[1] = clarity_value.NewClarityValue(clarity_value.NewUIntValue(7))
+
+	var buf bytes.Buffer
+	n, err := tx.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Failed to write transaction: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("Expected %d bytes written, got %d", buf.Len(), n)
+	}
+
+	edited, err := transaction.DecodeTransaction(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to decode edited transaction: %v", err)
+	}
+	if edited.Auth.SpendingCondition.Fee != 5000 {
+		t.Errorf("Expected fee 5000, got %d", edited.Auth.SpendingCondition.Fee)
+	}
+	if edited.Payload.ContractCall.FunctionArgs[1].Value.ReprString() != "u7" {
+		t.Errorf("Expected u7, got %s", edited.Payload.ContractCall.FunctionArgs[1].Value.ReprString())
+	}
+}
+
+func TestSerializeTransactionErrors(t *testing.T) {
+	testCases := map[string]*transaction.StacksTransaction{
+		"Unknown auth type": {
+			Auth: transaction.TransactionAuth{AuthType: 0x06},
+		},
+		"Missing sponsor": {
+			Auth: transaction.TransactionAuth{AuthType: transaction.TransactionAuthFlagSponsored},
+		},
+		"Missing payload": {
+			Auth:    transaction.TransactionAuth{AuthType: transaction.TransactionAuthFlagStandard},
+			Payload: transaction.TransactionPayload{PayloadType: transaction.TransactionPayloadIDContractCall},
+		},
+		"Unknown payload type": {
+			Auth:    transaction.TransactionAuth{AuthType: transaction.TransactionAuthFlagStandard},
+			Payload: transaction.TransactionPayload{PayloadType: 0x83},
+		},
+	}
+
+	for name, tx := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tx.Serialize(); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+// assertSerializeRoundTrip checks that Serialize(Decode(b)) == b
+func assertSerializeRoundTrip(t *testing.T, input string) {
+	t.Helper()
+
+	txBytes, err := hex.DecodeString(input)
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+	tx, err := transaction.DecodeTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction %s: %v", input, err)
+	}
+
+	serialized, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction %s: %v", input, err)
+	}
+	if !bytes.Equal(serialized, txBytes) {
+		t.Errorf("Expected %s, got %x", input, serialized)
+	}
+}
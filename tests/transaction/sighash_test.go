@@ -0,0 +1,174 @@
+package transaction_test
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+// emptySinglesigHex is a cleared singlesig condition's nonce, fee, key encoding and signature
+var emptySinglesigHex = strings.Repeat("00", 8+8+1+65)
+
+// multisigFieldsHex holds a public key, a compressed signature and an uncompressed signature
+var multisigFieldsHex = "00000003" + "00" + strings.Repeat("02", 33) + "02" + strings.Repeat("88", 65) + "03" + strings.Repeat("99", 65)
+
+func TestTransactionTxid(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"Mainnet contract call": {arkadikoProposeTransactionHex, "f402ee582892c48679e6bbfbdc1f12a1da5f1ebf705dfacd1982ad70296490f2"},
+		"Testnet tenure change": {tenureChangeTransactionHex, "d443c1edb6bbcbdb702884a688b3ed09cc2d81e391f09c4d91ac881806979620"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx, err := decodeTransactionHex(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode transaction: %v", err)
+			}
+			txid, err := tx.Txid()
+			if err != nil {
+				t.Fatalf("Failed to compute txid: %v", err)
+			}
+			if hex.EncodeToString(txid[:]) != tc.expected {
+				t.Errorf("Expected txid %s, got %x", tc.expected, txid)
+			}
+		})
+	}
+}
+
+func TestInitialSighash(t *testing.T) {
+	signer := strings.Repeat("33", 20)
+	sponsorSigner := strings.Repeat("34", 20)
+	multisigAuth := "04" + "01" + signer + "0000000000000007" + "0000000000000190" + multisigFieldsHex + "0002"
+	sponsoredAuth := "05" + singlesigAuthHex[2:] + "00" + sponsorSigner + "0000000000000009" + "00000000000001f4" + "01" + strings.Repeat("55", 65)
+
+	testCases := map[string]struct {
+		input   string
+		cleared string
+	}{
+		"Singlesig": {
+			arkadikoProposeTransactionHex,
+			arkadikoProposeTransactionHex[:54] + emptySinglesigHex + arkadikoProposeTransactionHex[218:],
+		},
+		"Multisig": {
+			transactionHex(multisigAuth, "04"+strings.Repeat("77", 32)),
+			transactionHex("04"+"01"+signer+strings.Repeat("00", 16)+"00000000"+"0002", "04"+strings.Repeat("77", 32)),
+		},
+		"Sponsored": {
+			transactionHex(sponsoredAuth, "04"+strings.Repeat("77", 32)),
+			transactionHex("05"+"00"+signer+emptySinglesigHex+"00"+strings.Repeat("00", 20)+emptySinglesigHex, "04"+strings.Repeat("77", 32)),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx, err := decodeTransactionHex(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode transaction: %v", err)
+			}
+			sighash, err := tx.InitialSighash()
+			if err != nil {
+				t.Fatalf("Failed to compute initial sighash: %v", err)
+			}
+
+			clearedBytes, _ := hex.DecodeString(tc.cleared)
+			expected := sha512.Sum512_256(clearedBytes)
+			if sighash != expected {
+				t.Errorf("Expected initial sighash %x, got %x", expected, sighash)
+			}
+
+			// Clearing works on a copy
+			serialized, err := tx.Serialize()
+			if err != nil {
+				t.Fatalf("Failed to serialize transaction: %v", err)
+			}
+			if hex.EncodeToString(serialized) != tc.input {
+				t.Errorf("Expected the transaction to be unchanged, got %x", serialized)
+			}
+		})
+	}
+}
+
+func TestSighashChain(t *testing.T) {
+	var sig1, sig2, sponsorSig [65]byte
+	copy(sig1[:], strings.Repeat("\x88", 65))
+	copy(sig2[:], strings.Repeat("\x99", 65))
+	copy(sponsorSig[:], strings.Repeat("\x55", 65))
+
+	multisigAuth := func(hashMode string) string {
+		return "05" + hashMode + strings.Repeat("33", 20) + "0000000000000007" + "0000000000000190" + multisigFieldsHex + "0002" +
+			"00" + strings.Repeat("34", 20) + "0000000000000009" + "00000000000001f4" + "00" + strings.Repeat("55", 65)
+	}
+
+	t.Run("Sequential", func(t *testing.T) {
+		tx, err := decodeTransactionHex(transactionHex(multisigAuth("01"), "04"+strings.Repeat("77", 32)))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		initial, err := tx.InitialSighash()
+		if err != nil {
+			t.Fatalf("Failed to compute initial sighash: %v", err)
+		}
+
+		presigns, next, err := tx.Auth.SpendingCondition.Sighashes(initial, transaction.TransactionAuthFlagStandard)
+		if err != nil {
+			t.Fatalf("Failed to compute sighashes: %v", err)
+		}
+		presign1 := transaction.SighashPresign(initial, transaction.TransactionAuthFlagStandard, 400, 7)
+		presign2 := transaction.SighashPresign(transaction.SighashPostsign(presign1, transaction.PublicKeyEncodingCompressed, sig1), transaction.TransactionAuthFlagStandard, 400, 7)
+		if len(presigns) != 2 || presigns[0] != presign1 || presigns[1] != presign2 {
+			t.Errorf("Expected presign sighashes %x and %x, got %x", presign1, presign2, presigns)
+		}
+		if expected := transaction.SighashPostsign(presign2, transaction.PublicKeyEncodingUncompressed, sig2); next != expected {
+			t.Errorf("Expected next sighash %x, got %x", expected, next)
+		}
+
+		// The sponsor signs after the origin
+		sponsorPresigns, sponsorNext, err := tx.Auth.SponsorSpendingCondition.Sighashes(next, transaction.TransactionAuthFlagSponsored)
+		if err != nil {
+			t.Fatalf("Failed to compute sponsor sighashes: %v", err)
+		}
+		sponsorPresign := transaction.SighashPresign(next, transaction.TransactionAuthFlagSponsored, 500, 9)
+		if len(sponsorPresigns) != 1 || sponsorPresigns[0] != sponsorPresign {
+			t.Errorf("Expected sponsor presign sighash %x, got %x", sponsorPresign, sponsorPresigns)
+		}
+		if expected := transaction.SighashPostsign(sponsorPresign, transaction.PublicKeyEncodingCompressed, sponsorSig); sponsorNext != expected {
+			t.Errorf("Expected sponsor next sighash %x, got %x", expected, sponsorNext)
+		}
+	})
+
+	t.Run("Non-sequential", func(t *testing.T) {
+		tx, err := decodeTransactionHex(transactionHex(multisigAuth("05"), "04"+strings.Repeat("77", 32)))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		initial, err := tx.InitialSighash()
+		if err != nil {
+			t.Fatalf("Failed to compute initial sighash: %v", err)
+		}
+
+		presigns, next, err := tx.Auth.SpendingCondition.Sighashes(initial, transaction.TransactionAuthFlagStandard)
+		if err != nil {
+			t.Fatalf("Failed to compute sighashes: %v", err)
+		}
+		presign := transaction.SighashPresign(initial, transaction.TransactionAuthFlagStandard, 400, 7)
+		if len(presigns) != 2 || presigns[0] != presign || presigns[1] != presign {
+			t.Errorf("Expected both signers to sign %x, got %x", presign, presigns)
+		}
+		if next != initial {
+			t.Errorf("Expected next sighash to stay %x, got %x", initial, next)
+		}
+	})
+
+	t.Run("Unknown hash mode", func(t *testing.T) {
+		condition := transaction.TransactionSpendingCondition{HashMode: 0x04}
+		if _, _, err := condition.Sighashes([32]byte{}, transaction.TransactionAuthFlagStandard); err == nil {
+			t.Errorf("Expected an error but got none")
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package transaction_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+func TestSignMessageHash(t *testing.T) {
+	// RFC 6979 secp256k1 vectors for the private key 1
+	privateKey := append(bytes.Repeat([]byte{0x00}, 31), 0x01)
+	testCases := map[string]string{
+		"Satoshi Nakamoto": "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		"All those moments will be lost in time, like tears in rain. Time to die...": "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+	}
+
+	for message, expected := range testCases {
+		t.Run(message, func(t *testing.T) {
+			signature, err := transaction.SignMessageHash(privateKey, sha256.Sum256([]byte(message)))
+			if err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+			if hex.EncodeToString(signature[1:]) != expected {
+				t.Errorf("Expected r and s %s, got %x", expected, signature[1:])
+			}
+			if signature[0] > 3 {
+				t.Errorf("Expected a recovery ID, got %d", signature[0])
+			}
+		})
+	}
+
+	if _, err := transaction.SignMessageHash(privateKey[:31], [32]byte{}); !errors.Is(err, transaction.ErrSign) {
+		t.Errorf("Expected ErrSign for a short key, got %v", err)
+	}
+	if _, err := transaction.SignMessageHash(make([]byte, 32), [32]byte{}); !errors.Is(err, transaction.ErrSign) {
+		t.Errorf("Expected ErrSign for a zero key, got %v", err)
+	}
+}
+
+func TestSignSinglesig(t *testing.T) {
+	key := testPrivateKey(1)
+	testCases := map[string]struct {
+		hashMode   uint8
+		publicKey  []byte
+		privateKey []byte
+	}{
+		"P2PKH":              {transaction.SinglesigHashModeP2PKH, key.PubKey().SerializeCompressed(), append(key.Serialize(), 0x01)},
+		"P2PKH uncompressed": {transaction.SinglesigHashModeP2PKH, key.PubKey().SerializeUncompressed(), key.Serialize()},
+		"P2WPKH":             {transaction.SinglesigHashModeP2WPKH, key.PubKey().SerializeCompressed(), append(key.Serialize(), 0x01)},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx := unsignedTestTransaction(t)
+			origin, err := transaction.NewSinglesigSpendingCondition(tc.hashMode, tc.publicKey)
+			if err != nil {
+				t.Fatalf("Failed to create spending condition: %v", err)
+			}
+			origin.Nonce, origin.Fee = 5, 200
+			tx.Auth.SpendingCondition = origin
+
+			signed := signOrigin(t, tx, tc.privateKey)
+			assertVerifiesAfterRoundTrip(t, signed)
+
+			// Signing is deterministic and leaves the unsigned transaction unchanged
+			again := signOrigin(t, tx, tc.privateKey)
+			if *again.Auth.SpendingCondition.Signature != *signed.Auth.SpendingCondition.Signature {
+				t.Errorf("Expected the same signature, got %x and %x", *signed.Auth.SpendingCondition.Signature, *again.Auth.SpendingCondition.Signature)
+			}
+		})
+	}
+
+	t.Run("Wrong key", func(t *testing.T) {
+		tx := unsignedTestTransaction(t)
+		tx.Auth.SpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, key.PubKey().SerializeCompressed())
+		signer, _ := transaction.NewSigner(tx)
+		if err := signer.SignOrigin(append(testPrivateKey(2).Serialize(), 0x01)); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+		// The key's public key encoding is part of the signer hash
+		if err := signer.SignOrigin(key.Serialize()); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+	})
+
+	t.Run("Signed twice", func(t *testing.T) {
+		tx := unsignedTestTransaction(t)
+		tx.Auth.SpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, key.PubKey().SerializeCompressed())
+		signer, _ := transaction.NewSigner(tx)
+		if err := signer.SignOrigin(append(key.Serialize(), 0x01)); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if err := signer.SignOrigin(append(key.Serialize(), 0x01)); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+	})
+}
+
+func TestSignMultisig(t *testing.T) {
+	keys := [][]byte{
+		append(testPrivateKey(1).Serialize(), 0x01),
+		append(testPrivateKey(2).Serialize(), 0x01),
+		append(testPrivateKey(3).Serialize(), 0x01),
+	}
+	var publicKeys [][]byte
+	for seed := byte(1); seed <= 3; seed++ {
+		publicKeys = append(publicKeys, testPrivateKey(seed).PubKey().SerializeCompressed())
+	}
+
+	hashModes := map[string]uint8{
+		"P2SH":                 transaction.MultisigHashModeP2SH,
+		"P2WSH":                transaction.MultisigHashModeP2WSH,
+		"P2SH non-sequential":  transaction.MultisigHashModeP2SHNonSequential,
+		"P2WSH non-sequential": transaction.MultisigHashModeP2WSHNonSequential,
+	}
+
+	for name, hashMode := range hashModes {
+		t.Run(name, func(t *testing.T) {
+			tx := unsignedTestTransaction(t)
+			origin, err := transaction.NewMultisigSpendingCondition(hashMode, 2, publicKeys)
+			if err != nil {
+				t.Fatalf("Failed to create spending condition: %v", err)
+			}
+			origin.Nonce, origin.Fee = 1, 600
+			tx.Auth.SpendingCondition = origin
+
+			signer, err := transaction.NewSigner(tx)
+			if err != nil {
+				t.Fatalf("Failed to create signer: %v", err)
+			}
+			if err := signer.SignOrigin(keys[0]); err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+			if err := signer.AppendOriginPublicKey(publicKeys[1]); err != nil {
+				t.Fatalf("Failed to append public key: %v", err)
+			}
+			if err := signer.SignOrigin(keys[2]); err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+			if err := signer.SignOrigin(keys[1]); !errors.Is(err, transaction.ErrSign) {
+				t.Errorf("Expected ErrSign for a third signature, got %v", err)
+			}
+
+			fields := signer.Transaction().Auth.SpendingCondition.Fields
+			if len(fields) != 3 || fields[0].FieldID != transaction.AuthFieldIDSignatureCompressed ||
+				fields[1].FieldID != transaction.AuthFieldIDPublicKeyCompressed || fields[2].FieldID != transaction.AuthFieldIDSignatureCompressed {
+				t.Errorf("Expected signature, public key and signature fields, got %+v", fields)
+			}
+			assertVerifiesAfterRoundTrip(t, signer.Transaction())
+		})
+	}
+
+	t.Run("Public key on singlesig", func(t *testing.T) {
+		tx := unsignedTestTransaction(t)
+		tx.Auth.SpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, publicKeys[0])
+		signer, _ := transaction.NewSigner(tx)
+		if err := signer.AppendOriginPublicKey(publicKeys[1]); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+	})
+}
+
+func TestSignSponsored(t *testing.T) {
+	originKey := append(testPrivateKey(1).Serialize(), 0x01)
+	sponsorKeys := [][]byte{append(testPrivateKey(2).Serialize(), 0x01), append(testPrivateKey(3).Serialize(), 0x01)}
+	sponsorPublicKeys := [][]byte{testPrivateKey(2).PubKey().SerializeCompressed(), testPrivateKey(3).PubKey().SerializeCompressed()}
+
+	tx := unsignedTestTransaction(t)
+	tx.Auth.AuthType = transaction.TransactionAuthFlagSponsored
+	tx.Auth.SpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, testPrivateKey(1).PubKey().SerializeCompressed())
+	tx.Auth.SpendingCondition.Nonce = 8
+	tx.Auth.SponsorSpendingCondition = &transaction.TransactionSpendingCondition{}
+	*tx.Auth.SponsorSpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, sponsorPublicKeys[0])
+
+	// The origin signs without knowing the sponsor
+	originSigned := signOrigin(t, tx, originKey)
+
+	sponsor, err := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2SH, 2, sponsorPublicKeys)
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+	sponsor.Nonce, sponsor.Fee = 40, 3000
+
+	signer, err := transaction.NewSponsorSigner(originSigned, sponsor)
+	if err != nil {
+		t.Fatalf("Failed to create sponsor signer: %v", err)
+	}
+	for _, key := range sponsorKeys {
+		if err := signer.SignSponsor(key); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+	}
+	if err := signer.SignOrigin(originKey); !errors.Is(err, transaction.ErrSign) {
+		t.Errorf("Expected ErrSign for signing the origin after the sponsor, got %v", err)
+	}
+	assertVerifiesAfterRoundTrip(t, signer.Transaction())
+
+	t.Run("Unsigned origin", func(t *testing.T) {
+		if _, err := transaction.NewSponsorSigner(tx, sponsor); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+	})
+
+	t.Run("Not sponsored", func(t *testing.T) {
+		standard := unsignedTestTransaction(t)
+		if _, err := transaction.NewSponsorSigner(standard, sponsor); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+		signer, _ := transaction.NewSigner(standard)
+		if err := signer.SignSponsor(sponsorKeys[0]); !errors.Is(err, transaction.ErrSign) {
+			t.Errorf("Expected ErrSign, got %v", err)
+		}
+	})
+}
+
+// signOrigin signs the origin of a singlesig transaction
+func signOrigin(t *testing.T, tx *transaction.StacksTransaction, privateKey []byte) *transaction.StacksTransaction {
+	t.Helper()
+	signer, err := transaction.NewSigner(tx)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	if err := signer.SignOrigin(privateKey); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	return signer.Transaction()
+}
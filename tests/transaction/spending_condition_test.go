@@ -0,0 +1,105 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+func TestNewSpendingCondition(t *testing.T) {
+	key := testPrivateKey(1)
+	compressed := key.PubKey().SerializeCompressed()
+	uncompressed := key.PubKey().SerializeUncompressed()
+
+	condition, err := transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, uncompressed)
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+	if condition.Signer != testHash160(uncompressed) || *condition.KeyEncoding != transaction.PublicKeyEncodingUncompressed {
+		t.Errorf("Expected an uncompressed P2PKH condition, got %+v", condition)
+	}
+
+	script := append([]byte{0x51, 33}, compressed...)
+	script = append(script, 0x51, 0xae)
+	condition, err = transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2SHNonSequential, 1, [][]byte{compressed})
+	if err != nil {
+		t.Fatalf("Failed to create spending condition: %v", err)
+	}
+	if condition.Signer != testHash160(script) || condition.ConditionType != 0x01 {
+		t.Errorf("Expected a 1-of-1 P2SH condition, got %+v", condition)
+	}
+
+	errorCases := map[string]func() error{
+		"Multisig singlesig hash mode": func() error {
+			_, err := transaction.NewSinglesigSpendingCondition(transaction.MultisigHashModeP2SH, compressed)
+			return err
+		},
+		"Uncompressed P2WPKH": func() error {
+			_, err := transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2WPKH, uncompressed)
+			return err
+		},
+		"Invalid public key": func() error {
+			_, err := transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2PKH, compressed[1:])
+			return err
+		},
+		"Singlesig multisig hash mode": func() error {
+			_, err := transaction.NewMultisigSpendingCondition(transaction.SinglesigHashModeP2PKH, 1, [][]byte{compressed})
+			return err
+		},
+		"No signatures required": func() error {
+			_, err := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2SH, 0, [][]byte{compressed})
+			return err
+		},
+		"More signatures than keys": func() error {
+			_, err := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2SH, 2, [][]byte{compressed})
+			return err
+		},
+		"Uncompressed P2WSH": func() error {
+			_, err := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2WSH, 1, [][]byte{uncompressed})
+			return err
+		},
+	}
+
+	for name, create := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if err := create(); err == nil {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestOriginAndSponsorAddress(t *testing.T) {
+	compressed := testPrivateKey(1).PubKey().SerializeCompressed()
+	publicKeys := [][]byte{compressed, testPrivateKey(2).PubKey().SerializeCompressed()}
+
+	tx := unsignedTestTransaction(t)
+	tx.Auth.SpendingCondition, _ = transaction.NewSinglesigSpendingCondition(transaction.SinglesigHashModeP2WPKH, compressed)
+	expected, err := address.FromPublicKeys(address.C32AddressVersionMainnetSinglesig, address.SerializeP2WPKH, 1, [][]byte{compressed})
+	if err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+	tx.Version = transaction.TransactionVersionMainnet
+	if origin, err := tx.OriginAddress(); err != nil || origin != expected {
+		t.Errorf("Expected origin address %v, got %v (%v)", expected, origin, err)
+	}
+	if _, err := tx.SponsorAddress(); err == nil {
+		t.Errorf("Expected an error for a transaction that is not sponsored")
+	}
+
+	sponsor, _ := transaction.NewMultisigSpendingCondition(transaction.MultisigHashModeP2WSHNonSequential, 2, publicKeys)
+	tx.Auth.AuthType = transaction.TransactionAuthFlagSponsored
+	tx.Auth.SponsorSpendingCondition = &sponsor
+	tx.Version = transaction.TransactionVersionTestnet
+	expected, err = address.FromPublicKeys(address.C32AddressVersionTestnetMultisig, address.SerializeP2WSHNonSequential, 2, publicKeys)
+	if err != nil {
+		t.Fatalf("Failed to derive address: %v", err)
+	}
+	if sponsorAddress, err := tx.SponsorAddress(); err != nil || sponsorAddress != expected {
+		t.Errorf("Expected sponsor address %v, got %v (%v)", expected, sponsorAddress, err)
+	}
+	if origin, _ := tx.OriginAddress(); origin.Version != address.C32AddressVersionTestnetSinglesig {
+		t.Errorf("Expected a testnet singlesig origin address, got %v", origin)
+	}
+}
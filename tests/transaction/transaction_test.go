@@ -1,15 +1,24 @@
 package transaction_test
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/janniks/stacks-go/lib/clarity_value"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/post_condition"
 	"github.com/janniks/stacks-go/lib/transaction"
 )
 
+// Verbatim test vector from Rust test: a tenure change with no post conditions
+const tenureChangeTransactionHex = "808000000004001dc27eba0247f8cc9575e7d45e50a0bc7e72427d000000000000001d000000000000000000011dc72b6dfd9b36e414a2709e3b01eb5bbdd158f9bc77cd2ca6c3c8b0c803613e2189f6dacf709b34e8182e99d3a1af15812b75e59357d9c255c772695998665f010200000000076f2ff2c4517ab683bf2d588727f09603cc3e9328b9c500e21a939ead57c0560af8a3a132bd7d56566f2ff2c4517ab683bf2d588727f09603cc3e932828dcefb98f6b221eef731cabec7538314441c1e0ff06b44c22085d41aae447c1000000010014ff3cb19986645fd7e71282ad9fea07d540a60e"
+
 func TestDecodeTransactionBug(t *testing.T) {
-	// Verbatim test vector from Rust test
-	input := []byte("808000000004001dc27eba0247f8cc9575e7d45e50a0bc7e72427d000000000000001d000000000000000000011dc72b6dfd9b36e414a2709e3b01eb5bbdd158f9bc77cd2ca6c3c8b0c803613e2189f6dacf709b34e8182e99d3a1af15812b75e59357d9c255c772695998665f010200000000076f2ff2c4517ab683bf2d588727f09603cc3e9328b9c500e21a939ead57c0560af8a3a132bd7d56566f2ff2c4517ab683bf2d588727f09603cc3e932828dcefb98f6b221eef731cabec7538314441c1e0ff06b44c22085d41aae447c1000000010014ff3cb19986645fd7e71282ad9fea07d540a60e")
+	input := []byte(tenureChangeTransactionHex)
 
 	// Decode the hex string
 	txBytes, err := transaction.DecodeHex(input)
@@ -87,21 +96,462 @@ func TestDecodeTransactionBug(t *testing.T) {
 		t.Errorf("Expected post condition mode 2, got %d", tx.PostConditionMode)
 	}
 
-	// Check anchor mode (should be 1 in the original data, but we treat it as AnchorModeAny=3)
-	if tx.AnchorMode != 3 {
-		t.Errorf("Expected adjusted anchor mode 3, got %d", tx.AnchorMode)
+	// Check anchor mode
+	if tx.AnchorMode != 1 {
+		t.Errorf("Expected anchor mode 1, got %d", tx.AnchorMode)
+	}
+
+	// Check post conditions
+	if len(tx.PostConditions) != 0 {
+		t.Errorf("Expected no post conditions, got %d", len(tx.PostConditions))
+	}
+	if hex.EncodeToString(tx.PostConditionsSerialized) != "0200000000" {
+		t.Errorf("Expected serialized post conditions 0200000000, got %x", tx.PostConditionsSerialized)
 	}
 
 	// Check payload type
-	// The original payload type is 7 (TenureChange), but our implementation transforms
-	// the value 131 to TokenTransfer (0) for the test vector
-	if tx.Payload.PayloadType != 0 {
-		t.Errorf("Expected adjusted payload type 0, got %d", tx.Payload.PayloadType)
+	if tx.Payload.PayloadType != transaction.TransactionPayloadIDTenureChange {
+		t.Errorf("Expected payload type 7, got %d", tx.Payload.PayloadType)
+	}
+
+	if tx.Payload.TenureChange == nil {
+		t.Fatalf("Expected tenure change payload to be set")
+	}
+	tenureChange := tx.Payload.TenureChange
+	if fmt.Sprintf("%x", tenureChange.TenureConsensusHash) != "6f2ff2c4517ab683bf2d588727f09603cc3e9328" {
+		t.Errorf("Unexpected tenure consensus hash %x", tenureChange.TenureConsensusHash)
+	}
+	if tenureChange.PreviousTenureBlocks != 1 {
+		t.Errorf("Expected 1 previous tenure block, got %d", tenureChange.PreviousTenureBlocks)
+	}
+	if tenureChange.Cause != transaction.TenureChangeCauseBlockFound {
+		t.Errorf("Expected cause %d, got %d", transaction.TenureChangeCauseBlockFound, tenureChange.Cause)
+	}
+	if fmt.Sprintf("%x", tenureChange.PubkeyHash) != "14ff3cb19986645fd7e71282ad9fea07d540a60e" {
+		t.Errorf("Unexpected pubkey hash %x", tenureChange.PubkeyHash)
+	}
+}
+
+func TestDecodeTransactionPostConditions(t *testing.T) {
+	// The tenure change vector with an STX and a fungible post condition spliced in
+	stxCondition := "00" + "02" + "1a" + strings.Repeat("11", 20) + "03" + "0000000000000064"
+	fungibleCondition := "01" + "01" + "1a" + strings.Repeat("22", 20) + "05746f6b656e" + "0474657374" + "01" + "00000000000003e8"
+	postConditions := "02" + "00000002" + stxCondition + fungibleCondition
+	input := strings.Replace(tenureChangeTransactionHex, "0200000000", postConditions, 1)
+
+	txBytes, err := hex.DecodeString(input)
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+	tx, err := transaction.DecodeTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+
+	if len(tx.PostConditions) != 2 {
+		t.Fatalf("Expected 2 post conditions, got %d", len(tx.PostConditions))
+	}
+	stx := tx.PostConditions[0]
+	if stx.Type != post_condition.AssetInfoSTX || stx.Principal.Type != post_condition.PrincipalStandard || stx.Amount != 100 {
+		t.Errorf("Unexpected STX post condition %+v", stx)
+	}
+	fungible := tx.PostConditions[1]
+	if fungible.Type != post_condition.AssetInfoFungible || fungible.Principal.Type != post_condition.PrincipalOrigin ||
+		fungible.Asset.ContractName != "token" || fungible.Asset.AssetName != "test" ||
+		fungible.ConditionCode != byte(post_condition.FCSentEq) || fungible.Amount != 1000 {
+		t.Errorf("Unexpected fungible post condition %+v", fungible)
+	}
+
+	// The raw bytes decode to the same post conditions without the rest of the transaction
+	if hex.EncodeToString(tx.PostConditionsSerialized) != postConditions {
+		t.Errorf("Expected serialized post conditions %s, got %x", postConditions, tx.PostConditionsSerialized)
+	}
+	decoded, err := post_condition.DecodeTxPostConditions(tx.PostConditionsSerialized)
+	if err != nil {
+		t.Fatalf("Failed to decode serialized post conditions: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.PostConditions, tx.PostConditions) {
+		t.Errorf("Expected %+v, got %+v", tx.PostConditions, decoded.PostConditions)
+	}
+
+	if tx.Payload.TenureChange == nil {
+		t.Errorf("Expected tenure change payload after the post conditions")
+	}
+
+	// A bad post condition is reported with its index
+	input = strings.Replace(tenureChangeTransactionHex, "0200000000", "02"+"00000001"+"00"+"09", 1)
+	txBytes, _ = hex.DecodeString(input)
+	_, err = transaction.DecodeTransaction(txBytes)
+	var deserializeErr *clarity_value.DeserializeError
+	if !errors.As(err, &deserializeErr) || deserializeErr.Path != "post_conditions[0].principal.type" {
+		t.Errorf("Expected error at post_conditions[0].principal.type, got %v", err)
+	}
+}
+
+func TestDecodeTransactionErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		offset int64
+		path   string
+	}{
+		{"Truncated chain ID", "8080", 1, "chain_id"},
+		{"Truncated spending condition", "808000000004", 6, "auth.spending_condition.hash_mode"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBytes, err := transaction.DecodeHex([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("Failed to decode hex: %v", err)
+			}
+
+			_, err = transaction.DecodeTransaction(txBytes)
+			if !errors.Is(err, transaction.ErrDeserialize) || !errors.Is(err, clarity_value.KindTruncated) {
+				t.Fatalf("Expected truncated error, got %v", err)
+			}
+			var deserializeErr *clarity_value.DeserializeError
+			if !errors.As(err, &deserializeErr) {
+				t.Fatalf("Expected DeserializeError, got %T", err)
+			}
+			if deserializeErr.Offset != tc.offset || deserializeErr.Path != tc.path {
+				t.Errorf("Expected %s at offset %d, got %v", tc.path, tc.offset, err)
+			}
+		})
+	}
+}
+
+// singlesigAuthHex is a standard P2PKH authorization with nonce 1 and fee 200
+const singlesigAuthHex = "04" + "00" + "3333333333333333333333333333333333333333" + "0000000000000001" + "00000000000000c8" + "00" +
+	"4444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444444"
+
+// transactionHex builds a mainnet transaction with the given auth and payload
+func transactionHex(auth, payload string) string {
+	return "00" + "00000001" + auth + "03" + "01" + "00000000" + payload
+}
+
+// contractCallTransactionHex builds a mainnet contract call to
+// SP000000000000000000002Q6VF78.market with the given serialized arguments
+func contractCallTransactionHex(args ...string) string {
+	payload := "02" + "16" + strings.Repeat("00", 20) + "066d61726b6574" + "03627579" + fmt.Sprintf("%08x", len(args)) + strings.Join(args, "")
+	return transactionHex(singlesigAuthHex, payload)
+}
+
+func TestDecodeContractCallArgs(t *testing.T) {
+	literals := []string{
+		"u100",
+		"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7.token",
+		`{owner: 'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7, meta: {tags: (list "a" "b"), expiry: (some u10)}}`,
+		"(list (ok 0x0102) (err -1))",
+		"'SP2J6ZY48GV1EZ5V2V5RB9MP66SW86PYKKNRV9EJ7",
+	}
+
+	var args []string
+	for _, literal := range literals {
+		value, err := clarity_value.ParseClarityLiteral(literal)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", literal, err)
+		}
+		serialized, err := clarity_value.SerializeClarityValue(value)
+		if err != nil {
+			t.Fatalf("Failed to serialize %s: %v", literal, err)
+		}
+		args = append(args, hex.EncodeToString(serialized))
+	}
+
+	txBytes, err := hex.DecodeString(contractCallTransactionHex(args...))
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+	tx, err := transaction.DecodeTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+
+	contractCall := tx.Payload.ContractCall
+	if contractCall == nil {
+		t.Fatalf("Expected contract call payload to be set")
+	}
+	if string(contractCall.ContractName) != "market" || string(contractCall.FunctionName) != "buy" {
+		t.Errorf("Expected market.buy, got %s.%s", contractCall.ContractName, contractCall.FunctionName)
+	}
+	if len(contractCall.FunctionArgs) != len(literals) {
+		t.Fatalf("Expected %d args, got %d", len(literals), len(contractCall.FunctionArgs))
+	}
+
+	for i, arg := range contractCall.FunctionArgs {
+		expected, _ := clarity_value.ParseClarityLiteral(literals[i])
+		if arg.Value.ReprString() != expected.ReprString() {
+			t.Errorf("Arg %d: expected %s, got %s", i, expected.ReprString(), arg.Value.ReprString())
+		}
+		if hex.EncodeToString(arg.SerializedBytes) != args[i] {
+			t.Errorf("Arg %d: expected serialized bytes %s, got %x", i, args[i], arg.SerializedBytes)
+		}
+	}
+}
+
+func TestDecodeContractCallArgsMainnet(t *testing.T) {
+	tx, err := decodeTransactionHex(arkadikoProposeTransactionHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	contractCall := tx.Payload.ContractCall
+	if contractCall == nil {
+		t.Fatalf("Expected contract call payload to be set")
 	}
 
-	// Since we're using TokenTransfer now, other payload fields will be different
-	// We'll just check that TokenTransfer is set
-	if tx.Payload.TokenTransfer == nil {
-		t.Fatalf("Expected token transfer payload to be set")
+	expected := []string{
+		"'SP2C2YFP12AJZB4MABJBAJ55XECVS7E4PMMZ89YZR.arkadiko-stake-pool-diko-v1-2",
+		"u61320",
+		"u1008",
+		`u"AIP10 Update LTVs and Liquidation Ratios"`,
+		`u"https://github.com/arkadiko-dao/arkadiko/pull/493"`,
+		"(list (tuple (address 'SP2C2YFP12AJZB4MABJBAJ55XECVS7E4PMMZ89YZR) (can-burn false) (can-mint false) " +
+			`(name "aip10-arkadiko-update-tvl-liquidation-ratio") ` +
+			"(qualified-name 'SP2C2YFP12AJZB4MABJBAJ55XECVS7E4PMMZ89YZR.aip10-arkadiko-update-tvl-liquidation-ratio)))",
+	}
+	if len(contractCall.FunctionArgs) != len(expected) {
+		t.Fatalf("Expected %d args, got %d", len(expected), len(contractCall.FunctionArgs))
+	}
+
+	// The args are the last bytes of the transaction
+	var args string
+	for i, arg := range contractCall.FunctionArgs {
+		if arg.Value.ReprString() != expected[i] {
+			t.Errorf("Arg %d: expected %s, got %s", i, expected[i], arg.Value.ReprString())
+		}
+		args += hex.EncodeToString(arg.SerializedBytes)
+	}
+	if !strings.HasSuffix(arkadikoProposeTransactionHex, args) {
+		t.Errorf("Expected the serialized args to end the transaction, got %s", args)
+	}
+
+	// Nested members keep their types and serialized bytes
+	list := contractCall.FunctionArgs[5].Value.(clarity_value.ListValue)
+	tuple := list[0].Value.(clarity_value.TupleValue)
+	qualifiedName, ok := tuple["qualified-name"].Value.(clarity_value.PrincipalContractValue)
+	if !ok || string(qualifiedName.Name) != "aip10-arkadiko-update-tvl-liquidation-ratio" {
+		t.Errorf("Expected a contract principal qualified-name, got %s", tuple["qualified-name"].Value.ReprString())
+	}
+	if !strings.Contains(arkadikoProposeTransactionHex, hex.EncodeToString(tuple["address"].SerializedBytes)) {
+		t.Errorf("Expected the serialized bytes of address to be part of the transaction")
+	}
+}
+
+func TestDecodeContractCallArgErrors(t *testing.T) {
+	// The owner field of the tuple holds an unknown type prefix
+	tuple := "0c00000001" + "056f776e6572" + "ff"
+	txBytes, err := hex.DecodeString(contractCallTransactionHex("0100000000000000000000000000000064", tuple))
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+
+	_, err = transaction.DecodeTransaction(txBytes)
+	if !errors.Is(err, transaction.ErrDeserialize) || !errors.Is(err, clarity_value.KindBadPrefix) {
+		t.Fatalf("Expected bad prefix error, got %v", err)
+	}
+	var deserializeErr *clarity_value.DeserializeError
+	if !errors.As(err, &deserializeErr) {
+		t.Fatalf("Expected DeserializeError, got %T", err)
+	}
+	if deserializeErr.Path != "payload.contract_call.args[1].owner" {
+		t.Errorf("Expected path payload.contract_call.args[1].owner, got %s", deserializeErr.Path)
+	}
+	// Header, auth, modes, post conditions, payload type, address, names, count, first arg, tuple header, field name
+	expectedOffset := int64(5 + 1 + 1 + 20 + 8 + 8 + 1 + 65 + 1 + 1 + 4 + 1 + 21 + 7 + 4 + 4 + 17 + 5 + 6)
+	if deserializeErr.Offset != expectedOffset {
+		t.Errorf("Expected offset %d, got %d", expectedOffset, deserializeErr.Offset)
+	}
+}
+
+func TestDecodeTransactionStrict(t *testing.T) {
+	// replaceAt overwrites the byte at offset in the tenure change vector
+	replaceAt := func(offset int, b string) string {
+		return tenureChangeTransactionHex[:offset*2] + b + tenureChangeTransactionHex[offset*2+2:]
+	}
+	multisigAuth := "04" + "01" + strings.Repeat("33", 20) + strings.Repeat("00", 16) + "00000001" + "04"
+	tokenTransfer := "00" + "bf" + strings.Repeat("00", 21)
+
+	testCases := []struct {
+		name    string
+		input   string
+		kind    clarity_value.DeserializeErrorKind
+		offset  int64
+		path    string
+		lenient bool // accepted by the lenient decoder
+	}{
+		{"Unknown version", replaceAt(0, "01"), clarity_value.KindInvalid, 0, "version", true},
+		{"Unknown auth type", replaceAt(5, "06"), clarity_value.KindBadPrefix, 5, "auth.auth_type", false},
+		{"Unknown hash mode", replaceAt(6, "09"), clarity_value.KindBadPrefix, 6, "auth.spending_condition.hash_mode", false},
+		{"Unknown key encoding", replaceAt(43, "02"), clarity_value.KindInvalid, 43, "auth.spending_condition.key_encoding", true},
+		{"Unknown anchor mode", replaceAt(109, "04"), clarity_value.KindInvalid, 109, "anchor_mode", true},
+		{"Unknown post condition mode", replaceAt(110, "03"), clarity_value.KindInvalid, 110, "post_condition_mode", true},
+		{"Unknown payload type", replaceAt(115, "83"), clarity_value.KindBadPrefix, 115, "payload.payload_type", false},
+		{"Unknown tenure change cause", replaceAt(212, "05"), clarity_value.KindInvalid, 212, "payload.tenure_change.cause", true},
+		{"Trailing bytes", tenureChangeTransactionHex + "00", clarity_value.KindTrailingBytes, 233, "", true},
+		{"Unknown auth field", transactionHex(multisigAuth, ""), clarity_value.KindBadPrefix, 47, "auth.spending_condition.fields[0].field_id", false},
+		{"Unknown principal type", transactionHex(singlesigAuthHex, tokenTransfer), clarity_value.KindBadPrefix, 116, "payload.token_transfer.recipient.type", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			txBytes, err := hex.DecodeString(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to decode hex: %v", err)
+			}
+
+			_, err = transaction.DecodeTransaction(txBytes)
+			if !errors.Is(err, transaction.ErrDeserialize) || !errors.Is(err, tc.kind) {
+				t.Fatalf("Expected %q error, got %v", tc.kind, err)
+			}
+			var deserializeErr *clarity_value.DeserializeError
+			if !errors.As(err, &deserializeErr) {
+				t.Fatalf("Expected DeserializeError, got %T", err)
+			}
+			if deserializeErr.Offset != tc.offset || deserializeErr.Path != tc.path {
+				t.Errorf("Expected %s at offset %d, got %v", tc.path, tc.offset, err)
+			}
+
+			_, err = transaction.DecodeTransactionWithOptions(txBytes, transaction.DecodeOptions{Lenient: true})
+			if tc.lenient && err != nil {
+				t.Errorf("Expected lenient decoding to succeed, got %v", err)
+			}
+			if !tc.lenient && !errors.Is(err, tc.kind) {
+				t.Errorf("Expected lenient decoding to fail with %q, got %v", tc.kind, err)
+			}
+		})
+	}
+}
+
+func TestDecodeTransactionNetwork(t *testing.T) {
+	txBytes, err := transaction.DecodeHex([]byte(tenureChangeTransactionHex))
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+
+	for _, n := range []network.Network{network.Testnet, network.Devnet} {
+		if _, err := transaction.DecodeTransactionWithOptions(txBytes, transaction.DecodeOptions{Network: &n}); err != nil {
+			t.Errorf("Expected decoding on %s to succeed, got %v", n, err)
+		}
+	}
+
+	subnet := network.NewSubnet("subnet", 0x55005500, network.Testnet)
+	for _, n := range []network.Network{network.Mainnet, subnet} {
+		_, err := transaction.DecodeTransactionWithOptions(txBytes, transaction.DecodeOptions{Network: &n})
+		if !errors.Is(err, network.ErrWrongNetwork) {
+			t.Errorf("Expected decoding on %s to fail with ErrWrongNetwork, got %v", n, err)
+		}
+	}
+}
+
+func TestCheckNetworkAddresses(t *testing.T) {
+	recipient := func(version byte) *transaction.StacksTransaction {
+		return &transaction.StacksTransaction{
+			Version: transaction.TransactionVersionTestnet,
+			ChainID: transaction.ChainIDTestnet,
+			Payload: transaction.TransactionPayload{
+				TokenTransfer: &transaction.TokenTransferPayload{
+					Recipient: transaction.PrincipalData{
+						Type:         transaction.PrincipalTypeStandard,
+						StandardData: &transaction.StandardPrincipalData{Version: version},
+					},
+				},
+			},
+		}
+	}
+
+	if err := recipient(26).CheckNetwork(network.Testnet); err != nil {
+		t.Errorf("Expected a testnet recipient to pass, got %v", err)
+	}
+	if err := recipient(22).CheckNetwork(network.Testnet); !errors.Is(err, network.ErrWrongNetwork) {
+		t.Errorf("Expected a mainnet recipient to fail with ErrWrongNetwork, got %v", err)
+	}
+}
+
+func TestDecodeTransactionPayloads(t *testing.T) {
+	principal := "05" + "16" + strings.Repeat("55", 20)
+	vrfProof := strings.Repeat("66", transaction.VRFProofLength)
+
+	t.Run("Versioned smart contract", func(t *testing.T) {
+		payload := "06" + "02" + "0568656c6c6f" + "00000004" + "28292029"
+		tx, err := decodeTransactionHex(transactionHex(singlesigAuthHex, payload))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		if tx.Payload.ClarityVersion == nil || *tx.Payload.ClarityVersion != transaction.ClarityVersion2 {
+			t.Errorf("Expected clarity version 2, got %v", tx.Payload.ClarityVersion)
+		}
+		if tx.Payload.SmartContract == nil || string(tx.Payload.SmartContract.Name) != "hello" || string(tx.Payload.SmartContract.CodeBody) != "() )" {
+			t.Errorf("Unexpected smart contract %+v", tx.Payload.SmartContract)
+		}
+	})
+
+	t.Run("Coinbase to alt recipient", func(t *testing.T) {
+		payload := "05" + strings.Repeat("77", 32) + principal
+		tx, err := decodeTransactionHex(transactionHex(singlesigAuthHex, payload))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		if tx.Payload.AltRecipient == nil || tx.Payload.AltRecipient.StandardData == nil || tx.Payload.AltRecipient.StandardData.Version != 0x16 {
+			t.Errorf("Unexpected alt recipient %+v", tx.Payload.AltRecipient)
+		}
+	})
+
+	t.Run("Nakamoto coinbase", func(t *testing.T) {
+		payload := "08" + strings.Repeat("77", 32) + "0a" + principal + fmt.Sprintf("%08x", transaction.VRFProofLength) + vrfProof
+		tx, err := decodeTransactionHex(transactionHex(singlesigAuthHex, payload))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		if tx.Payload.AltRecipient == nil || tx.Payload.AltRecipient.Type != transaction.PrincipalTypeStandard {
+			t.Errorf("Unexpected alt recipient %+v", tx.Payload.AltRecipient)
+		}
+		if tx.Payload.VRFProof == nil || hex.EncodeToString(*tx.Payload.VRFProof) != vrfProof {
+			t.Errorf("Unexpected VRF proof %v", tx.Payload.VRFProof)
+		}
+
+		payload = "08" + strings.Repeat("77", 32) + "09" + fmt.Sprintf("%08x", transaction.VRFProofLength) + vrfProof
+		tx, err = decodeTransactionHex(transactionHex(singlesigAuthHex, payload))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		if tx.Payload.AltRecipient != nil {
+			t.Errorf("Expected no alt recipient, got %+v", tx.Payload.AltRecipient)
+		}
+
+		payload = "08" + strings.Repeat("77", 32) + "09" + "00000001" + "66"
+		if _, err := decodeTransactionHex(transactionHex(singlesigAuthHex, payload)); !errors.Is(err, clarity_value.KindInvalid) {
+			t.Errorf("Expected short VRF proof to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("Multisig", func(t *testing.T) {
+		fields := "00000003" + "00" + strings.Repeat("02", 33) + "02" + strings.Repeat("88", 65) + "01" + strings.Repeat("03", 33)
+		auth := "04" + "01" + strings.Repeat("33", 20) + strings.Repeat("00", 16) + fields + "0002"
+		tx, err := decodeTransactionHex(transactionHex(auth, "04"+strings.Repeat("77", 32)))
+		if err != nil {
+			t.Fatalf("Failed to decode transaction: %v", err)
+		}
+		condition := tx.Auth.SpendingCondition
+		if len(condition.Fields) != 3 || condition.SignaturesRequired == nil || *condition.SignaturesRequired != 2 {
+			t.Fatalf("Unexpected multisig condition %+v", condition)
+		}
+		if condition.Fields[1].Signature == nil || *condition.Fields[2].PublicKeyEncoding != transaction.PublicKeyEncodingUncompressed {
+			t.Errorf("Unexpected auth fields %+v", condition.Fields)
+		}
+
+		// P2WSH only allows compressed keys
+		auth = "04" + "03" + auth[4:]
+		if _, err := decodeTransactionHex(transactionHex(auth, "04"+strings.Repeat("77", 32))); !errors.Is(err, clarity_value.KindInvalid) {
+			t.Errorf("Expected uncompressed P2WSH key to be rejected, got %v", err)
+		}
+	})
+}
+
+func decodeTransactionHex(input string) (*transaction.StacksTransaction, error) {
+	txBytes, err := hex.DecodeString(input)
+	if err != nil {
+		return nil, err
 	}
+	return transaction.DecodeTransaction(txBytes)
 }
@@ -0,0 +1,323 @@
+package transaction_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/janniks/stacks-go/lib/transaction"
+)
+
+func TestVerifyMainnetTransaction(t *testing.T) {
+	tx, err := decodeTransactionHex(arkadikoProposeTransactionHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	if err := tx.Verify(); err != nil {
+		t.Fatalf("Expected the mainnet transaction to verify, got %v", err)
+	}
+	if err := tx.VerifySponsor(); !errors.Is(err, transaction.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a standard transaction, got %v", err)
+	}
+
+	edits := map[string]func(tx *transaction.StacksTransaction){
+		"Fee":       func(tx *transaction.StacksTransaction) { tx.Auth.SpendingCondition.Fee++ },
+		"Nonce":     func(tx *transaction.StacksTransaction) { tx.Auth.SpendingCondition.Nonce++ },
+		"Signer":    func(tx *transaction.StacksTransaction) { tx.Auth.SpendingCondition.Signer[0] ^= 0x01 },
+		"Signature": func(tx *transaction.StacksTransaction) { tx.Auth.SpendingCondition.Signature[64] ^= 0x01 },
+		"Payload":   func(tx *transaction.StacksTransaction) { tx.Payload.ContractCall.FunctionName = []byte("vote") },
+		"Key encoding": func(tx *transaction.StacksTransaction) {
+			keyEncoding := transaction.PublicKeyEncodingUncompressed
+			tx.Auth.SpendingCondition.KeyEncoding = &keyEncoding
+		},
+	}
+
+	for name, edit := range edits {
+		t.Run(name, func(t *testing.T) {
+			tx, _ := decodeTransactionHex(arkadikoProposeTransactionHex)
+			edit(tx)
+			if err := tx.VerifyOrigin(); !errors.Is(err, transaction.ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySinglesig(t *testing.T) {
+	key := testPrivateKey(1)
+	compressed := key.PubKey().SerializeCompressed()
+	keyHash := testHash160(compressed)
+
+	testCases := map[string]struct {
+		hashMode    uint8
+		keyEncoding uint8
+		signer      [20]byte
+	}{
+		"P2PKH":              {transaction.SinglesigHashModeP2PKH, transaction.PublicKeyEncodingCompressed, keyHash},
+		"P2PKH uncompressed": {transaction.SinglesigHashModeP2PKH, transaction.PublicKeyEncodingUncompressed, testHash160(key.PubKey().SerializeUncompressed())},
+		"P2WPKH":             {transaction.SinglesigHashModeP2WPKH, transaction.PublicKeyEncodingCompressed, testHash160(append([]byte{0x00, 0x14}, keyHash[:]...))},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx := unsignedTestTransaction(t)
+			keyEncoding := tc.keyEncoding
+			tx.Auth.SpendingCondition = transaction.TransactionSpendingCondition{
+				HashMode:    tc.hashMode,
+				Signer:      tc.signer,
+				Nonce:       3,
+				Fee:         180,
+				KeyEncoding: &keyEncoding,
+			}
+
+			initial, err := tx.InitialSighash()
+			if err != nil {
+				t.Fatalf("Failed to compute initial sighash: %v", err)
+			}
+			signature := testSign(key, transaction.SighashPresign(initial, transaction.TransactionAuthFlagStandard, 180, 3))
+			tx.Auth.SpendingCondition.Signature = &signature
+
+			assertVerifiesAfterRoundTrip(t, tx)
+		})
+	}
+
+	t.Run("P2WPKH uncompressed", func(t *testing.T) {
+		tx := unsignedTestTransaction(t)
+		keyEncoding := transaction.PublicKeyEncodingUncompressed
+		tx.Auth.SpendingCondition = transaction.TransactionSpendingCondition{
+			HashMode:    transaction.SinglesigHashModeP2WPKH,
+			Signer:      testHash160(append([]byte{0x00, 0x14}, keyHash[:]...)),
+			KeyEncoding: &keyEncoding,
+		}
+		initial, _ := tx.InitialSighash()
+		signature := testSign(key, transaction.SighashPresign(initial, transaction.TransactionAuthFlagStandard, 0, 0))
+		tx.Auth.SpendingCondition.Signature = &signature
+
+		if err := tx.VerifyOrigin(); !errors.Is(err, transaction.ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature, got %v", err)
+		}
+	})
+}
+
+func TestVerifyMultisig(t *testing.T) {
+	keys := []*secp256k1.PrivateKey{testPrivateKey(1), testPrivateKey(2), testPrivateKey(3)}
+	script := []byte{0x52}
+	for _, key := range keys {
+		script = append(script, 33)
+		script = append(script, key.PubKey().SerializeCompressed()...)
+	}
+	script = append(script, 0x53, 0xae)
+	scriptHash := sha256.Sum256(script)
+	p2shSigner := testHash160(script)
+	p2wshSigner := testHash160(append([]byte{0x00, 0x20}, scriptHash[:]...))
+
+	testCases := map[string]struct {
+		hashMode uint8
+		signer   [20]byte
+		signing  []bool
+		valid    bool
+	}{
+		"P2SH":                        {transaction.MultisigHashModeP2SH, p2shSigner, []bool{true, false, true}, true},
+		"P2WSH":                       {transaction.MultisigHashModeP2WSH, p2wshSigner, []bool{false, true, true}, true},
+		"P2SH non-sequential":         {transaction.MultisigHashModeP2SHNonSequential, p2shSigner, []bool{true, true, false}, true},
+		"P2WSH non-sequential":        {transaction.MultisigHashModeP2WSHNonSequential, p2wshSigner, []bool{true, false, true}, true},
+		"Non-sequential extra signer": {transaction.MultisigHashModeP2SHNonSequential, p2shSigner, []bool{true, true, true}, true},
+		"Sequential extra signer":     {transaction.MultisigHashModeP2SH, p2shSigner, []bool{true, true, true}, false},
+		"Too few signatures":          {transaction.MultisigHashModeP2SH, p2shSigner, []bool{true, false, false}, false},
+		"Wrong signer":                {transaction.MultisigHashModeP2SH, p2wshSigner, []bool{true, false, true}, false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx := unsignedTestTransaction(t)
+			required := uint16(2)
+			tx.Auth.SpendingCondition = transaction.TransactionSpendingCondition{
+				ConditionType:      0x01,
+				HashMode:           tc.hashMode,
+				Signer:             tc.signer,
+				Nonce:              12,
+				Fee:                900,
+				SignaturesRequired: &required,
+			}
+
+			initial, err := tx.InitialSighash()
+			if err != nil {
+				t.Fatalf("Failed to compute initial sighash: %v", err)
+			}
+			testSignMultisig(&tx.Auth.SpendingCondition, initial, transaction.TransactionAuthFlagStandard, keys, tc.signing)
+
+			err = tx.VerifyOrigin()
+			if tc.valid && err != nil {
+				t.Errorf("Expected the transaction to verify, got %v", err)
+			}
+			if !tc.valid && !errors.Is(err, transaction.ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+			if tc.valid {
+				assertVerifiesAfterRoundTrip(t, tx)
+			}
+		})
+	}
+
+	t.Run("Sequential signatures out of order", func(t *testing.T) {
+		tx := unsignedTestTransaction(t)
+		required := uint16(2)
+		tx.Auth.SpendingCondition = transaction.TransactionSpendingCondition{
+			ConditionType:      0x01,
+			HashMode:           transaction.MultisigHashModeP2SH,
+			Signer:             p2shSigner,
+			SignaturesRequired: &required,
+		}
+		initial, _ := tx.InitialSighash()
+		testSignMultisig(&tx.Auth.SpendingCondition, initial, transaction.TransactionAuthFlagStandard, keys, []bool{true, true, false})
+
+		fields := tx.Auth.SpendingCondition.Fields
+		fields[0].Signature, fields[1].Signature = fields[1].Signature, fields[0].Signature
+		if err := tx.VerifyOrigin(); !errors.Is(err, transaction.ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature, got %v", err)
+		}
+	})
+}
+
+func TestVerifySponsored(t *testing.T) {
+	originKey := testPrivateKey(1)
+	sponsorKeys := []*secp256k1.PrivateKey{testPrivateKey(2), testPrivateKey(3)}
+
+	script := []byte{0x51}
+	for _, key := range sponsorKeys {
+		script = append(script, 33)
+		script = append(script, key.PubKey().SerializeCompressed()...)
+	}
+	script = append(script, 0x52, 0xae)
+
+	tx := unsignedTestTransaction(t)
+	keyEncoding := transaction.PublicKeyEncodingCompressed
+	required := uint16(1)
+	tx.Auth = transaction.TransactionAuth{
+		AuthType: transaction.TransactionAuthFlagSponsored,
+		SpendingCondition: transaction.TransactionSpendingCondition{
+			HashMode:    transaction.SinglesigHashModeP2PKH,
+			Signer:      testHash160(originKey.PubKey().SerializeCompressed()),
+			Nonce:       4,
+			KeyEncoding: &keyEncoding,
+		},
+		SponsorSpendingCondition: &transaction.TransactionSpendingCondition{
+			ConditionType:      0x01,
+			HashMode:           transaction.MultisigHashModeP2SH,
+			Signer:             testHash160(script),
+			Nonce:              30,
+			Fee:                2000,
+			SignaturesRequired: &required,
+		},
+	}
+
+	// The origin signs first, without knowing the sponsor
+	initial, err := tx.InitialSighash()
+	if err != nil {
+		t.Fatalf("Failed to compute initial sighash: %v", err)
+	}
+	presign := transaction.SighashPresign(initial, transaction.TransactionAuthFlagStandard, 0, 4)
+	signature := testSign(originKey, presign)
+	tx.Auth.SpendingCondition.Signature = &signature
+
+	originSighash := transaction.SighashPostsign(presign, keyEncoding, signature)
+	testSignMultisig(tx.Auth.SponsorSpendingCondition, originSighash, transaction.TransactionAuthFlagSponsored, sponsorKeys, []bool{false, true})
+
+	assertVerifiesAfterRoundTrip(t, tx)
+
+	// The sponsor signature commits to the origin's
+	tx.Auth.SpendingCondition.Signature = &[65]byte{}
+	if err := tx.VerifySponsor(); !errors.Is(err, transaction.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+// unsignedTestTransaction returns a mainnet coinbase to be given an auth
+func unsignedTestTransaction(t *testing.T) *transaction.StacksTransaction {
+	t.Helper()
+	tx, err := decodeTransactionHex(transactionHex(singlesigAuthHex, "04"+strings.Repeat("77", 32)))
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	return tx
+}
+
+// assertVerifiesAfterRoundTrip checks that the transaction verifies, before and after serialization
+func assertVerifiesAfterRoundTrip(t *testing.T, tx *transaction.StacksTransaction) {
+	t.Helper()
+	if err := tx.Verify(); err != nil {
+		t.Fatalf("Expected the transaction to verify, got %v", err)
+	}
+
+	serialized, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	decoded, err := transaction.DecodeTransaction(serialized)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	if err := decoded.Verify(); err != nil {
+		t.Errorf("Expected the decoded transaction to verify, got %v", err)
+	}
+}
+
+// testSignMultisig appends a compressed public key field for each key, or a
+// signature field for keys marked as signing, following the sighash chain
+func testSignMultisig(condition *transaction.TransactionSpendingCondition, curSighash [32]byte, authFlag uint8, keys []*secp256k1.PrivateKey, signing []bool) {
+	sequential := condition.HashMode == transaction.MultisigHashModeP2SH || condition.HashMode == transaction.MultisigHashModeP2WSH
+	presign := transaction.SighashPresign(curSighash, authFlag, condition.Fee, condition.Nonce)
+	for i, key := range keys {
+		if !signing[i] {
+			var publicKey [33]byte
+			copy(publicKey[:], key.PubKey().SerializeCompressed())
+			condition.Fields = append(condition.Fields, transaction.TransactionAuthField{
+				FieldID:   transaction.AuthFieldIDPublicKeyCompressed,
+				PublicKey: &publicKey,
+			})
+			continue
+		}
+
+		signature := testSign(key, presign)
+		condition.Fields = append(condition.Fields, transaction.TransactionAuthField{
+			FieldID:   transaction.AuthFieldIDSignatureCompressed,
+			Signature: &signature,
+		})
+		if sequential {
+			curSighash = transaction.SighashPostsign(presign, transaction.PublicKeyEncodingCompressed, signature)
+			presign = transaction.SighashPresign(curSighash, authFlag, condition.Fee, condition.Nonce)
+		}
+	}
+}
+
+// testPrivateKey returns a private key with every byte set to seed
+func testPrivateKey(seed byte) *secp256k1.PrivateKey {
+	var keyBytes [32]byte
+	for i := range keyBytes {
+		keyBytes[i] = seed
+	}
+	return secp256k1.PrivKeyFromBytes(keyBytes[:])
+}
+
+// testSign returns a recoverable signature (recovery ID, r, s) over sighash
+func testSign(key *secp256k1.PrivateKey, sighash [32]byte) [65]byte {
+	compact := ecdsa.SignCompact(key, sighash[:], false)
+	var signature [65]byte
+	signature[0] = compact[0] - 27
+	copy(signature[1:], compact[1:])
+	return signature
+}
+
+func testHash160(data []byte) [20]byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	var hash [20]byte
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
@@ -0,0 +1,100 @@
+package wallet_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/wallet"
+)
+
+// Test vector 1 from BIP32
+func TestDeriveVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := wallet.NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("NewMasterKey() failed: %v", err)
+	}
+
+	testCases := []struct {
+		path       string
+		privateKey string
+		chainCode  string
+		publicKey  string
+	}{
+		{
+			"m",
+			"e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			"873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			"0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+		},
+		{
+			"m/0'/1/2'/2/1000000000",
+			"471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+			"c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+			"022a471424da5e657499d1ff51cb43c47481a03b1e77f951fe64cec9f5a48f7011",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			key, err := master.Derive(tc.path)
+			if err != nil {
+				t.Fatalf("Derive(%s) failed: %v", tc.path, err)
+			}
+			if hex.EncodeToString(key.PrivateKey()) != tc.privateKey {
+				t.Errorf("PrivateKey() = %x, want %s", key.PrivateKey(), tc.privateKey)
+			}
+			if hex.EncodeToString(key.ChainCode[:]) != tc.chainCode {
+				t.Errorf("ChainCode = %x, want %s", key.ChainCode, tc.chainCode)
+			}
+			if hex.EncodeToString(key.PublicKey()) != tc.publicKey {
+				t.Errorf("PublicKey() = %x, want %s", key.PublicKey(), tc.publicKey)
+			}
+		})
+	}
+
+	// Deriving step by step gives the same key
+	key := master
+	for _, index := range []uint32{wallet.HardenedOffset, 1, wallet.HardenedOffset + 2, 2, 1000000000} {
+		if key, err = key.Child(index); err != nil {
+			t.Fatalf("Child(%d) failed: %v", index, err)
+		}
+	}
+	if hex.EncodeToString(key.PrivateKey()) != testCases[1].privateKey || key.Depth != 5 || key.Index != 1000000000 {
+		t.Errorf("Child() = %x at depth %d and index %d, want %s at depth 5 and index 1000000000", key.PrivateKey(), key.Depth, key.Index, testCases[1].privateKey)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	testCases := map[string][]uint32{
+		"m":                {},
+		"m/44'/5757'/0'/0": {wallet.HardenedOffset + 44, wallet.HardenedOffset + 5757, wallet.HardenedOffset, 0},
+		"0h/1":             {wallet.HardenedOffset, 1},
+		"2147483647'":      {wallet.HardenedOffset + 2147483647},
+	}
+	for path, expected := range testCases {
+		indexes, err := wallet.ParsePath(path)
+		if err != nil {
+			t.Fatalf("ParsePath(%s) failed: %v", path, err)
+		}
+		if !reflect.DeepEqual(indexes, expected) {
+			t.Errorf("ParsePath(%s) = %v, want %v", path, indexes, expected)
+		}
+	}
+
+	for _, path := range []string{"", "m/", "m/a", "m/-1", "m/1''", "m/2147483648", "m/0/m"} {
+		if _, err := wallet.ParsePath(path); !errors.Is(err, wallet.ErrDerivation) {
+			t.Errorf("ParsePath(%q) expected ErrDerivation, got %v", path, err)
+		}
+	}
+}
+
+func TestMasterKeySeedLength(t *testing.T) {
+	for _, length := range []int{0, 15, 65} {
+		if _, err := wallet.NewMasterKey(make([]byte, length)); !errors.Is(err, wallet.ErrDerivation) {
+			t.Errorf("NewMasterKey() expected ErrDerivation for a %d-byte seed, got %v", length, err)
+		}
+	}
+}
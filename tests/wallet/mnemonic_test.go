@@ -0,0 +1,139 @@
+package wallet_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/wallet"
+)
+
+// Test vectors from the BIP39 reference implementation, with the passphrase "TREZOR"
+var mnemonicVectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+		"0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+	},
+	{
+		"8080808080808080808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+		"c0c519bd0e91a2ed54357d9d1ebef6f5af218a153624cf4f2da911a0ed8f7a09e2ef61af0aca007096df430022f7a2b6fb91661a9589097069720d015e4e982f",
+	},
+	{
+		"77c2b00716cec7213839159e404db50d",
+		"jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		"b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+	},
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, tc := range mnemonicVectors {
+		t.Run(tc.entropy, func(t *testing.T) {
+			entropy, _ := hex.DecodeString(tc.entropy)
+			mnemonic, err := wallet.EntropyToMnemonic(entropy)
+			if err != nil {
+				t.Fatalf("EntropyToMnemonic() failed: %v", err)
+			}
+			if mnemonic != tc.mnemonic {
+				t.Errorf("EntropyToMnemonic() = %q, want %q", mnemonic, tc.mnemonic)
+			}
+
+			decoded, err := wallet.MnemonicToEntropy(tc.mnemonic)
+			if err != nil {
+				t.Fatalf("MnemonicToEntropy() failed: %v", err)
+			}
+			if hex.EncodeToString(decoded) != tc.entropy {
+				t.Errorf("MnemonicToEntropy() = %x, want %s", decoded, tc.entropy)
+			}
+
+			seed, err := wallet.MnemonicToSeed(tc.mnemonic, "TREZOR")
+			if err != nil {
+				t.Fatalf("MnemonicToSeed() failed: %v", err)
+			}
+			if hex.EncodeToString(seed) != tc.seed {
+				t.Errorf("MnemonicToSeed() = %x, want %s", seed, tc.seed)
+			}
+		})
+	}
+}
+
+func TestMnemonicWhitespace(t *testing.T) {
+	// Extra whitespace between words does not change the seed
+	tc := mnemonicVectors[0]
+	seed, err := wallet.MnemonicToSeed("  "+strings.ReplaceAll(tc.mnemonic, " ", " \t ")+"\n", "TREZOR")
+	if err != nil {
+		t.Fatalf("MnemonicToSeed() failed: %v", err)
+	}
+	if hex.EncodeToString(seed) != tc.seed {
+		t.Errorf("MnemonicToSeed() = %x, want %s", seed, tc.seed)
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for bits, words := range map[int]int{128: 12, 160: 15, 192: 18, 224: 21, 256: 24} {
+		mnemonic, err := wallet.NewMnemonic(bits)
+		if err != nil {
+			t.Fatalf("NewMnemonic(%d) failed: %v", bits, err)
+		}
+		if len(strings.Fields(mnemonic)) != words {
+			t.Errorf("NewMnemonic(%d) has %d words, want %d", bits, len(strings.Fields(mnemonic)), words)
+		}
+		if err := wallet.ValidateMnemonic(mnemonic); err != nil {
+			t.Errorf("ValidateMnemonic(NewMnemonic(%d)) failed: %v", bits, err)
+		}
+	}
+
+	for _, bits := range []int{0, 96, 129, 288} {
+		if _, err := wallet.NewMnemonic(bits); !errors.Is(err, wallet.ErrInvalidMnemonic) {
+			t.Errorf("NewMnemonic(%d) expected ErrInvalidMnemonic, got %v", bits, err)
+		}
+	}
+}
+
+func TestInvalidMnemonics(t *testing.T) {
+	testCases := map[string]string{
+		"Empty":          "",
+		"Bad checksum":   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"Unknown word":   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon stacks",
+		"Uppercase word": "Abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"Eleven words":   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"Thirteen words": "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"Too many words": strings.Repeat("zoo ", 27) + "zoo",
+	}
+
+	for name, mnemonic := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := wallet.ValidateMnemonic(mnemonic); !errors.Is(err, wallet.ErrInvalidMnemonic) {
+				t.Errorf("ValidateMnemonic() expected ErrInvalidMnemonic, got %v", err)
+			}
+			if _, err := wallet.MnemonicToSeed(mnemonic, ""); !errors.Is(err, wallet.ErrInvalidMnemonic) {
+				t.Errorf("MnemonicToSeed() expected ErrInvalidMnemonic, got %v", err)
+			}
+		})
+	}
+
+	if _, err := wallet.EntropyToMnemonic(make([]byte, 17)); !errors.Is(err, wallet.ErrInvalidMnemonic) {
+		t.Errorf("EntropyToMnemonic() expected ErrInvalidMnemonic for 17 bytes, got %v", err)
+	}
+}
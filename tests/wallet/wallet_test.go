@@ -0,0 +1,196 @@
+package wallet_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janniks/stacks-go/lib/address"
+	"github.com/janniks/stacks-go/lib/network"
+	"github.com/janniks/stacks-go/lib/transaction"
+	"github.com/janniks/stacks-go/lib/wallet"
+)
+
+// Secret key of the stacks.js wallet-sdk tests
+const stacksJSMnemonic = "sound idle panel often situate develop unit text design antenna vendor screen opinion balcony share trigger accuse scatter visa uniform brass update opinion media"
+
+func TestStacksJSAccount(t *testing.T) {
+	w, err := wallet.New(stacksJSMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	account, err := w.Account(0)
+	if err != nil {
+		t.Fatalf("Account(0) failed: %v", err)
+	}
+
+	if account.PrivateKeyHex() != "8721c6a5237f5e8d361161a7855aa56885a3e19e2ea6ee268fb14eabc5e2ed9001" {
+		t.Errorf("PrivateKeyHex() = %s, want 8721c6a5237f5e8d361161a7855aa56885a3e19e2ea6ee268fb14eabc5e2ed9001", account.PrivateKeyHex())
+	}
+	if addr := account.Address(network.Mainnet).String(); addr != "SP384CVPNDTYA0E92TKJZQTYXQHNZSWGCAG7SAPVB" {
+		t.Errorf("Address(mainnet) = %s, want SP384CVPNDTYA0E92TKJZQTYXQHNZSWGCAG7SAPVB", addr)
+	}
+	if addr := account.Address(network.Testnet).String(); addr != "ST384CVPNDTYA0E92TKJZQTYXQHNZSWGCAH0ER64E" {
+		t.Errorf("Address(testnet) = %s, want ST384CVPNDTYA0E92TKJZQTYXQHNZSWGCAH0ER64E", addr)
+	}
+
+	// The public key is the one the signer derives from the private key
+	publicKey, err := transaction.PrivateKeyToPublicKey(account.PrivateKey())
+	if err != nil {
+		t.Fatalf("PrivateKeyToPublicKey() failed: %v", err)
+	}
+	if string(publicKey) != string(account.PublicKey()) || len(publicKey) != 33 {
+		t.Errorf("PublicKey() = %x, want the compressed key %x", account.PublicKey(), publicKey)
+	}
+}
+
+func TestWIF(t *testing.T) {
+	w, err := wallet.New(stacksJSMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	account, err := w.Account(0)
+	if err != nil {
+		t.Fatalf("Account(0) failed: %v", err)
+	}
+
+	testCases := []struct {
+		network network.Network
+		prefix  string
+		version byte
+	}{
+		{network.Mainnet, "L", wallet.WIFVersionMainnet},
+		{network.Testnet, "c", wallet.WIFVersionTestnet},
+	}
+	for _, tc := range testCases {
+		wif := account.WIF(tc.network)
+		decoded, err := address.DecodeBase58Check(wif)
+		if err != nil {
+			t.Fatalf("DecodeBase58Check(%s) failed: %v", wif, err)
+		}
+		if wif[:1] != tc.prefix || decoded[0] != tc.version || string(decoded[1:]) != string(account.PrivateKey()) {
+			t.Errorf("WIF(%s) = %s, want a %s-prefixed encoding of version %#x and the private key", tc.network, wif, tc.prefix, tc.version)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	plain, err := wallet.New(stacksJSMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	salted, err := wallet.New(stacksJSMnemonic, "passphrase")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	a, _ := plain.Account(0)
+	b, _ := salted.Account(0)
+	if a.Address(network.Mainnet) == b.Address(network.Mainnet) {
+		t.Errorf("Expected the passphrase to change the account address")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	w, err := wallet.New(stacksJSMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	accounts, err := w.Accounts(2, 3)
+	if err != nil {
+		t.Fatalf("Accounts() failed: %v", err)
+	}
+	if len(accounts) != 3 {
+		t.Fatalf("Accounts() returned %d accounts, want 3", len(accounts))
+	}
+
+	seen := map[address.StacksAddress]bool{}
+	for i, account := range accounts {
+		if account.Index != uint32(2+i) {
+			t.Errorf("Accounts()[%d].Index = %d, want %d", i, account.Index, 2+i)
+		}
+		single, err := w.Account(account.Index)
+		if err != nil {
+			t.Fatalf("Account(%d) failed: %v", account.Index, err)
+		}
+		if single.PrivateKeyHex() != account.PrivateKeyHex() {
+			t.Errorf("Accounts()[%d] differs from Account(%d)", i, account.Index)
+		}
+		seen[account.Address(network.Mainnet)] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected 3 distinct addresses, got %d", len(seen))
+	}
+
+	if _, err := w.Account(wallet.HardenedOffset); !errors.Is(err, wallet.ErrDerivation) {
+		t.Errorf("Account(HardenedOffset) expected ErrDerivation, got %v", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	w, err := wallet.New(stacksJSMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	accounts, err := w.Accounts(0, 10)
+	if err != nil {
+		t.Fatalf("Accounts() failed: %v", err)
+	}
+	usedAt := func(indexes ...int) map[address.StacksAddress]bool {
+		used := map[address.StacksAddress]bool{}
+		for _, i := range indexes {
+			used[accounts[i].Address(network.Testnet)] = true
+		}
+		return used
+	}
+
+	testCases := []struct {
+		name     string
+		used     map[address.StacksAddress]bool
+		gapLimit int
+		expected int
+		checked  int
+	}{
+		{"None used", usedAt(), 3, 0, 3},
+		{"First used", usedAt(0), 3, 1, 4},
+		{"Gap within the limit", usedAt(0, 3), 3, 4, 7},
+		{"Gap at the limit", usedAt(0, 4), 3, 1, 4},
+		{"Gap limit of one", usedAt(0, 1, 2), 1, 3, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checked := 0
+			result, err := w.Scan(network.Testnet, tc.gapLimit, func(addr address.StacksAddress) (bool, error) {
+				checked++
+				return tc.used[addr], nil
+			})
+			if err != nil {
+				t.Fatalf("Scan() failed: %v", err)
+			}
+			if len(result) != tc.expected || checked != tc.checked {
+				t.Errorf("Scan() returned %d accounts after %d checks, want %d after %d", len(result), checked, tc.expected, tc.checked)
+			}
+			for i, account := range result {
+				if account.Index != uint32(i) {
+					t.Errorf("Scan()[%d].Index = %d", i, account.Index)
+				}
+			}
+		})
+	}
+
+	errLookup := errors.New("lookup failed")
+	if _, err := w.Scan(network.Testnet, 3, func(address.StacksAddress) (bool, error) { return false, errLookup }); !errors.Is(err, errLookup) {
+		t.Errorf("Scan() expected the callback error, got %v", err)
+	}
+	if _, err := w.Scan(network.Testnet, 0, func(address.StacksAddress) (bool, error) { return false, nil }); err == nil {
+		t.Errorf("Scan() expected error for a gap limit of 0, got nil")
+	}
+}
+
+func TestInvalidWallet(t *testing.T) {
+	if _, err := wallet.New("abandon abandon abandon", ""); !errors.Is(err, wallet.ErrInvalidMnemonic) {
+		t.Errorf("New() expected ErrInvalidMnemonic, got %v", err)
+	}
+	if _, err := wallet.FromSeed(make([]byte, 8)); !errors.Is(err, wallet.ErrDerivation) {
+		t.Errorf("FromSeed() expected ErrDerivation, got %v", err)
+	}
+}